@@ -1,59 +1,74 @@
 package main
 
 import (
-	"intern-bcc/internal/handler/rest"
-	"intern-bcc/internal/repository"
-	"intern-bcc/internal/usecase"
-	"intern-bcc/pkg/gomail"
-	"intern-bcc/pkg/infrastucture"
+	"context"
+	"fmt"
+	"intern-bcc/internal/app"
+	"intern-bcc/pkg/config"
 	"intern-bcc/pkg/infrastucture/database"
-	"intern-bcc/pkg/jwt"
-	"intern-bcc/pkg/logging"
-	"intern-bcc/pkg/middleware"
-	"intern-bcc/pkg/midtrans"
-	"intern-bcc/pkg/redis"
-	"intern-bcc/pkg/supabase"
 	"log"
 	"os"
-
-	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 )
 
 func main() {
-	err := godotenv.Load()
-	env := os.Getenv("ENV")
-	if err != nil && env == "" {
-		log.Fatal("error loading .env file")
+	if len(os.Args) > 1 {
+		err := runCommand(os.Args[1], os.Args[2:])
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
-	redis.ConnectToRedis()
-	database.ConnectToDB()
-	database.Migrate()
 
-	jwt := jwt.JwtInit()
-	goMail := gomail.GoMailInit()
-	midTrans := midtrans.MidTransInit()
-	supabase := supabase.SupabaseInit()
-	redis := redis.RedisInit(redis.RDB)
-	logging := 	logging.LoggingInit()
+	cfg, err := config.Load(config.CommandServe)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	repository := repository.NewRepository(database.DB, repository.RepositoryParam{Redis: redis})
+	log.Printf("loaded config:\n%v", cfg)
 
-	usecase := usecase.NewUsecase(usecase.UsecaseParam{
-		Repository: repository,
-		Jwt:        jwt,
-		Supabase:   supabase,
-		Midtrans:   midTrans,
-		GoMail:     goMail,
-		Redis:      redis,
-	})
+	application, err := app.NewApp(cfg, app.Dependencies{})
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	middleware := middleware.MiddlerwareInit(jwt, usecase, logging)
-	infrastucture.SeedData(database.DB)
+	migrator, err := database.MigratorInit(application.DB)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	rest := rest.NewRest(gin.New(), usecase, middleware)
+	if cfg.Database.MigrateOnStart {
+		_, err = migrator.Up(context.Background())
+		if err != nil {
+			log.Fatal("failed to migrate database: ", err)
+		}
+	}
+
+	err = migrator.Check(context.Background())
+	if err != nil {
+		log.Fatal(err, "\nrun `migrate up` to apply pending migrations")
+	}
 
-	rest.MountEndpoint()
+	err = application.Run()
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func runCommand(command string, args []string) error {
+	var run func(cfg config.Config, args []string) error
+	switch command {
+	case "migrate":
+		run = runMigrate
+	case "seed":
+		run = runSeed
+	default:
+		return fmt.Errorf("unknown command %q, expected migrate or seed", command)
+	}
+
+	cfg, err := config.Load(config.Command(command))
+	if err != nil {
+		return err
+	}
 
-	rest.Run()
+	return run(cfg, args)
 }
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"intern-bcc/pkg/config"
+	"intern-bcc/pkg/infrastucture"
+	"intern-bcc/pkg/infrastucture/database"
+	"strings"
+)
+
+const seedUsage = "usage: app seed [production|development]"
+
+func runSeed(cfg config.Config, args []string) error {
+	if len(args) > 1 {
+		return errors.New(seedUsage)
+	}
+	if len(args) == 1 {
+		cfg.Seed.Profile = args[0]
+	}
+
+	db, err := database.ConnectToDB(cfg.Database)
+	if err != nil {
+		return err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	defer sqlDB.Close()
+
+	migrator, err := database.MigratorInit(db)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+
+	err = migrator.Check(ctx)
+	if err != nil {
+		return fmt.Errorf("%w\nrun `migrate up` before seeding", err)
+	}
+
+	seeded, err := infrastucture.Seed(ctx, db, cfg.Seed)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("seeded %v profile: %v\n", cfg.Seed.Profile, strings.Join(seeded, ", "))
+
+	return nil
+}
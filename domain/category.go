@@ -1,8 +1,47 @@
 package domain
 
+const (
+	CategoryKindProduct     = "product"
+	CategoryKindArticle     = "article"
+	CategoryKindWebinar     = "webinar"
+	CategoryKindCompetition = "competition"
+)
+
 type Categories struct {
 	Id          int           `json:"-"`
 	Category    string        `json:"category" gorm:"unique" binding:"required"`
+	Kind        string        `json:"kind" gorm:"type:varchar(20);index"`
+	ParentId    *int          `json:"-"`
+	Parent      *Categories   `json:"-" gorm:"foreignKey:parent_id;references:id"`
 	Information []Information `json:"-" gorm:"foreignKey:category_id;references:id"`
 	Product     []Products    `json:"-" gorm:"foreignKey:category_id;references:id"`
 }
+
+func (c Categories) IsInformation() bool {
+	return c.Kind == CategoryKindArticle || c.Kind == CategoryKindWebinar || c.Kind == CategoryKindCompetition
+}
+
+type CategoryRequest struct {
+	Category string `json:"category" binding:"required"`
+	Kind     string `json:"kind" binding:"required,oneof=product article webinar competition"`
+	ParentId *int   `json:"parent_id"`
+}
+
+type CategoryUpdate struct {
+	Category     string `json:"category"`
+	ParentId     *int   `json:"parent_id"`
+	RemoveParent bool   `json:"remove_parent"`
+}
+
+type CategoryParam struct {
+	Id       int    `json:"-"`
+	Category string `json:"-"`
+	Kind     string `json:"kind" form:"kind"`
+}
+
+type CategoryResponse struct {
+	Id       int    `json:"id"`
+	Category string `json:"category"`
+	Kind     string `json:"kind"`
+	ParentId *int   `json:"parent_id"`
+}
@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"slices"
+	"time"
+)
+
+// ImageOwnerUpload owns the references of uploads that are not saved to a
+// record yet.
+const ImageOwnerUpload = "uploads"
+
+type ImageRenditions struct {
+	Medium    string `json:"medium"`
+	Thumbnail string `json:"thumbnail"`
+}
+
+// Or fills renditions that are missing, as for images uploaded before they
+// were generated, with the original image.
+func (r ImageRenditions) Or(original string) ImageRenditions {
+	if r.Medium == "" {
+		r.Medium = original
+	}
+	if r.Thumbnail == "" {
+		r.Thumbnail = original
+	}
+
+	return r
+}
+
+type UploadedImage struct {
+	Original string
+	Sizes    ImageRenditions
+}
+
+// Links lists every stored file of the image. Renditions of a small image
+// have the same content as the original, so they share its link.
+func (i UploadedImage) Links() []string {
+	var links []string
+	for _, link := range []string{i.Original, i.Sizes.Medium, i.Sizes.Thumbnail} {
+		if link != "" && !slices.Contains(links, link) {
+			links = append(links, link)
+		}
+	}
+
+	return links
+}
+
+// ImageReferences records that a record, or an upload in progress, uses a
+// stored file. Database triggers keep the rows of records up to date.
+type ImageReferences struct {
+	Id        uint64    `json:"-"`
+	Link      string    `json:"-" gorm:"type:varchar(768);index"`
+	Owner     string    `json:"-" gorm:"type:varchar(32);index:idx_image_references_owner"`
+	OwnerId   string    `json:"-" gorm:"type:varchar(36);index:idx_image_references_owner"`
+	CreatedAt time.Time `json:"-"`
+}
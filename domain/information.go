@@ -1,17 +1,23 @@
 package domain
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Information struct {
-	Id               int        `json:"id"`
-	Title            string     `json:"title"`
-	CategoryId       int        `json:"-"`
-	Synopsis         string     `json:"synopsis"`
-	Content          string     `json:"content"`
-	InformationPhoto string     `json:"information_photo"`
-	CreatedAt        time.Time  `json:"-"`
-	UpdatedAt        time.Time  `json:"-"`
-	Category         Categories `json:"category"`
+	Id                    int             `json:"id"`
+	Title                 string          `json:"title"`
+	CategoryId            int             `json:"-"`
+	Synopsis              string          `json:"synopsis"`
+	Content               string          `json:"content"`
+	InformationPhoto      string          `json:"information_photo"`
+	InformationPhotoSizes ImageRenditions `json:"information_photo_sizes" gorm:"embedded;embeddedPrefix:information_photo_"`
+	CreatedAt             time.Time       `json:"-"`
+	UpdatedAt             time.Time       `json:"-"`
+	DeletedAt             gorm.DeletedAt  `json:"-" gorm:"index"`
+	Category              Categories      `json:"category"`
 }
 
 type InformationRequest struct {
@@ -22,9 +28,10 @@ type InformationRequest struct {
 }
 
 type InformationUpdate struct {
-	Sysnopsis        string `json:"synopsis"`
-	Content          string `json:"content"`
-	InformationPhoto string `json:"-"`
+	Sysnopsis             string          `json:"synopsis"`
+	Content               string          `json:"content"`
+	InformationPhoto      string          `json:"-"`
+	InformationPhotoSizes ImageRenditions `json:"-" gorm:"embedded;embeddedPrefix:information_photo_"`
 }
 
 type InformationParam struct {
@@ -39,12 +46,13 @@ type Articles struct {
 }
 
 type Article struct {
-	Id               int    `json:"id"`
-	Title            string `json:"title"`
-	Content          string `json:"content"`
-	InformationPhoto string `json:"information_photo"`
-	CreatedAt        string `json:"created_at"`
-	UpdatedAt        string `json:"updated_at"`
+	Id                    int             `json:"id"`
+	Title                 string          `json:"title"`
+	Content               string          `json:"content"`
+	InformationPhoto      string          `json:"information_photo"`
+	InformationPhotoSizes ImageRenditions `json:"information_photo_sizes"`
+	CreatedAt             string          `json:"created_at"`
+	UpdatedAt             string          `json:"updated_at"`
 }
 
 type WebinarNCompetition struct {
@@ -5,20 +5,23 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Mentors struct {
-	Id            uuid.UUID      `json:"id" gorm:"type:varchar(36);primary key"`
-	Name          string         `json:"name" gorm:"unique"`
-	CurrentJob    string         `json:"current_job"`
-	Description   string         `json:"description"`
-	Price         uint64         `json:"price"`
-	MentorPicture string         `json:"mentor_picture"`
-	CreatedAt     time.Time      `json:"-"`
-	UpdatedAt     time.Time      `json:"-"`
-	Transactions  []Transactions `json:"-" gorm:"foreignKey:mentor_id;references:id"`
-	Experiences   []Experiences  `json:"-" gorm:"foreignKey:mentor_id;references:id"`
-	Users         []Users        `json:"-" gorm:"many2many:has_mentors;foreignKey:id;joinForeignKey:mentor_id;references:id;joinReferences:user_id"`
+	Id                 uuid.UUID       `json:"id" gorm:"type:varchar(36);primary key"`
+	Name               string          `json:"name" gorm:"unique"`
+	CurrentJob         string          `json:"current_job"`
+	Description        string          `json:"description"`
+	Price              uint64          `json:"price"`
+	MentorPicture      string          `json:"mentor_picture"`
+	MentorPictureSizes ImageRenditions `json:"mentor_picture_sizes" gorm:"embedded;embeddedPrefix:mentor_picture_"`
+	CreatedAt          time.Time       `json:"-"`
+	UpdatedAt          time.Time       `json:"-"`
+	DeletedAt          gorm.DeletedAt  `json:"-" gorm:"index"`
+	Transactions       []Transactions  `json:"-" gorm:"foreignKey:mentor_id;references:id"`
+	Experiences        []Experiences   `json:"-" gorm:"foreignKey:mentor_id;references:id"`
+	Users              []Users         `json:"-" gorm:"many2many:has_mentors;foreignKey:id;joinForeignKey:mentor_id;references:id;joinReferences:user_id"`
 }
 
 type MentorRequest struct {
@@ -33,10 +36,11 @@ type MentorParam struct {
 }
 
 type MentorUpdate struct {
-	CurrentJob    string `json:"current_job"`
-	Description   string `json:"description"`
-	Price         uint64 `json:"price"`
-	MentorPicture string `json:"-"`
+	CurrentJob         string          `json:"current_job"`
+	Description        string          `json:"description"`
+	Price              uint64          `json:"price"`
+	MentorPicture      string          `json:"-"`
+	MentorPictureSizes ImageRenditions `json:"-" gorm:"embedded;embeddedPrefix:mentor_picture_"`
 }
 
 type UploadMentorPicture struct {
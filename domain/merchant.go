@@ -5,35 +5,46 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type Merchants struct {
-	Id            uuid.UUID    `json:"id" gorm:"type:varchar(36);unique"`
-	UserId        uuid.UUID    `json:"-" gorm:"type:varchar(36);unique"`
-	MerchantName  string       `json:"merhcant_name"`
-	UniversityId  int          `json:"-"`
-	Faculty       string       `json:"faculty"`
-	ProvinceId    int          `json:"-"`
-	City          string       `json:"city"`
-	PhoneNumber   string       `json:"phone_number"`
-	Instagram     string       `json:"instagram"`
-	MerchantPhoto string       `json:"merchant_photo"`
-	IsActive      bool         `json:"-"`
-	CreatedAt     time.Time    `json:"-"`
-	UpdatedAt     time.Time    `json:"-"`
-	Products      []Products   `json:"-" gorm:"foreignKey:merchant_id;references:id"`
-	University    Universities `json:"University"`
-	Province      Province     `json:"-"`
+	Id                 uuid.UUID       `json:"id" gorm:"type:varchar(36);unique"`
+	UserId             uuid.UUID       `json:"-" gorm:"type:varchar(36);unique"`
+	MerchantName       string          `json:"merhcant_name"`
+	UniversityId       int             `json:"-"`
+	Faculty            string          `json:"faculty"`
+	ProvinceId         int             `json:"-"`
+	City               string          `json:"city"`
+	RegencyId          *int            `json:"-"`
+	DistrictId         *int            `json:"-"`
+	Latitude           *float64        `json:"latitude" gorm:"type:decimal(10,7)"`
+	Longitude          *float64        `json:"longitude" gorm:"type:decimal(10,7)"`
+	PhoneNumber        string          `json:"phone_number"`
+	Instagram          string          `json:"instagram"`
+	MerchantPhoto      string          `json:"merchant_photo"`
+	MerchantPhotoSizes ImageRenditions `json:"merchant_photo_sizes" gorm:"embedded;embeddedPrefix:merchant_photo_"`
+	IsActive           bool            `json:"-"`
+	CreatedAt          time.Time       `json:"-"`
+	UpdatedAt          time.Time       `json:"-"`
+	DeletedAt          gorm.DeletedAt  `json:"-" gorm:"index"`
+	Products           []Products      `json:"-" gorm:"foreignKey:merchant_id;references:id"`
+	University         Universities    `json:"University"`
+	Province           Province        `json:"-"`
 }
 
 type MerchantRequest struct {
-	MerchantName string `json:"merchant_name"`
-	University   string `json:"university" binding:"required"`
-	Faculty      string `json:"faculty" binding:"required"`
-	Province     string `json:"province" binding:"required"`
-	City         string `json:"city" binding:"required"`
-	PhoneNumber  string `json:"phone_number" binding:"required"`
-	Instagram    string `json:"instagram"`
+	MerchantName string   `json:"merchant_name"`
+	University   string   `json:"university" binding:"required"`
+	Faculty      string   `json:"faculty" binding:"required"`
+	Province     string   `json:"province" binding:"required"`
+	City         string   `json:"city" binding:"required_without=RegencyId"`
+	RegencyId    int      `json:"regency_id"`
+	DistrictId   int      `json:"district_id"`
+	Latitude     *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude    *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,min=-180,max=180"`
+	PhoneNumber  string   `json:"phone_number" binding:"required"`
+	Instagram    string   `json:"instagram"`
 }
 
 type MerchantParam struct {
@@ -47,15 +58,20 @@ type MerchantVerify struct {
 }
 
 type UpdateMerchant struct {
-	MerchantName  string `json:"merchant_name"`
-	UniversityId  int    `json:"-"`
-	Faculty       string `json:"-"`
-	ProvinceId    int    `json:"-"`
-	City          string `json:"-"`
-	PhoneNumber   string `json:"phone_number"`
-	Instagram     string `json:"instagram"`
-	MerchantPhoto string `json:"-"`
-	IsActive      bool   `json:"-"`
+	MerchantName       string          `json:"merchant_name"`
+	UniversityId       int             `json:"-"`
+	Faculty            string          `json:"-"`
+	ProvinceId         int             `json:"-"`
+	City               string          `json:"-"`
+	RegencyId          *int            `json:"-"`
+	DistrictId         *int            `json:"-"`
+	Latitude           *float64        `json:"latitude" binding:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude          *float64        `json:"longitude" binding:"required_with=Latitude,omitempty,min=-180,max=180"`
+	PhoneNumber        string          `json:"phone_number"`
+	Instagram          string          `json:"instagram"`
+	MerchantPhoto      string          `json:"-"`
+	MerchantPhotoSizes ImageRenditions `json:"-" gorm:"embedded;embeddedPrefix:merchant_photo_"`
+	IsActive           bool            `json:"-"`
 }
 
 type UploadMerchantPhoto struct {
@@ -63,13 +79,16 @@ type UploadMerchantPhoto struct {
 }
 
 type MerchantProfileResponse struct {
-	Id            uuid.UUID `json:"id"`
-	MerchantName  string    `json:"merchant_name"`
-	Province      string    `json:"province"`
-	City          string    `json:"city"`
-	University    string    `json:"university"`
-	Faculty       string    `json:"faculty"`
-	PhoneNumber   string    `json:"phone_number"`
-	Instagram     string    `json:"instagram"`
-	MerchantPhoto string    `json:"merchant_photo"`
+	Id                 uuid.UUID       `json:"id"`
+	MerchantName       string          `json:"merchant_name"`
+	Province           string          `json:"province"`
+	City               string          `json:"city"`
+	University         string          `json:"university"`
+	Faculty            string          `json:"faculty"`
+	Latitude           *float64        `json:"latitude"`
+	Longitude          *float64        `json:"longitude"`
+	PhoneNumber        string          `json:"phone_number"`
+	Instagram          string          `json:"instagram"`
+	MerchantPhoto      string          `json:"merchant_photo"`
+	MerchantPhotoSizes ImageRenditions `json:"merchant_photo_sizes"`
 }
@@ -5,21 +5,28 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+const ProductSortDistance = "distance"
+
 type Products struct {
-	Id           uuid.UUID  `json:"id" gorm:"type:varchar(36);primary key"`
-	MerchantId   uuid.UUID  `json:"-" gorm:"type:varchar(36)"`
-	CategoryId   int        `json:"-"`
-	Name         string     `json:"name"`
-	Price        uint       `json:"price"`
-	Description  string     `json:"description"`
-	ProductPhoto string     `json:"product_photo"`
-	CreatedAt    time.Time  `json:"-"`
-	UpdatedAt    time.Time  `json:"-"`
-	LikeByUser   []Users    `json:"-" gorm:"many2many:user_like_product;foreignKey:id;joinForeignKey:product_id;references:id;joinReferences:user_id"`
-	Merchant     Merchants  `json:"merchant"`
-	Category     Categories `json:"category"`
+	Id                uuid.UUID       `json:"id" gorm:"type:varchar(36);primary key"`
+	MerchantId        uuid.UUID       `json:"-" gorm:"type:varchar(36)"`
+	CategoryId        int             `json:"-"`
+	Name              string          `json:"name"`
+	Price             uint            `json:"price"`
+	Description       string          `json:"description"`
+	ProductPhoto      string          `json:"product_photo"`
+	ProductPhotoSizes ImageRenditions `json:"product_photo_sizes" gorm:"embedded;embeddedPrefix:product_photo_"`
+	CreatedAt         time.Time       `json:"-"`
+	UpdatedAt         time.Time       `json:"-"`
+	DeletedAt         gorm.DeletedAt  `json:"-" gorm:"index"`
+	Distance          *float64        `json:"distance,omitempty" gorm:"->;-:migration"`
+	LikeByUser        []Users         `json:"-" gorm:"many2many:user_like_product;foreignKey:id;joinForeignKey:product_id;references:id;joinReferences:user_id"`
+	Photos            []ProductPhotos `json:"photos" gorm:"foreignKey:product_id;references:id"`
+	Merchant          Merchants       `json:"merchant"`
+	Category          Categories      `json:"category"`
 }
 
 type UserLikeProduct struct {
@@ -35,6 +42,12 @@ type ProductParam struct {
 	Name         string    `json:"name" form:"name"`
 	ProvinceId   int       `json:"province_id" form:"province" gorm:"-"`
 	UniversityId int       `json:"university_id" form:"university" gorm:"-"`
+	RegencyId    int       `json:"regency_id" form:"city" gorm:"-"`
+	Latitude     *float64  `json:"latitude" form:"lat" gorm:"-"`
+	Longitude    *float64  `json:"longitude" form:"lng" gorm:"-"`
+	Radius       float64   `json:"radius" form:"radius" gorm:"-"`
+	Sort         string    `json:"sort" form:"sort" gorm:"-"`
+	Geohash      string    `json:"geohash" gorm:"-"`
 	Page         int       `json:"page" form:"page" gorm:"-"`
 	Offset       int       `json:"offset" gorm:"-"`
 }
@@ -65,28 +78,33 @@ type ProductResponses struct {
 	University   string    `json:"university"`
 	Price        uint      `json:"price"`
 	ProductPhoto string    `json:"product_photo"`
+	Distance     *float64  `json:"distance,omitempty"`
 }
 
 type ProductResponse struct {
-	Id           uuid.UUID `json:"id"`
-	Name         string    `json:"name"`
-	Description  string    `json:"description"`
-	MerchantName string    `json:"merchant_name"`
-	University   string    `json:"university"`
-	Faculty      string    `json:"faculty"`
-	Province     string    `json:"province"`
-	City         string    `json:"city"`
-	Price        uint      `json:"price"`
-	ProductPhoto string    `json:"product_photo"`
-	WhatsApp     string    `json:"whatsapp"`
-	Instagram    string    `json:"instagram"`
+	Id                uuid.UUID              `json:"id"`
+	Name              string                 `json:"name"`
+	Description       string                 `json:"description"`
+	MerchantName      string                 `json:"merchant_name"`
+	University        string                 `json:"university"`
+	Faculty           string                 `json:"faculty"`
+	Province          string                 `json:"province"`
+	City              string                 `json:"city"`
+	Price             uint                   `json:"price"`
+	ProductPhoto      string                 `json:"product_photo"`
+	ProductPhotoSizes ImageRenditions        `json:"product_photo_sizes"`
+	Photos            []ProductPhotoResponse `json:"photos"`
+	WhatsApp          string                 `json:"whatsapp"`
+	Instagram         string                 `json:"instagram"`
 }
 
 type ProductProfileResponse struct {
-	Id           uuid.UUID `json:"id"`
-	Name         string    `json:"name"`
-	Description  string    `json:"description"`
-	Category     string    `json:"category"`
-	Price        uint      `json:"price"`
-	ProductPhoto string    `json:"product_photo"`
+	Id                uuid.UUID              `json:"id"`
+	Name              string                 `json:"name"`
+	Description       string                 `json:"description"`
+	Category          string                 `json:"category"`
+	Price             uint                   `json:"price"`
+	ProductPhoto      string                 `json:"product_photo"`
+	ProductPhotoSizes ImageRenditions        `json:"product_photo_sizes"`
+	Photos            []ProductPhotoResponse `json:"photos"`
 }
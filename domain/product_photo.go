@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type ProductPhotos struct {
+	Id        uuid.UUID       `json:"id" gorm:"type:varchar(36);primary key"`
+	ProductId uuid.UUID       `json:"-" gorm:"type:varchar(36);index"`
+	Url       string          `json:"url"`
+	Sizes     ImageRenditions `json:"sizes" gorm:"embedded"`
+	Position  int             `json:"position"`
+	IsPrimary bool            `json:"is_primary"`
+	CreatedAt time.Time       `json:"-"`
+	UpdatedAt time.Time       `json:"-"`
+}
+
+type ProductPhotoOrder struct {
+	PhotoIds []uuid.UUID `json:"photo_ids" binding:"required,min=1,dive,required"`
+}
+
+type ProductPhotoResponse struct {
+	Id        uuid.UUID       `json:"id"`
+	Url       string          `json:"url"`
+	Sizes     ImageRenditions `json:"sizes"`
+	Position  int             `json:"position"`
+	IsPrimary bool            `json:"is_primary"`
+}
@@ -2,6 +2,18 @@ package domain
 
 type Province struct {
 	Id        int         `json:"-"`
+	Code      *string     `json:"code" gorm:"type:varchar(2);unique" binding:"required,len=2,numeric"`
 	Province  string      `json:"province" gorm:"unique" binding:"required"`
 	Merchants []Merchants `json:"-" gorm:"foreignKey:province_id;references:id"`
+	Regencies []Regencies `json:"-" gorm:"foreignKey:province_id;references:id"`
+}
+
+type ProvinceRequest struct {
+	Province string `json:"province" binding:"required"`
+}
+
+type ProvinceResponse struct {
+	Id       int     `json:"id"`
+	Code     *string `json:"code"`
+	Province string  `json:"province"`
 }
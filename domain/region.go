@@ -0,0 +1,24 @@
+package domain
+
+type Regencies struct {
+	Id         int         `json:"-"`
+	ProvinceId int         `json:"-"`
+	Code       string      `json:"code" gorm:"type:varchar(5);unique"`
+	Name       string      `json:"name" gorm:"type:varchar(191)"`
+	Province   Province    `json:"-"`
+	Districts  []Districts `json:"-" gorm:"foreignKey:regency_id;references:id"`
+}
+
+type Districts struct {
+	Id        int       `json:"-"`
+	RegencyId int       `json:"-"`
+	Code      string    `json:"code" gorm:"type:varchar(8);unique"`
+	Name      string    `json:"name" gorm:"type:varchar(191)"`
+	Regency   Regencies `json:"-"`
+}
+
+type RegionResponse struct {
+	Id   int    `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+}
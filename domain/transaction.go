@@ -7,7 +7,7 @@ import (
 )
 
 type Transactions struct {
-	Id          uuid.UUID `json:"-" gorm:"primary key"`
+	Id          uuid.UUID `json:"-" gorm:"type:varchar(36);primary key"`
 	UserId      uuid.UUID `json:"-"`
 	MentorId    uuid.UUID `json:"-"`
 	Price       uint64    `json:"-"`
@@ -32,3 +32,13 @@ type TransactionResponse struct {
 	BillKey     string `json:"bill_key"`
 	URL         string `json:"url"`
 }
+
+type TransactionExport struct {
+	Id          uuid.UUID `json:"id"`
+	MentorName  string    `json:"mentor_name"`
+	Price       uint64    `json:"price"`
+	PaymentType string    `json:"payment_type"`
+	IsPayed     bool      `json:"is_payed"`
+	CreatedAt   time.Time `json:"created_at"`
+	PayedAt     time.Time `json:"payed_at"`
+}
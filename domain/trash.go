@@ -0,0 +1,16 @@
+package domain
+
+import "time"
+
+type TrashItem struct {
+	Id        string    `json:"id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+type TrashResponse struct {
+	Products    []TrashItem `json:"products"`
+	Merchants   []TrashItem `json:"merchants"`
+	Mentors     []TrashItem `json:"mentors"`
+	Information []TrashItem `json:"information"`
+}
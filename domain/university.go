@@ -3,9 +3,31 @@ package domain
 type Universities struct {
 	Id         int         `json:"-"`
 	University string      `json:"university" gorm:"unique" binding:"required"`
+	Latitude   *float64    `json:"latitude" gorm:"type:decimal(10,7)" binding:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude  *float64    `json:"longitude" gorm:"type:decimal(10,7)" binding:"required_with=Latitude,omitempty,min=-180,max=180"`
 	Merchants  []Merchants `json:"-" gorm:"foreignKey:university_id;references:id"`
 }
 
 type UniversityRequest struct {
-	University string `json:"university" binding:"required"`
-}
\ No newline at end of file
+	University string   `json:"university" binding:"required"`
+	Latitude   *float64 `json:"latitude" binding:"required_with=Longitude,omitempty,min=-90,max=90"`
+	Longitude  *float64 `json:"longitude" binding:"required_with=Latitude,omitempty,min=-180,max=180"`
+}
+
+type UniversityParam struct {
+	Search string `json:"search" form:"search"`
+	Limit  int    `json:"limit" form:"limit"`
+}
+
+type UniversityResponse struct {
+	Id         int      `json:"id"`
+	University string   `json:"university"`
+	Latitude   *float64 `json:"latitude"`
+	Longitude  *float64 `json:"longitude"`
+}
+
+type UniversityImportResponse struct {
+	Created int      `json:"created"`
+	Skipped int      `json:"skipped"`
+	Errors  []string `json:"errors"`
+}
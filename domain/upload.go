@@ -0,0 +1,51 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	UploadEntityUser        = "user"
+	UploadEntityMerchant    = "merchant"
+	UploadEntityProduct     = "product"
+	UploadEntityMentor      = "mentor"
+	UploadEntityInformation = "information"
+)
+
+// UploadTarget is the record field a direct upload is attached to. EntityId is
+// empty for the login user and their merchant.
+type UploadTarget struct {
+	Entity   string `json:"entity"`
+	EntityId string `json:"entity_id,omitempty"`
+	Field    string `json:"field"`
+}
+
+type UploadRequest struct {
+	ContentType string `json:"content_type" binding:"required"`
+	Size        int64  `json:"size" binding:"required,min=1"`
+}
+
+type UploadConfirm struct {
+	UploadId uuid.UUID `json:"upload_id" binding:"required"`
+}
+
+type PendingUpload struct {
+	Id          uuid.UUID    `json:"id"`
+	UserId      uuid.UUID    `json:"user_id"`
+	Target      UploadTarget `json:"target"`
+	Key         string       `json:"key"`
+	Link        string       `json:"link"`
+	ContentType string       `json:"content_type"`
+	Size        int64        `json:"size"`
+}
+
+type UploadResponse struct {
+	UploadId  uuid.UUID         `json:"upload_id"`
+	Target    UploadTarget      `json:"target"`
+	Url       string            `json:"url"`
+	Method    string            `json:"method"`
+	Headers   map[string]string `json:"headers"`
+	ExpiresAt time.Time         `json:"expires_at"`
+}
@@ -7,21 +7,25 @@ import (
 )
 
 type Users struct {
-	Id             uuid.UUID      `json:"id" gorm:"type:varchar(36);primary key"`
-	Name           string         `json:"name" gorm:"unique"`
-	Email          string         `json:"email" gorm:"unique"`
-	Password       string         `json:"-"`
-	Gender         string         `json:"gender" gorm:"type:enum('Laki-laki', 'Perempuan', '') NULL"`
-	PlaceBirth     string         `json:"place_birth"`
-	DateBirth      string         `json:"date_birth"`
-	IsAdmin        bool           `json:"-"`
-	ProfilePicture string         `json:"profile_picture"`
-	CreatedAt      time.Time      `json:"-"`
-	UpdatedAt      time.Time      `json:"-"`
-	Merchant       Merchants      `json:"-" gorm:"foreignKey:user_id;references:id"`
-	Transactions   []Transactions `json:"-" gorm:"foreignKey:user_id;references:id"`
-	LikeProduct    []Products     `json:"like_product" gorm:"many2many:user_like_product;foreignKey:id;joinForeignKey:user_id;references:id;joinReferences:product_id"`
-	HasMentors     []Mentors      `json:"-" gorm:"many2many:has_mentors;foreignKey:id;joinForeignKey:user_id;references:id;joinReferences:mentor_id"`
+	Id                  uuid.UUID       `json:"id" gorm:"type:varchar(36);primary key"`
+	Name                string          `json:"name" gorm:"unique"`
+	Email               string          `json:"email" gorm:"unique"`
+	Password            string          `json:"-"`
+	Gender              string          `json:"gender" gorm:"type:enum('Laki-laki', 'Perempuan', '') NULL"`
+	PlaceBirth          string          `json:"place_birth"`
+	DateBirth           string          `json:"date_birth"`
+	IsAdmin             bool            `json:"-"`
+	ProfilePicture      string          `json:"profile_picture"`
+	ProfilePictureSizes ImageRenditions `json:"profile_picture_sizes" gorm:"embedded;embeddedPrefix:profile_picture_"`
+	TwoFactor           bool            `json:"-"`
+	TwoFactorKey        string          `json:"-"`
+	CreatedAt           time.Time       `json:"-"`
+	UpdatedAt           time.Time       `json:"-"`
+	Merchant            Merchants       `json:"-" gorm:"foreignKey:user_id;references:id"`
+	Transactions        []Transactions  `json:"-" gorm:"foreignKey:user_id;references:id"`
+	LikeProduct         []Products      `json:"like_product" gorm:"many2many:user_like_product;foreignKey:id;joinForeignKey:user_id;references:id;joinReferences:product_id"`
+	HasMentors          []Mentors       `json:"-" gorm:"many2many:has_mentors;foreignKey:id;joinForeignKey:user_id;references:id;joinReferences:mentor_id"`
+	RecoveryCodes       []RecoveryCodes `json:"-" gorm:"foreignKey:user_id;references:id"`
 }
 
 type UserRequest struct {
@@ -42,16 +46,19 @@ type UserParam struct {
 }
 
 type LoginResponse struct {
-	JWT string `json:"jwt"`
+	JWT               string `json:"jwt"`
+	TwoFactorRequired bool   `json:"two_factor_required"`
+	Challenge         string `json:"challenge,omitempty"`
 }
 
 type UserUpdate struct {
-	Name           string `json:"name"`
-	Gender         string `json:"gender"`
-	PlaceBirth     string `json:"place_birth"`
-	DateBirth      string `json:"date_birth"`
-	ProfilePicture string `json:"-"`
-	Password       string `json:"-"`
+	Name                string          `json:"name"`
+	Gender              string          `json:"gender"`
+	PlaceBirth          string          `json:"place_birth"`
+	DateBirth           string          `json:"date_birth"`
+	ProfilePicture      string          `json:"-"`
+	ProfilePictureSizes ImageRenditions `json:"-" gorm:"embedded;embeddedPrefix:profile_picture_"`
+	Password            string          `json:"-"`
 }
 
 type PasswordUpdate struct {
@@ -60,13 +67,34 @@ type PasswordUpdate struct {
 }
 
 type UserResponse struct {
-	Id             uuid.UUID `json:"id"`
-	Name           string    `json:"name"`
-	Email          string    `json:"email"`
-	Gender         string    `json:"gender"`
-	PlaceBirth     string    `json:"place_birth"`
-	DateBirth      string    `json:"date_birth"`
-	ProfilePicture string    `json:"profile_picture"`
+	Id                  uuid.UUID       `json:"id"`
+	Name                string          `json:"name"`
+	Email               string          `json:"email"`
+	Gender              string          `json:"gender"`
+	PlaceBirth          string          `json:"place_birth"`
+	DateBirth           string          `json:"date_birth"`
+	ProfilePicture      string          `json:"profile_picture"`
+	ProfilePictureSizes ImageRenditions `json:"profile_picture_sizes"`
+}
+
+type UserPublicResponse struct {
+	Id                  uuid.UUID       `json:"id"`
+	Name                string          `json:"name"`
+	ProfilePicture      string          `json:"profile_picture"`
+	ProfilePictureSizes ImageRenditions `json:"profile_picture_sizes"`
+}
+
+type DeleteAccountRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type UserDataExport struct {
+	Profile       UserResponse             `json:"profile"`
+	LikedProducts []ProductResponses       `json:"liked_products"`
+	Merchant      *MerchantProfileResponse `json:"merchant"`
+	Products      []ProductProfileResponse `json:"products"`
+	Transactions  []TransactionExport      `json:"transactions"`
+	Mentors       []OwnMentorResponses     `json:"mentors"`
 }
 
 type LikeProduct struct {
@@ -0,0 +1,309 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"intern-bcc/internal/handler/rest"
+	"intern-bcc/internal/repository"
+	"intern-bcc/internal/usecase"
+	"intern-bcc/pkg/cache"
+	"intern-bcc/pkg/config"
+	"intern-bcc/pkg/gomail"
+	"intern-bcc/pkg/health"
+	"intern-bcc/pkg/infrastucture/database"
+	"intern-bcc/pkg/jwt"
+	"intern-bcc/pkg/lifecycle"
+	"intern-bcc/pkg/logging"
+	"intern-bcc/pkg/middleware"
+	"intern-bcc/pkg/midtrans"
+	"intern-bcc/pkg/redis"
+	"intern-bcc/pkg/storage"
+	"intern-bcc/pkg/totp"
+	"intern-bcc/pkg/tracing"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const uploadSweepInterval = 15 * time.Minute
+
+type Dependencies struct {
+	DB       *gorm.DB
+	Cache    cache.ICache
+	Jwt      jwt.IJwt
+	GoMail   gomail.IGoMail
+	Midtrans midtrans.IMidTrans
+	Storage  storage.IStorage
+	Totp     totp.ITotp
+	Logging  logging.ILogging
+	Tracing  tracing.ITracing
+	Engine   *gin.Engine
+}
+
+type App struct {
+	Config config.Config
+	Dependencies
+	Repository *repository.Repository
+	Usecase    *usecase.Usecase
+	Middleware middleware.IMiddleware
+	Rest       *rest.Rest
+	Lifecycle  lifecycle.ILifecycle
+}
+
+// NewApp builds every dependency that deps leaves nil from cfg. Injected
+// dependencies are used as they are, so an injected DB must already have the
+// tracing plugin registered when its queries should be traced. What NewApp
+// opens itself is closed again when a later step fails.
+func NewApp(cfg config.Config, deps Dependencies) (app *App, err error) {
+	var cleanups []func()
+	defer func() {
+		if err == nil {
+			return
+		}
+		for i := len(cleanups) - 1; i >= 0; i-- {
+			cleanups[i]()
+		}
+	}()
+
+	if deps.Tracing == nil {
+		deps.Tracing, err = tracing.TracingInit(context.Background(), cfg.Tracing)
+		if err != nil {
+			return nil, err
+		}
+		cleanups = append(cleanups, func() { _ = deps.Tracing.Shutdown(context.Background()) })
+	}
+
+	if deps.DB == nil {
+		deps.DB, err = database.ConnectToDB(cfg.Database)
+		if err != nil {
+			return nil, err
+		}
+		cleanups = append(cleanups, func() { _ = closeDB(deps.DB) })
+
+		err = deps.DB.Use(tracing.GormPlugin())
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if deps.Cache == nil {
+		deps.Cache, err = newCache(cfg.Cache)
+		if err != nil {
+			return nil, err
+		}
+		cleanups = append(cleanups, func() { _ = deps.Cache.Close() })
+	}
+
+	if deps.Jwt == nil {
+		deps.Jwt = jwt.JwtInit(cfg.Jwt)
+	}
+	if deps.GoMail == nil {
+		deps.GoMail = gomail.GoMailInit(cfg.Mail)
+	}
+	if deps.Midtrans == nil {
+		deps.Midtrans = midtrans.MidTransInit(cfg.Midtrans)
+	}
+	if deps.Storage == nil {
+		deps.Storage, err = newStorage(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if deps.Totp == nil {
+		deps.Totp = totp.TotpInit(cfg.Totp)
+	}
+	if deps.Logging == nil {
+		deps.Logging = logging.LoggingInit()
+	}
+	if deps.Engine == nil {
+		deps.Engine = gin.New()
+	}
+
+	// gin trusts X-Forwarded-For from any peer by default, which would let
+	// clients pick their own address and reset the per-IP rate limits.
+	err = deps.Engine.SetTrustedProxies(cfg.App.TrustedProxies)
+	if err != nil {
+		return nil, fmt.Errorf("invalid trusted proxies: %w", err)
+	}
+
+	repositories := repository.NewRepository(deps.DB, repository.RepositoryParam{Cache: deps.Cache})
+
+	usecases := usecase.NewUsecase(usecase.UsecaseParam{
+		Repository: repositories,
+		Jwt:        deps.Jwt,
+		Storage:    deps.Storage,
+		Midtrans:   deps.Midtrans,
+		GoMail:     deps.GoMail,
+		Cache:      deps.Cache,
+		Totp:       deps.Totp,
+		BaseUrl:    cfg.App.BaseUrl(),
+	})
+
+	middlewares := middleware.MiddlerwareInit(deps.Jwt, usecases, deps.Logging, deps.Cache)
+
+	checks := health.HealthInit(cfg.Health.Timeout)
+	checks.Register("database", func(ctx context.Context) error {
+		sqlDB, err := deps.DB.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+	checks.Register("cache", deps.Cache.Ping)
+	if cfg.Health.CheckSmtp {
+		checks.Register("smtp", deps.GoMail.Ping)
+	}
+	if cfg.Health.CheckStorage {
+		checks.Register("storage", deps.Storage.Ping)
+	}
+
+	router := rest.NewRest(deps.Engine, usecases, middlewares, checks)
+	router.MountEndpoint()
+	if local, ok := deps.Storage.(*storage.Local); ok {
+		router.ServeFiles(storage.LocalRoute, local)
+	}
+
+	hooks := lifecycle.LifecycleInit(cfg.App.ShutdownTimeout)
+	hooks.Append(lifecycle.Hook{Name: "tracing", OnStop: deps.Tracing.Shutdown})
+	hooks.Append(closeHook("database", func() error {
+		return closeDB(deps.DB)
+	}))
+	hooks.Append(closeHook("cache", deps.Cache.Close))
+	hooks.Worker("upload sweeper", func(ctx context.Context) error {
+		return sweepUploads(ctx, usecases.UploadUsecase)
+	})
+
+	return &App{
+		Config:       cfg,
+		Dependencies: deps,
+		Repository:   repositories,
+		Usecase:      usecases,
+		Middleware:   middlewares,
+		Rest:         router,
+		Lifecycle:    hooks,
+	}, nil
+}
+
+func (a *App) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{
+		Addr:              fmt.Sprintf(":%v", a.Config.App.Port),
+		Handler:           a.Rest.Handler(),
+		ReadTimeout:       a.Config.App.ReadTimeout,
+		ReadHeaderTimeout: a.Config.App.ReadTimeout,
+		WriteTimeout:      a.Config.App.WriteTimeout,
+		IdleTimeout:       a.Config.App.IdleTimeout,
+	}
+
+	a.Lifecycle.Append(lifecycle.Hook{
+		Name: "http server",
+		OnStart: func(ctx context.Context) error {
+			listener, err := net.Listen("tcp", server.Addr)
+			if err != nil {
+				return err
+			}
+
+			go func() {
+				err := server.Serve(listener)
+				if err != nil && !errors.Is(err, http.ErrServerClosed) {
+					a.Lifecycle.Fail(err)
+				}
+			}()
+
+			return nil
+		},
+		OnStop: server.Shutdown,
+	})
+
+	return a.Lifecycle.Run(ctx)
+}
+
+// sweepUploads removes abandoned direct uploads and unused images on start and
+// then every uploadSweepInterval. A failed sweep is logged and retried on the
+// next tick, since storage being briefly unreachable must not stop the app.
+func sweepUploads(ctx context.Context, uploadUsecase usecase.IUploadUsecase) error {
+	ticker := time.NewTicker(uploadSweepInterval)
+	defer ticker.Stop()
+
+	for {
+		err := uploadUsecase.SweepUploads(ctx)
+		if err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).WithError(err).Error("failed to sweep abandoned uploads")
+		}
+
+		err = uploadUsecase.SweepImages(ctx)
+		if err != nil && ctx.Err() == nil {
+			logging.FromContext(ctx).WithError(err).Error("failed to sweep unused images")
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func closeDB(db *gorm.DB) error {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	return sqlDB.Close()
+}
+
+func closeHook(name string, close func() error) lifecycle.Hook {
+	return lifecycle.Hook{
+		Name: name,
+		OnStop: func(ctx context.Context) error {
+			return close()
+		},
+	}
+}
+
+func newCache(cfg config.CacheConfig) (cache.ICache, error) {
+	switch cfg.Driver {
+	case "memory":
+		return cache.MemoryInit(cfg.MemorySize), nil
+	case "redis":
+		client, err := redis.ConnectToRedis(cfg.RedisUrl)
+		if err != nil {
+			return nil, err
+		}
+		client.AddHook(tracing.RedisHook())
+		return redis.RedisInit(client), nil
+	default:
+		return nil, fmt.Errorf("unknown cache driver %q", cfg.Driver)
+	}
+}
+
+func newStorage(cfg config.Config) (storage.IStorage, error) {
+	switch cfg.Storage.Driver {
+	case "supabase":
+		return storage.SupabaseInit(cfg.Supabase), nil
+	case "local":
+		baseUrl := cfg.Storage.PublicUrl
+		if baseUrl == "" {
+			baseUrl = cfg.App.BaseUrl() + storage.LocalRoute
+		}
+		local, err := storage.LocalInit(cfg.Storage.LocalDir, baseUrl)
+		if err != nil {
+			return nil, err
+		}
+		return local, nil
+	case "s3":
+		return storage.S3Init(cfg.S3, cfg.Storage.PublicUrl)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Storage.Driver)
+	}
+}
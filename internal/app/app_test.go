@@ -0,0 +1,360 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"intern-bcc/domain"
+	"intern-bcc/pkg/cache"
+	"intern-bcc/pkg/config"
+	"intern-bcc/pkg/jwt"
+	"intern-bcc/pkg/storage"
+	"intern-bcc/pkg/tracing"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/midtrans/midtrans-go/coreapi"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+type stubMail struct{}
+
+func (stubMail) SendGoMail(ctx context.Context, subject string, htmlBody string, toEmail string) error {
+	return nil
+}
+
+func (stubMail) Ping(ctx context.Context) error {
+	return nil
+}
+
+type stubMidtrans struct{}
+
+func (stubMidtrans) ChargeTransaction(ctx context.Context, newTransaction domain.Transactions) (*coreapi.ChargeResponse, error) {
+	return &coreapi.ChargeResponse{}, nil
+}
+
+func (stubMidtrans) VerifyPayment(ctx context.Context, transctionIdString string) (bool, error) {
+	return true, nil
+}
+
+// newTestDB opens a handle that never connects, which is enough to wire the
+// app as long as no request reaches the database.
+func newTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(mysql.New(mysql.Config{
+		DSN:                       "test:test@tcp(127.0.0.1:1)/test",
+		SkipInitializeWithVersion: true,
+	}), &gorm.Config{DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+// newTestConfig configures the app to store files in a temporary directory.
+func newTestConfig(t *testing.T) (config.Config, *storage.Local) {
+	t.Helper()
+
+	dir := t.TempDir()
+	cfg := config.Config{
+		App:     config.AppConfig{Address: "localhost", Port: "8080", ShutdownTimeout: time.Second},
+		Jwt:     config.JwtConfig{SecretKey: "secret", ExpiredTime: 1},
+		Storage: config.StorageConfig{Driver: "local", LocalDir: dir},
+		Health:  config.HealthConfig{Timeout: time.Second},
+	}
+
+	local, err := storage.LocalInit(dir, cfg.App.BaseUrl()+storage.LocalRoute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cfg, local
+}
+
+func TestNewAppWithInMemoryDependencies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg, local := newTestConfig(t)
+
+	deps := Dependencies{
+		DB:       newTestDB(t),
+		Cache:    cache.MemoryInit(100),
+		GoMail:   stubMail{},
+		Midtrans: stubMidtrans{},
+		Storage:  local,
+		Tracing:  tracing.Noop(),
+	}
+
+	// Building twice from the same dependencies must work, which it would not
+	// if NewApp registered plugins on the injected DB.
+	for i := 0; i < 2; i++ {
+		app, err := NewApp(cfg, deps)
+		if err != nil {
+			t.Fatalf("build %d: %v", i, err)
+		}
+
+		res := serve(app, http.MethodGet, "/healthz", "", nil)
+		if res.Code != http.StatusOK {
+			t.Fatalf("build %d: expected liveness to be ok, got %v", i, res.Code)
+		}
+
+		data := "file " + strconv.Itoa(i)
+		key := storage.Key([]byte(data), ".txt")
+		presigned, err := local.PresignUpload(context.Background(), key, "text/plain", int64(len(data)), time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res = serve(app, presigned.Method, strings.TrimPrefix(presigned.Url, cfg.App.BaseUrl()), data, presigned.Headers)
+		if res.Code != http.StatusOK {
+			t.Fatalf("build %d: expected the upload to be stored, got %v: %v", i, res.Code, res.Body)
+		}
+
+		res = serve(app, http.MethodGet, strings.TrimPrefix(presigned.Link, cfg.App.BaseUrl()), "", nil)
+		body, _ := io.ReadAll(res.Body)
+		if res.Code != http.StatusOK || string(body) != data {
+			t.Fatalf("build %d: expected the stored file to be served, got %v: %q", i, res.Code, body)
+		}
+	}
+}
+
+func TestLocalStorageNeverServesPendingUploads(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg, local := newTestConfig(t)
+	app, err := NewApp(cfg, Dependencies{
+		DB:       newTestDB(t),
+		Cache:    cache.MemoryInit(100),
+		GoMail:   stubMail{},
+		Midtrans: stubMidtrans{},
+		Storage:  local,
+		Tracing:  tracing.Noop(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A client can sign for an image and upload HTML instead; until the upload
+	// is confirmed and checked, it must not be reachable.
+	html := "<script>alert(1)</script>"
+	presigned, err := local.PresignUpload(context.Background(), storage.PendingDir+"/"+uuid.NewString(), "image/png", int64(len(html)), time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := serve(app, presigned.Method, strings.TrimPrefix(presigned.Url, cfg.App.BaseUrl()), html, presigned.Headers)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected the upload to be stored, got %v: %v", res.Code, res.Body)
+	}
+
+	res = serve(app, http.MethodGet, strings.TrimPrefix(presigned.Link, cfg.App.BaseUrl()), "", nil)
+	if res.Code != http.StatusNotFound {
+		t.Fatalf("expected a pending upload not to be served, got %v: %v", res.Code, res.Body)
+	}
+
+	// Stored files get the type of their extension and are never sniffed.
+	link, err := local.Upload(context.Background(), storage.Key([]byte(html), ".png"), "image/png", []byte(html))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res = serve(app, http.MethodGet, strings.TrimPrefix(link, cfg.App.BaseUrl()), "", nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected the stored file to be served, got %v", res.Code)
+	}
+	if contentType := res.Header().Get("Content-Type"); contentType != "image/png" {
+		t.Fatalf("expected the content type of the extension, got %v", contentType)
+	}
+	if res.Header().Get("X-Content-Type-Options") != "nosniff" {
+		t.Fatal("expected sniffing to be disabled")
+	}
+}
+
+func TestNewAppUsesInjectedEngine(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg, local := newTestConfig(t)
+
+	engine := gin.New()
+	engine.GET("/test-only", func(c *gin.Context) {
+		c.String(http.StatusOK, "injected")
+	})
+
+	app, err := NewApp(cfg, Dependencies{
+		DB:       newTestDB(t),
+		Cache:    cache.MemoryInit(100),
+		GoMail:   stubMail{},
+		Midtrans: stubMidtrans{},
+		Storage:  local,
+		Tracing:  tracing.Noop(),
+		Engine:   engine,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	res := serve(app, http.MethodGet, "/test-only", "", nil)
+	if res.Code != http.StatusOK || res.Body.String() != "injected" {
+		t.Fatalf("expected the injected engine to serve its own routes, got %v: %v", res.Code, res.Body)
+	}
+
+	res = serve(app, http.MethodGet, "/healthz", "", nil)
+	if res.Code != http.StatusOK {
+		t.Fatalf("expected the app routes to be mounted on the injected engine, got %v", res.Code)
+	}
+}
+
+func TestQuerySpansBelongToTheRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previous)
+		_ = provider.Shutdown(context.Background())
+	})
+
+	cfg, local := newTestConfig(t)
+
+	// The queries fail because the database is unreachable, but their spans
+	// are still recorded with the context they were given.
+	db := newTestDB(t)
+	err := db.Use(tracing.GormPlugin())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app, err := NewApp(cfg, Dependencies{
+		DB:       db,
+		Cache:    cache.MemoryInit(100),
+		GoMail:   stubMail{},
+		Midtrans: stubMidtrans{},
+		Storage:  local,
+		Tracing:  tracing.Noop(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := jwt.JwtInit(cfg.Jwt).GenerateToken(uuid.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := []struct {
+		name    string
+		target  string
+		headers map[string]string
+	}{
+		{name: "public route", target: "/api/v1/province/"},
+		{name: "authenticated route", target: "/api/v1/profile/me", headers: map[string]string{"Authorization": "Bearer " + token}},
+	}
+
+	for _, request := range requests {
+		t.Run(request.name, func(t *testing.T) {
+			exporter.Reset()
+			serve(app, http.MethodGet, request.target, "", request.headers)
+
+			var server trace.SpanContext
+			var queries []sdktrace.ReadOnlySpan
+			for _, span := range exporter.GetSpans().Snapshots() {
+				switch {
+				case span.SpanKind() == trace.SpanKindServer:
+					server = span.SpanContext()
+				case strings.HasPrefix(span.Name(), "gorm."):
+					queries = append(queries, span)
+				}
+			}
+
+			if !server.IsValid() {
+				t.Fatal("expected a server span for the request")
+			}
+			if len(queries) == 0 {
+				t.Fatal("expected the request to run a query")
+			}
+			for _, query := range queries {
+				if query.Parent().TraceID() != server.TraceID() || query.Parent().SpanID() != server.SpanID() {
+					t.Fatalf("expected %v to be a child of the request span, got parent %v", query.Name(), query.Parent())
+				}
+			}
+		})
+	}
+}
+
+func TestSweepUploadsDeletesOnlyAbandonedUploads(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg, local := newTestConfig(t)
+
+	app, err := NewApp(cfg, Dependencies{
+		DB:       newTestDB(t),
+		Cache:    cache.MemoryInit(100),
+		GoMail:   stubMail{},
+		Midtrans: stubMidtrans{},
+		Storage:  local,
+		Tracing:  tracing.Noop(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := context.Background()
+	for _, key := range []string{"uploads/abandoned", "uploads/pending", "kept.txt"} {
+		_, err = local.Upload(ctx, key, "text/plain", []byte(key))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	old := time.Now().Add(-2 * time.Hour)
+	for _, name := range []string{"uploads/abandoned", "kept.txt"} {
+		err = os.Chtimes(filepath.Join(cfg.Storage.LocalDir, filepath.FromSlash(name)), old, old)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err = app.Usecase.UploadUsecase.SweepUploads(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, exists := range map[string]bool{"uploads/abandoned": false, "uploads/pending": true, "kept.txt": true} {
+		_, err = local.Size(ctx, key)
+		if exists && err != nil {
+			t.Fatalf("expected %v to be kept, got %v", key, err)
+		}
+		if !exists && !errors.Is(err, storage.ErrNotFound) {
+			t.Fatalf("expected %v to be deleted, got %v", key, err)
+		}
+	}
+}
+
+func serve(app *App, method string, target string, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	res := httptest.NewRecorder()
+	app.Rest.Handler().ServeHTTP(res, req)
+
+	return res
+}
@@ -4,12 +4,34 @@ import (
 	"intern-bcc/domain"
 	"intern-bcc/pkg/response"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
+func (r *Rest) GetCategories(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var categoryParam domain.CategoryParam
+	err := c.ShouldBindQuery(&categoryParam)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	categories, err := r.usecase.CategoryUsecase.GetCategories(ctx, categoryParam)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success get categories", categories)
+}
+
 func (r *Rest) CreateCategory(c *gin.Context) {
-	var categoryRequest domain.Categories
+	ctx := c.Request.Context()
+
+	var categoryRequest domain.CategoryRequest
 
 	err := c.ShouldBindJSON(&categoryRequest)
 	if err != nil {
@@ -17,7 +39,7 @@ func (r *Rest) CreateCategory(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.CategoryUsecase.CreateCategory(categoryRequest)
+	err = r.usecase.CategoryUsecase.CreateCategory(ctx, categoryRequest)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -25,3 +47,48 @@ func (r *Rest) CreateCategory(c *gin.Context) {
 
 	response.Success(c, "success create category", nil)
 }
+
+func (r *Rest) UpdateCategory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	categoryIdString := c.Param("categoryId")
+	categoryId, err := strconv.Atoi(categoryIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing category id", err))
+		return
+	}
+
+	var categoryUpdate domain.CategoryUpdate
+	err = c.ShouldBindJSON(&categoryUpdate)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	category, err := r.usecase.CategoryUsecase.UpdateCategory(ctx, categoryId, categoryUpdate)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success update category", category)
+}
+
+func (r *Rest) DeleteCategory(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	categoryIdString := c.Param("categoryId")
+	categoryId, err := strconv.Atoi(categoryIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing category id", err))
+		return
+	}
+
+	err = r.usecase.CategoryUsecase.DeleteCategory(ctx, categoryId)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success delete category", nil)
+}
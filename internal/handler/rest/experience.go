@@ -10,6 +10,8 @@ import (
 )
 
 func (r *Rest) AddExperience(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	mentorIdString := c.Param("mentorId")
 	mentorId, _ := uuid.Parse(mentorIdString)
 	mentorParam := domain.MentorParam{
@@ -24,7 +26,7 @@ func (r *Rest) AddExperience(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.ExperienceUsecase.AddExperience(experienceRequest, mentorParam)
+	err = r.usecase.ExperienceUsecase.AddExperience(ctx, experienceRequest, mentorParam)
 	if err != nil {
 		response.Failed(c, err)
 		return
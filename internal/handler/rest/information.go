@@ -21,6 +21,8 @@ func (r *Rest) GetInformations(c *gin.Context) {
 }
 
 func (r *Rest) GetArticle(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	informationIdString := c.Param("informationId")
 	informationId, err := strconv.Atoi(informationIdString)
 	if err != nil {
@@ -32,7 +34,7 @@ func (r *Rest) GetArticle(c *gin.Context) {
 		Id: informationId,
 	}
 
-	article, err := r.usecase.InformationUsecase.GetArticle(informationParam)
+	article, err := r.usecase.InformationUsecase.GetArticle(ctx, informationParam)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -42,6 +44,8 @@ func (r *Rest) GetArticle(c *gin.Context) {
 }
 
 func (r *Rest) CreateInformation(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	var informationRequest domain.InformationRequest
 	err := c.ShouldBindJSON(&informationRequest)
 	if err != nil {
@@ -49,7 +53,7 @@ func (r *Rest) CreateInformation(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.InformationUsecase.CreateInformation(informationRequest)
+	err = r.usecase.InformationUsecase.CreateInformation(ctx, informationRequest)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -59,6 +63,8 @@ func (r *Rest) CreateInformation(c *gin.Context) {
 }
 
 func (r *Rest) UpdateInformation(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	informationIdString := c.Param("informationId")
 	informationId, err := strconv.Atoi(informationIdString)
 	if err != nil {
@@ -77,7 +83,7 @@ func (r *Rest) UpdateInformation(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.InformationUsecase.UpdateInformation(informationParam, informationUpdate)
+	err = r.usecase.InformationUsecase.UpdateInformation(ctx, informationParam, informationUpdate)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -87,6 +93,8 @@ func (r *Rest) UpdateInformation(c *gin.Context) {
 }
 
 func (r *Rest) UploadInformationPhoto(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	informationIdString := c.Param("informationId")
 	informationId, err := strconv.Atoi(informationIdString)
 	if err != nil {
@@ -104,7 +112,48 @@ func (r *Rest) UploadInformationPhoto(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.InformationUsecase.UploadInformationPhoto(informationParam, informationPhoto)
+	err = r.usecase.InformationUsecase.UploadInformationPhoto(ctx, informationParam, informationPhoto)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success upload information photo", nil)
+}
+
+func (r *Rest) RequestInformationPhotoUpload(c *gin.Context) {
+	informationIdString := c.Param("informationId")
+	informationId, err := strconv.Atoi(informationIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing information id", err))
+		return
+	}
+
+	r.requestUpload(c, domain.UploadTarget{Entity: domain.UploadEntityInformation, EntityId: strconv.Itoa(informationId), Field: "information_photo"})
+}
+
+func (r *Rest) ConfirmInformationPhotoUpload(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	informationIdString := c.Param("informationId")
+	informationId, err := strconv.Atoi(informationIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing information id", err))
+		return
+	}
+
+	informationPhoto, uploadId, err := r.openUpload(c, domain.UploadTarget{Entity: domain.UploadEntityInformation, EntityId: strconv.Itoa(informationId), Field: "information_photo"})
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+	defer r.usecase.UploadUsecase.FinishUpload(ctx, uploadId)
+
+	informationParam := domain.InformationParam{
+		Id: informationId,
+	}
+
+	err = r.usecase.InformationUsecase.UploadInformationPhoto(ctx, informationParam, informationPhoto)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -112,3 +161,26 @@ func (r *Rest) UploadInformationPhoto(c *gin.Context) {
 
 	response.Success(c, "success upload information photo", nil)
 }
+
+func (r *Rest) DeleteInformation(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	informationIdString := c.Param("informationId")
+	informationId, err := strconv.Atoi(informationIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing information id", err))
+		return
+	}
+
+	informationParam := domain.InformationParam{
+		Id: informationId,
+	}
+
+	err = r.usecase.InformationUsecase.DeleteInformation(ctx, informationParam)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success delete information", nil)
+}
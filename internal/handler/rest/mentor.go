@@ -10,6 +10,8 @@ import (
 )
 
 func (r *Rest) GetMentor(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	mentorIdString := c.Param("mentorId")
 	mentorId, err := uuid.Parse(mentorIdString)
 	if err != nil {
@@ -21,7 +23,7 @@ func (r *Rest) GetMentor(c *gin.Context) {
 		Id: mentorId,
 	}
 
-	mentor, err := r.usecase.MentorUsecase.GetMentor(mentorParam)
+	mentor, err := r.usecase.MentorUsecase.GetMentor(ctx, mentorParam)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -43,6 +45,8 @@ func (r *Rest) GetMentors(c *gin.Context) {
 }
 
 func (r *Rest) CreateMentor(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	var mentorRequest domain.MentorRequest
 	err := c.ShouldBindJSON(&mentorRequest)
 	if err != nil {
@@ -50,7 +54,7 @@ func (r *Rest) CreateMentor(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.MentorUsecase.CreateMentor(mentorRequest)
+	err = r.usecase.MentorUsecase.CreateMentor(ctx, mentorRequest)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -60,6 +64,8 @@ func (r *Rest) CreateMentor(c *gin.Context) {
 }
 
 func (r *Rest) UpdateMentor(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	mentorIdString := c.Param("mentorId")
 	mentorId, err := uuid.Parse(mentorIdString)
 	if err != nil {
@@ -78,7 +84,7 @@ func (r *Rest) UpdateMentor(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.MentorUsecase.UpdateMentor(mentorParam, mentorUpdate)
+	err = r.usecase.MentorUsecase.UpdateMentor(ctx, mentorParam, mentorUpdate)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -88,6 +94,8 @@ func (r *Rest) UpdateMentor(c *gin.Context) {
 }
 
 func (r *Rest) UploadMentorPicture(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	mentorIdString := c.Param("mentorId")
 	mentorId, err := uuid.Parse(mentorIdString)
 	if err != nil {
@@ -105,7 +113,7 @@ func (r *Rest) UploadMentorPicture(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.MentorUsecase.UploadMentorPhoto(mentorParam, mentorPicture)
+	err = r.usecase.MentorUsecase.UploadMentorPhoto(ctx, mentorParam, mentorPicture)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -113,3 +121,67 @@ func (r *Rest) UploadMentorPicture(c *gin.Context) {
 
 	response.Success(c, "succes upload mentor picture", nil)
 }
+
+func (r *Rest) RequestMentorPictureUpload(c *gin.Context) {
+	mentorIdString := c.Param("mentorId")
+	mentorId, err := uuid.Parse(mentorIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing string to uuid", err))
+		return
+	}
+
+	r.requestUpload(c, domain.UploadTarget{Entity: domain.UploadEntityMentor, EntityId: mentorId.String(), Field: "mentor_picture"})
+}
+
+func (r *Rest) ConfirmMentorPictureUpload(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	mentorIdString := c.Param("mentorId")
+	mentorId, err := uuid.Parse(mentorIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing string to uuid", err))
+		return
+	}
+
+	mentorPicture, uploadId, err := r.openUpload(c, domain.UploadTarget{Entity: domain.UploadEntityMentor, EntityId: mentorId.String(), Field: "mentor_picture"})
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+	defer r.usecase.UploadUsecase.FinishUpload(ctx, uploadId)
+
+	mentorParam := domain.MentorParam{
+		Id: mentorId,
+	}
+
+	err = r.usecase.MentorUsecase.UploadMentorPhoto(ctx, mentorParam, mentorPicture)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "succes upload mentor picture", nil)
+}
+
+func (r *Rest) DeleteMentor(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	mentorIdString := c.Param("mentorId")
+	mentorId, err := uuid.Parse(mentorIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing string to uuid", err))
+		return
+	}
+
+	mentorParam := domain.MentorParam{
+		Id: mentorId,
+	}
+
+	err = r.usecase.MentorUsecase.DeleteMentor(ctx, mentorParam)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "succes delete mentor", nil)
+}
@@ -10,7 +10,9 @@ import (
 )
 
 func (r *Rest) GetMerchant(c *gin.Context) {
-	merchant, err := r.usecase.MerchantUsecase.GetMerchant(c)
+	ctx := c.Request.Context()
+
+	merchant, err := r.usecase.MerchantUsecase.GetMerchant(c, ctx)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -20,6 +22,8 @@ func (r *Rest) GetMerchant(c *gin.Context) {
 }
 
 func (r *Rest) CreateMerchant(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	var merchantRequest domain.MerchantRequest
 	err := c.ShouldBindJSON(&merchantRequest)
 	if err != nil {
@@ -27,7 +31,7 @@ func (r *Rest) CreateMerchant(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.MerchantUsecase.CreateMerchant(c, merchantRequest)
+	err = r.usecase.MerchantUsecase.CreateMerchant(c, ctx, merchantRequest)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -68,22 +72,17 @@ func (r *Rest) VerifyOtp(c *gin.Context) {
 }
 
 func (r *Rest) UpdateMerchant(c *gin.Context) {
-	merchantIdString := c.Param("merchantId")
-	merchantId, err := uuid.Parse(merchantIdString)
-	if err != nil {
-		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parse merchant id", err))
-		return
-	}
+	ctx := c.Request.Context()
 
 	var updateMerchant domain.UpdateMerchant
 
-	err = c.ShouldBindJSON(&updateMerchant)
+	err := c.ShouldBindJSON(&updateMerchant)
 	if err != nil {
 		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
 		return
 	}
 
-	merchant, err := r.usecase.MerchantUsecase.UpdateMerchant(c, merchantId, updateMerchant)
+	merchant, err := r.usecase.MerchantUsecase.UpdateMerchant(c, ctx, updateMerchant)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -93,20 +92,38 @@ func (r *Rest) UpdateMerchant(c *gin.Context) {
 }
 
 func (r *Rest) UploadMerchantPhoto(c *gin.Context) {
-	merchantIdString := c.Param("merchantId")
-	merchantId, err := uuid.Parse(merchantIdString)
+	ctx := c.Request.Context()
+
+	merchantPhoto, err := c.FormFile("merchant_photo")
 	if err != nil {
-		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parse merchant id", err))
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
 		return
 	}
 
-	merchantPhoto, err := c.FormFile("merchant_photo")
+	merchant, err := r.usecase.MerchantUsecase.UploadMerchantPhoto(c, ctx, merchantPhoto)
 	if err != nil {
-		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success upload merchant photo", merchant)
+}
+
+func (r *Rest) RequestMerchantPhotoUpload(c *gin.Context) {
+	r.requestUpload(c, domain.UploadTarget{Entity: domain.UploadEntityMerchant, Field: "merchant_photo"})
+}
+
+func (r *Rest) ConfirmMerchantPhotoUpload(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	merchantPhoto, uploadId, err := r.openUpload(c, domain.UploadTarget{Entity: domain.UploadEntityMerchant, Field: "merchant_photo"})
+	if err != nil {
+		response.Failed(c, err)
 		return
 	}
+	defer r.usecase.UploadUsecase.FinishUpload(ctx, uploadId)
 
-	merchant, err := r.usecase.MerchantUsecase.UploadMerchantPhoto(c, merchantId, merchantPhoto)
+	merchant, err := r.usecase.MerchantUsecase.UploadMerchantPhoto(c, ctx, merchantPhoto)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -114,3 +131,34 @@ func (r *Rest) UploadMerchantPhoto(c *gin.Context) {
 
 	response.Success(c, "success upload merchant photo", merchant)
 }
+
+func (r *Rest) DeleteOwnMerchant(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	err := r.usecase.MerchantUsecase.DeleteOwnMerchant(c, ctx)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success delete merchant", nil)
+}
+
+func (r *Rest) DeleteMerchant(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	merchantIdString := c.Param("merchantId")
+	merchantId, err := uuid.Parse(merchantIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parse merchant id", err))
+		return
+	}
+
+	err = r.usecase.MerchantUsecase.DeleteMerchant(c, ctx, merchantId)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success delete merchant", nil)
+}
@@ -10,6 +10,8 @@ import (
 )
 
 func (r *Rest) CreateProduct(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	var productRequest domain.ProductRequest
 
 	err := c.ShouldBindJSON(&productRequest)
@@ -18,7 +20,7 @@ func (r *Rest) CreateProduct(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.ProductUsecase.CreateProduct(c, productRequest)
+	err = r.usecase.ProductUsecase.CreateProduct(c, ctx, productRequest)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -28,6 +30,8 @@ func (r *Rest) CreateProduct(c *gin.Context) {
 }
 
 func (r *Rest) UpdateProduct(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	productIdString := c.Param("productId")
 	productId, err := uuid.Parse(productIdString)
 	if err != nil {
@@ -42,7 +46,7 @@ func (r *Rest) UpdateProduct(c *gin.Context) {
 		return
 	}
 
-	product, err := r.usecase.ProductUsecase.UpdateProduct(c, productId, updateProduct)
+	product, err := r.usecase.ProductUsecase.UpdateProduct(c, ctx, productId, updateProduct)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -52,6 +56,8 @@ func (r *Rest) UpdateProduct(c *gin.Context) {
 }
 
 func (r *Rest) UploadProductPhoto(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	productIdString := c.Param("productId")
 	productId, err := uuid.Parse(productIdString)
 	if err != nil {
@@ -65,7 +71,7 @@ func (r *Rest) UploadProductPhoto(c *gin.Context) {
 		return
 	}
 
-	product, err := r.usecase.ProductUsecase.UploadProductPhoto(c, productId, productPhoto)
+	product, err := r.usecase.ProductUsecase.UploadProductPhoto(c, ctx, productId, productPhoto)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -94,6 +100,8 @@ func (r *Rest) GetProducts(c *gin.Context) {
 }
 
 func (r *Rest) GetProduct(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	productIdString := c.Param("productId")
 	productId, err := uuid.Parse(productIdString)
 	if err != nil {
@@ -104,7 +112,7 @@ func (r *Rest) GetProduct(c *gin.Context) {
 		Id: productId,
 	}
 
-	product, err := r.usecase.ProductUsecase.GetProduct(productParam)
+	product, err := r.usecase.ProductUsecase.GetProduct(ctx, productParam)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -114,6 +122,8 @@ func (r *Rest) GetProduct(c *gin.Context) {
 }
 
 func (r *Rest) GetOwnProduct(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	productIdString := c.Param("productId")
 	productId, err := uuid.Parse(productIdString)
 	if err != nil {
@@ -124,7 +134,7 @@ func (r *Rest) GetOwnProduct(c *gin.Context) {
 		Id: productId,
 	}
 
-	ownProduct, err := r.usecase.ProductUsecase.GetOwnProduct(productParam)
+	ownProduct, err := r.usecase.ProductUsecase.GetOwnProduct(c, ctx, productParam)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -132,3 +142,194 @@ func (r *Rest) GetOwnProduct(c *gin.Context) {
 
 	response.Success(c, "success get product data", ownProduct)
 }
+
+func (r *Rest) DeleteProduct(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	productIdString := c.Param("productId")
+	productId, err := uuid.Parse(productIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing product id", err))
+		return
+	}
+
+	err = r.usecase.ProductUsecase.DeleteProduct(c, ctx, productId)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success delete product", nil)
+}
+
+func (r *Rest) AddProductPhoto(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	productIdString := c.Param("productId")
+	productId, err := uuid.Parse(productIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing product id", err))
+		return
+	}
+
+	productPhoto, err := c.FormFile("product_photo")
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	photos, err := r.usecase.ProductUsecase.AddProductPhoto(c, ctx, productId, productPhoto)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success add product photo", photos)
+}
+
+func (r *Rest) RequestProductPhotoUpload(c *gin.Context) {
+	productIdString := c.Param("productId")
+	productId, err := uuid.Parse(productIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing product id", err))
+		return
+	}
+
+	r.requestUpload(c, domain.UploadTarget{Entity: domain.UploadEntityProduct, EntityId: productId.String(), Field: "product_photo"})
+}
+
+func (r *Rest) ConfirmProductPhotoUpload(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	productIdString := c.Param("productId")
+	productId, err := uuid.Parse(productIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing product id", err))
+		return
+	}
+
+	productPhoto, uploadId, err := r.openUpload(c, domain.UploadTarget{Entity: domain.UploadEntityProduct, EntityId: productId.String(), Field: "product_photo"})
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+	defer r.usecase.UploadUsecase.FinishUpload(ctx, uploadId)
+
+	photos, err := r.usecase.ProductUsecase.AddProductPhoto(c, ctx, productId, productPhoto)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success add product photo", photos)
+}
+
+func (r *Rest) ReplaceProductPhoto(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	productIdString := c.Param("productId")
+	productId, err := uuid.Parse(productIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing product id", err))
+		return
+	}
+
+	photoIdString := c.Param("photoId")
+	photoId, err := uuid.Parse(photoIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing photo id", err))
+		return
+	}
+
+	productPhoto, err := c.FormFile("product_photo")
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	photos, err := r.usecase.ProductUsecase.ReplaceProductPhoto(c, ctx, productId, photoId, productPhoto)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success replace product photo", photos)
+}
+
+func (r *Rest) ReorderProductPhotos(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	productIdString := c.Param("productId")
+	productId, err := uuid.Parse(productIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing product id", err))
+		return
+	}
+
+	var photoOrder domain.ProductPhotoOrder
+	err = c.ShouldBindJSON(&photoOrder)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	photos, err := r.usecase.ProductUsecase.ReorderProductPhotos(c, ctx, productId, photoOrder)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success reorder product photos", photos)
+}
+
+func (r *Rest) SetPrimaryProductPhoto(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	productIdString := c.Param("productId")
+	productId, err := uuid.Parse(productIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing product id", err))
+		return
+	}
+
+	photoIdString := c.Param("photoId")
+	photoId, err := uuid.Parse(photoIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing photo id", err))
+		return
+	}
+
+	photos, err := r.usecase.ProductUsecase.SetPrimaryProductPhoto(c, ctx, productId, photoId)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success set primary product photo", photos)
+}
+
+func (r *Rest) DeleteProductPhoto(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	productIdString := c.Param("productId")
+	productId, err := uuid.Parse(productIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing product id", err))
+		return
+	}
+
+	photoIdString := c.Param("photoId")
+	photoId, err := uuid.Parse(photoIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing photo id", err))
+		return
+	}
+
+	photos, err := r.usecase.ProductUsecase.DeleteProductPhoto(c, ctx, productId, photoId)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success delete product photo", photos)
+}
@@ -4,11 +4,26 @@ import (
 	"intern-bcc/domain"
 	"intern-bcc/pkg/response"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
+func (r *Rest) GetProvinces(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	provinces, err := r.usecase.ProvinceUsecase.GetProvinces(ctx)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success get provinces", provinces)
+}
+
 func (r *Rest) CreateProvince(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	var provinceRequest domain.Province
 	err := c.ShouldBindJSON(&provinceRequest)
 	if err != nil {
@@ -16,7 +31,7 @@ func (r *Rest) CreateProvince(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.ProvinceUsecase.CreateProvince(provinceRequest)
+	err = r.usecase.ProvinceUsecase.CreateProvince(ctx, provinceRequest)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -24,3 +39,48 @@ func (r *Rest) CreateProvince(c *gin.Context) {
 
 	response.Success(c, "success create province", nil)
 }
+
+func (r *Rest) UpdateProvince(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	provinceIdString := c.Param("provinceId")
+	provinceId, err := strconv.Atoi(provinceIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing province id", err))
+		return
+	}
+
+	var provinceRequest domain.ProvinceRequest
+	err = c.ShouldBindJSON(&provinceRequest)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	province, err := r.usecase.ProvinceUsecase.UpdateProvince(ctx, provinceId, provinceRequest)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success update province", province)
+}
+
+func (r *Rest) DeleteProvince(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	provinceIdString := c.Param("provinceId")
+	provinceId, err := strconv.Atoi(provinceIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing province id", err))
+		return
+	}
+
+	err = r.usecase.ProvinceUsecase.DeleteProvince(ctx, provinceId)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success delete province", nil)
+}
@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"intern-bcc/pkg/response"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *Rest) GetRegencies(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	provinceIdString := c.Param("provinceId")
+	provinceId, err := strconv.Atoi(provinceIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing province id", err))
+		return
+	}
+
+	regencies, err := r.usecase.RegionUsecase.GetRegencies(ctx, provinceId)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success get regencies", regencies)
+}
+
+func (r *Rest) GetDistricts(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	regencyIdString := c.Param("regencyId")
+	regencyId, err := strconv.Atoi(regencyIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing regency id", err))
+		return
+	}
+
+	districts, err := r.usecase.RegionUsecase.GetDistricts(ctx, regencyId)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success get districts", districts)
+}
@@ -1,28 +1,31 @@
 package rest
 
 import (
-	"fmt"
 	"intern-bcc/internal/usecase"
+	"intern-bcc/pkg/health"
 	"intern-bcc/pkg/middleware"
-	"log"
-	"os"
+	"net/http"
 	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
 )
 
 type Rest struct {
 	router     *gin.Engine
 	usecase    *usecase.Usecase
 	middleware middleware.IMiddleware
+	health     health.IHealth
 }
 
-func NewRest(c *gin.Engine, usecase *usecase.Usecase, middleware middleware.IMiddleware) *Rest {
+func NewRest(c *gin.Engine, usecase *usecase.Usecase, middleware middleware.IMiddleware, health health.IHealth) *Rest {
 	return &Rest{
-		router:     gin.Default(),
+		router:     c,
 		usecase:    usecase,
 		middleware: middleware,
+		health:     health,
 	}
 }
 
@@ -37,31 +40,53 @@ func (r *Rest) MountEndpoint() {
 		MaxAge: 12 * time.Hour,
 	}))
 
+	r.router.Use(otelgin.Middleware("intern-bcc"), r.middleware.RequestId, r.middleware.Metrics, r.middleware.Recovery)
+
+	r.router.GET("/healthz", r.Liveness)
+	r.router.GET("/readyz", r.Readiness)
+	r.router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
 	routerGroup := r.router.Group("api/v1", r.middleware.LogEvent)
 
 	routerGroup.POST("/register", r.Register)
-	routerGroup.POST("/login", r.Login)
-	routerGroup.GET("/recoveryaccount", r.PasswordRecovery)
-	routerGroup.PATCH("/recoveryaccount/:name/:verPass", r.ChangePassword)
+	routerGroup.POST("/login", r.middleware.RateLimitByIP("login", 20, time.Minute), r.middleware.RateLimitByAccount("login", 10, 15*time.Minute), r.Login)
+	routerGroup.POST("/login/2fa", r.middleware.RateLimitByIP("login-2fa", 10, time.Minute), r.LoginTwoFactor)
+	routerGroup.GET("/recoveryaccount", r.middleware.RateLimitByIP("recovery", 10, 15*time.Minute), r.middleware.RateLimitByAccount("recovery", 3, 15*time.Minute), r.PasswordRecovery)
+	routerGroup.PATCH("/recoveryaccount/:name/:verPass", r.middleware.RateLimitByIP("recovery-change", 10, 15*time.Minute), r.ChangePassword)
+
+	twoFactor := routerGroup.Group("/2fa")
+	twoFactor.POST("/enroll", r.middleware.Authentication, r.EnrollTwoFactor)
+	twoFactor.POST("/activate", r.middleware.Authentication, r.ActivateTwoFactor)
+	twoFactor.POST("/disable", r.middleware.Authentication, r.DisableTwoFactor)
+	twoFactor.POST("/recovery-codes", r.middleware.Authentication, r.RegenerateRecoveryCodes)
 
 	user := routerGroup.Group("/user")
-	user.PATCH("/:userId", r.middleware.Authentication, r.UpdateUser)
-	user.PATCH("/:userId/upload-photo", r.middleware.Authentication, r.UploadUserPhoto)
+	user.PATCH("/me", r.middleware.Authentication, r.UpdateUser)
+	user.PATCH("/me/upload-photo", r.middleware.Authentication, r.UploadUserPhoto)
+	user.POST("/me/upload-photo/presign", r.middleware.Authentication, r.RequestUserPhotoUpload)
+	user.POST("/me/upload-photo/confirm", r.middleware.Authentication, r.ConfirmUserPhotoUpload)
+	user.DELETE("/me", r.middleware.Authentication, r.DeleteAccount)
 
 	profile := routerGroup.Group("/profile")
+	profile.GET("/me", r.middleware.Authentication, r.GetProfile)
+	profile.GET("/me/export", r.middleware.Authentication, r.ExportUserData)
 	profile.GET("/:userId", r.middleware.Authentication, r.GetUser)
 	profile.GET("/favourite", r.middleware.Authentication, r.GetLikeProduct)
 	profile.GET("/merchant", r.middleware.Authentication, r.GetMerchant)
 	profile.GET("/product", r.middleware.Authentication, r.GetOwnProducts)
 	profile.GET("/product/:productId", r.middleware.Authentication, r.GetOwnProduct)
+	profile.DELETE("/product/:productId", r.middleware.Authentication, r.DeleteProduct)
 	profile.GET("/mentor", r.middleware.Authentication, r.GetOwnMentors)
 
 	merchant := routerGroup.Group("/merchant")
 	merchant.POST("/", r.middleware.Authentication, r.CreateMerchant)
-	merchant.GET("/verify", r.middleware.Authentication, r.SendOtp)
-	merchant.PATCH("/verify", r.middleware.Authentication, r.VerifyOtp)
-	merchant.PATCH("/:merchantId", r.middleware.Authentication, r.UpdateMerchant)
-	merchant.PATCH("/:merchantId/upload-photo", r.middleware.Authentication, r.UploadMerchantPhoto)
+	merchant.GET("/verify", r.middleware.Authentication, r.middleware.RateLimitByAccount("otp-send", 3, 10*time.Minute), r.SendOtp)
+	merchant.PATCH("/verify", r.middleware.RateLimitByIP("otp-verify", 20, 15*time.Minute), r.middleware.Authentication, r.middleware.RateLimitByAccount("otp-verify", 10, 15*time.Minute), r.VerifyOtp)
+	merchant.PATCH("/me", r.middleware.Authentication, r.UpdateMerchant)
+	merchant.PATCH("/me/upload-photo", r.middleware.Authentication, r.UploadMerchantPhoto)
+	merchant.POST("/me/upload-photo/presign", r.middleware.Authentication, r.RequestMerchantPhotoUpload)
+	merchant.POST("/me/upload-photo/confirm", r.middleware.Authentication, r.ConfirmMerchantPhotoUpload)
+	merchant.DELETE("/me", r.middleware.Authentication, r.DeleteOwnMerchant)
 
 	mentor := routerGroup.Group("/mentor")
 	mentor.GET("/:mentorId", r.GetMentor)
@@ -69,6 +94,9 @@ func (r *Rest) MountEndpoint() {
 	mentor.POST("/", r.middleware.Authentication, r.middleware.OnlyAdmin, r.CreateMentor)
 	mentor.PATCH("/:mentorId", r.middleware.Authentication, r.middleware.OnlyAdmin, r.UpdateMentor)
 	mentor.PATCH("/:mentorId/upload-photo", r.middleware.Authentication, r.middleware.OnlyAdmin, r.UploadMentorPicture)
+	mentor.POST("/:mentorId/upload-photo/presign", r.middleware.Authentication, r.middleware.OnlyAdmin, r.RequestMentorPictureUpload)
+	mentor.POST("/:mentorId/upload-photo/confirm", r.middleware.Authentication, r.middleware.OnlyAdmin, r.ConfirmMentorPictureUpload)
+	mentor.DELETE("/:mentorId", r.middleware.Authentication, r.middleware.OnlyAdmin, r.DeleteMentor)
 	mentor.POST("/:mentorId/transaction", r.middleware.Authentication, r.CreateTransaction)
 	mentor.POST("/:mentorId/experience", r.middleware.Authentication, r.middleware.OnlyAdmin, r.AddExperience)
 	mentor.POST("/payment-callback", r.VerifyTransaction)
@@ -79,6 +107,13 @@ func (r *Rest) MountEndpoint() {
 	product.POST("/", r.middleware.Authentication, r.CreateProduct)
 	product.PATCH("/:productId", r.middleware.Authentication, r.UpdateProduct)
 	product.PATCH("/:productId/product-photo", r.middleware.Authentication, r.UploadProductPhoto)
+	product.POST("/:productId/photo", r.middleware.Authentication, r.AddProductPhoto)
+	product.POST("/:productId/photo/presign", r.middleware.Authentication, r.RequestProductPhotoUpload)
+	product.POST("/:productId/photo/confirm", r.middleware.Authentication, r.ConfirmProductPhotoUpload)
+	product.PATCH("/:productId/photo/order", r.middleware.Authentication, r.ReorderProductPhotos)
+	product.PATCH("/:productId/photo/:photoId", r.middleware.Authentication, r.ReplaceProductPhoto)
+	product.PATCH("/:productId/photo/:photoId/primary", r.middleware.Authentication, r.SetPrimaryProductPhoto)
+	product.DELETE("/:productId/photo/:photoId", r.middleware.Authentication, r.DeleteProductPhoto)
 	product.POST("/:productId", r.middleware.Authentication, r.LikeProduct)
 	product.DELETE("/:productId", r.middleware.Authentication, r.DeleteLikeProduct)
 
@@ -88,22 +123,49 @@ func (r *Rest) MountEndpoint() {
 	information.POST("/", r.middleware.Authentication, r.middleware.OnlyAdmin, r.CreateInformation)
 	information.PATCH("/:informationId", r.middleware.Authentication, r.middleware.OnlyAdmin, r.UpdateInformation)
 	information.PATCH("/:informationId/upload-photo", r.middleware.Authentication, r.middleware.OnlyAdmin, r.UploadInformationPhoto)
+	information.POST("/:informationId/upload-photo/presign", r.middleware.Authentication, r.middleware.OnlyAdmin, r.RequestInformationPhotoUpload)
+	information.POST("/:informationId/upload-photo/confirm", r.middleware.Authentication, r.middleware.OnlyAdmin, r.ConfirmInformationPhotoUpload)
+	information.DELETE("/:informationId", r.middleware.Authentication, r.middleware.OnlyAdmin, r.DeleteInformation)
+
+	admin := routerGroup.Group("/admin", r.middleware.Authentication, r.middleware.OnlyAdmin)
+	admin.GET("/trash", r.GetTrash)
+	admin.PATCH("/trash/:entity/:id/restore", r.RestoreTrash)
+	admin.DELETE("/product/:productId", r.DeleteProduct)
+	admin.DELETE("/merchant/:merchantId", r.DeleteMerchant)
 
 	category := routerGroup.Group("/category")
+	category.GET("/", r.GetCategories)
 	category.POST("/", r.middleware.Authentication, r.middleware.OnlyAdmin, r.CreateCategory)
+	category.PATCH("/:categoryId", r.middleware.Authentication, r.middleware.OnlyAdmin, r.UpdateCategory)
+	category.DELETE("/:categoryId", r.middleware.Authentication, r.middleware.OnlyAdmin, r.DeleteCategory)
 
 	province := routerGroup.Group("/province")
+	province.GET("/", r.GetProvinces)
+	province.GET("/:provinceId/regency", r.GetRegencies)
 	province.POST("/", r.middleware.Authentication, r.middleware.OnlyAdmin, r.CreateProvince)
+	province.PATCH("/:provinceId", r.middleware.Authentication, r.middleware.OnlyAdmin, r.UpdateProvince)
+	province.DELETE("/:provinceId", r.middleware.Authentication, r.middleware.OnlyAdmin, r.DeleteProvince)
+
+	regency := routerGroup.Group("/regency")
+	regency.GET("/:regencyId/district", r.GetDistricts)
 
 	university := routerGroup.Group("/university")
+	university.GET("/", r.GetUniversities)
 	university.POST("/", r.middleware.Authentication, r.middleware.OnlyAdmin, r.CreateUniversity)
+	university.POST("/import", r.middleware.Authentication, r.middleware.OnlyAdmin, r.ImportUniversities)
+	university.PATCH("/:universityId", r.middleware.Authentication, r.middleware.OnlyAdmin, r.UpdateUniversity)
+	university.DELETE("/:universityId", r.middleware.Authentication, r.middleware.OnlyAdmin, r.DeleteUniversity)
 }
 
-func (r *Rest) Run() {
-	port := os.Getenv("PORT")
+// ServeFiles serves the files of the local storage driver and accepts direct
+// uploads to them.
+func (r *Rest) ServeFiles(route string, files http.Handler) {
+	handler := gin.WrapH(http.StripPrefix(route, files))
+	r.router.GET(route+"/*key", handler)
+	r.router.HEAD(route+"/*key", handler)
+	r.router.PUT(route+"/*key", handler)
+}
 
-	err := r.router.Run(fmt.Sprintf(":%v", port))
-	if err != nil {
-		log.Fatal("failed to run router")
-	}
+func (r *Rest) Handler() http.Handler {
+	return r.router
 }
@@ -10,6 +10,8 @@ import (
 )
 
 func (r *Rest) CreateTransaction(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	mentorIdString := c.Param("mentorId")
 	mentorId, _ := uuid.Parse(mentorIdString)
 
@@ -22,7 +24,7 @@ func (r *Rest) CreateTransaction(c *gin.Context) {
 		return
 	}
 
-	coreApiRes, err := r.usecase.TransactionUsecase.CreateTransaction(c, mentorId, transactionRequest)
+	coreApiRes, err := r.usecase.TransactionUsecase.CreateTransaction(c, ctx, mentorId, transactionRequest)
 	if err != nil {
 
 		response.Failed(c, err)
@@ -41,7 +43,7 @@ func (r *Rest) VerifyTransaction(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.TransactionUsecase.VerifyTransaction(payLoad)
+	err = r.usecase.TransactionUsecase.VerifyTransaction(c.Request.Context(), payLoad)
 	if err != nil {
 		response.Failed(c, err)
 		return
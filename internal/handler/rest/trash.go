@@ -0,0 +1,33 @@
+package rest
+
+import (
+	"intern-bcc/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *Rest) GetTrash(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	trash, err := r.usecase.TrashUsecase.GetTrash(ctx)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success get trash", trash)
+}
+
+func (r *Rest) RestoreTrash(c *gin.Context) {
+	ctx := c.Request.Context()
+	entity := c.Param("entity")
+	id := c.Param("id")
+
+	err := r.usecase.TrashUsecase.Restore(ctx, entity, id)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success restore "+entity, nil)
+}
@@ -0,0 +1,97 @@
+package rest
+
+import (
+	"intern-bcc/domain"
+	"intern-bcc/pkg/response"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (r *Rest) EnrollTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	enrollResponse, err := r.usecase.TwoFactorUsecase.Enroll(c, ctx)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "scan the provisioning uri with your authenticator app", enrollResponse)
+}
+
+func (r *Rest) ActivateTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var twoFactorCode domain.TwoFactorCode
+	err := c.ShouldBindJSON(&twoFactorCode)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	recoveryCodes, err := r.usecase.TwoFactorUsecase.Activate(c, ctx, twoFactorCode)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success enable two factor, please save your recovery codes", recoveryCodes)
+}
+
+func (r *Rest) DisableTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var twoFactorCode domain.TwoFactorCode
+	err := c.ShouldBindJSON(&twoFactorCode)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	err = r.usecase.TwoFactorUsecase.Disable(c, ctx, twoFactorCode)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success disable two factor", nil)
+}
+
+func (r *Rest) RegenerateRecoveryCodes(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var twoFactorCode domain.TwoFactorCode
+	err := c.ShouldBindJSON(&twoFactorCode)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	recoveryCodes, err := r.usecase.TwoFactorUsecase.RegenerateRecoveryCodes(c, ctx, twoFactorCode)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success regenerate recovery codes", recoveryCodes)
+}
+
+func (r *Rest) LoginTwoFactor(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var twoFactorLogin domain.TwoFactorLogin
+	err := c.ShouldBindJSON(&twoFactorLogin)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	loginResponse, err := r.usecase.TwoFactorUsecase.Login(ctx, twoFactorLogin)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "login success", loginResponse)
+}
@@ -4,11 +4,33 @@ import (
 	"intern-bcc/domain"
 	"intern-bcc/pkg/response"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 )
 
+func (r *Rest) GetUniversities(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var universityParam domain.UniversityParam
+	err := c.ShouldBindQuery(&universityParam)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	universities, err := r.usecase.UniversityUsecase.GetUniversities(ctx, universityParam)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success get universities", universities)
+}
+
 func (r *Rest) CreateUniversity(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	var universityRequest domain.Universities
 	err := c.ShouldBindJSON(&universityRequest)
 	if err != nil {
@@ -16,7 +38,7 @@ func (r *Rest) CreateUniversity(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.UniversityUsecase.CreateUniversity(universityRequest)
+	err = r.usecase.UniversityUsecase.CreateUniversity(ctx, universityRequest)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -24,3 +46,66 @@ func (r *Rest) CreateUniversity(c *gin.Context) {
 
 	response.Success(c, "success create university", nil)
 }
+
+func (r *Rest) ImportUniversities(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	result, err := r.usecase.UniversityUsecase.ImportUniversities(ctx, file)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success import universities", result)
+}
+
+func (r *Rest) UpdateUniversity(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	universityIdString := c.Param("universityId")
+	universityId, err := strconv.Atoi(universityIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing university id", err))
+		return
+	}
+
+	var universityRequest domain.UniversityRequest
+	err = c.ShouldBindJSON(&universityRequest)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	university, err := r.usecase.UniversityUsecase.UpdateUniversity(ctx, universityId, universityRequest)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success update university", university)
+}
+
+func (r *Rest) DeleteUniversity(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	universityIdString := c.Param("universityId")
+	universityId, err := strconv.Atoi(universityIdString)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing university id", err))
+		return
+	}
+
+	err = r.usecase.UniversityUsecase.DeleteUniversity(ctx, universityId)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success delete university", nil)
+}
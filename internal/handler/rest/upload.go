@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"intern-bcc/domain"
+	"intern-bcc/pkg/response"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// requestUpload answers with a presigned URL to upload a photo for target
+// straight to the storage.
+func (r *Rest) requestUpload(c *gin.Context, target domain.UploadTarget) {
+	ctx := c.Request.Context()
+
+	var uploadRequest domain.UploadRequest
+	err := c.ShouldBindJSON(&uploadRequest)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	upload, err := r.usecase.UploadUsecase.RequestUpload(c, ctx, target, uploadRequest)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success create upload url", upload)
+}
+
+// openUpload returns the verified direct upload named in the request body. The
+// caller attaches it like a form upload and then finishes the upload.
+func (r *Rest) openUpload(c *gin.Context, target domain.UploadTarget) (*multipart.FileHeader, uuid.UUID, error) {
+	ctx := c.Request.Context()
+
+	var uploadConfirm domain.UploadConfirm
+	err := c.ShouldBindJSON(&uploadConfirm)
+	if err != nil {
+		return nil, uuid.Nil, response.NewError(http.StatusBadRequest, "failed to bind request", err)
+	}
+
+	file, err := r.usecase.UploadUsecase.OpenUpload(c, ctx, target, uploadConfirm.UploadId)
+	if err != nil {
+		return nil, uuid.Nil, err
+	}
+
+	return file, uploadConfirm.UploadId, nil
+}
@@ -1,6 +1,7 @@
 package rest
 
 import (
+	"fmt"
 	"intern-bcc/domain"
 	"intern-bcc/pkg/response"
 	"net/http"
@@ -10,6 +11,8 @@ import (
 )
 
 func (r *Rest) Register(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	var userRequest domain.UserRequest
 
 	err := c.ShouldBindJSON(&userRequest)
@@ -19,7 +22,7 @@ func (r *Rest) Register(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.UserUsecase.Register(userRequest)
+	err = r.usecase.UserUsecase.Register(ctx, userRequest)
 	if err != nil {
 
 		response.Failed(c, err)
@@ -30,6 +33,8 @@ func (r *Rest) Register(c *gin.Context) {
 }
 
 func (r *Rest) Login(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	var userLogin domain.UserLogin
 
 	err := c.ShouldBindJSON(&userLogin)
@@ -39,7 +44,7 @@ func (r *Rest) Login(c *gin.Context) {
 		return
 	}
 
-	loginRespone, err := r.usecase.UserUsecase.Login(userLogin)
+	loginRespone, err := r.usecase.UserUsecase.Login(ctx, userLogin)
 	if err != nil {
 
 		response.Failed(c, err)
@@ -49,7 +54,19 @@ func (r *Rest) Login(c *gin.Context) {
 	response.Success(c, "login success", loginRespone)
 }
 
+func (r *Rest) GetProfile(c *gin.Context) {
+	user, err := r.usecase.UserUsecase.GetProfile(c)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success get profile", user)
+}
+
 func (r *Rest) GetUser(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	userIdString := c.Param("userId")
 	userId, err := uuid.Parse(userIdString)
 	if err != nil {
@@ -61,9 +78,8 @@ func (r *Rest) GetUser(c *gin.Context) {
 		Id: userId,
 	}
 
-	user, err := r.usecase.UserUsecase.GetUser(userParam)
+	user, err := r.usecase.UserUsecase.GetPublicProfile(ctx, userParam)
 	if err != nil {
-
 		response.Failed(c, err)
 		return
 	}
@@ -72,7 +88,9 @@ func (r *Rest) GetUser(c *gin.Context) {
 }
 
 func (r *Rest) GetOwnProducts(c *gin.Context) {
-	products, err := r.usecase.UserUsecase.GetOwnProducts(c)
+	ctx := c.Request.Context()
+
+	products, err := r.usecase.UserUsecase.GetOwnProducts(c, ctx)
 	if err != nil {
 
 		response.Failed(c, err)
@@ -83,7 +101,9 @@ func (r *Rest) GetOwnProducts(c *gin.Context) {
 }
 
 func (r *Rest) GetLikeProduct(c *gin.Context) {
-	products, err := r.usecase.UserUsecase.GetLikeProducts(c)
+	ctx := c.Request.Context()
+
+	products, err := r.usecase.UserUsecase.GetLikeProducts(c, ctx)
 	if err != nil {
 
 		response.Failed(c, err)
@@ -94,7 +114,9 @@ func (r *Rest) GetLikeProduct(c *gin.Context) {
 }
 
 func (r *Rest) GetOwnMentors(c *gin.Context) {
-	mentors, err := r.usecase.UserUsecase.GetOwnMentors(c)
+	ctx := c.Request.Context()
+
+	mentors, err := r.usecase.UserUsecase.GetOwnMentors(c, ctx)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -104,21 +126,17 @@ func (r *Rest) GetOwnMentors(c *gin.Context) {
 }
 
 func (r *Rest) UpdateUser(c *gin.Context) {
-	userIdString := c.Param("userId")
-	userId, err := uuid.Parse(userIdString)
-	if err != nil {
-		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing user id", err))
-		return
-	}
+	ctx := c.Request.Context()
+
 	var userUpdate domain.UserUpdate
 
-	err = c.ShouldBindJSON(&userUpdate)
+	err := c.ShouldBindJSON(&userUpdate)
 	if err != nil {
 		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
 		return
 	}
 
-	updatedUser, err := r.usecase.UserUsecase.UpdateUser(c, userId, userUpdate)
+	updatedUser, err := r.usecase.UserUsecase.UpdateUser(c, ctx, userUpdate)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -128,20 +146,36 @@ func (r *Rest) UpdateUser(c *gin.Context) {
 }
 
 func (r *Rest) UploadUserPhoto(c *gin.Context) {
-	userIdString := c.Param("userId")
-	userId, err := uuid.Parse(userIdString)
+	profilePicture, err := c.FormFile("profile_picture")
 	if err != nil {
-		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to parsing user id", err))
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
 		return
 	}
 
-	profilePicture, err := c.FormFile("profile_picture")
+	user, err := r.usecase.UserUsecase.UploadUserPhoto(c, profilePicture)
 	if err != nil {
-		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		response.Failed(c, err)
 		return
 	}
 
-	user, err := r.usecase.UserUsecase.UploadUserPhoto(c, userId, profilePicture)
+	response.Success(c, "success updload photo", user)
+}
+
+func (r *Rest) RequestUserPhotoUpload(c *gin.Context) {
+	r.requestUpload(c, domain.UploadTarget{Entity: domain.UploadEntityUser, Field: "profile_picture"})
+}
+
+func (r *Rest) ConfirmUserPhotoUpload(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	profilePicture, uploadId, err := r.openUpload(c, domain.UploadTarget{Entity: domain.UploadEntityUser, Field: "profile_picture"})
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+	defer r.usecase.UploadUsecase.FinishUpload(ctx, uploadId)
+
+	user, err := r.usecase.UserUsecase.UploadUserPhoto(c, profilePicture)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -191,6 +225,8 @@ func (r *Rest) ChangePassword(c *gin.Context) {
 }
 
 func (r *Rest) LikeProduct(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	productIdString := c.Param("productId")
 	productId, err := uuid.Parse(productIdString)
 	if err != nil {
@@ -198,7 +234,7 @@ func (r *Rest) LikeProduct(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.UserUsecase.LikeProduct(c, productId)
+	err = r.usecase.UserUsecase.LikeProduct(c, ctx, productId)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -208,6 +244,8 @@ func (r *Rest) LikeProduct(c *gin.Context) {
 }
 
 func (r *Rest) DeleteLikeProduct(c *gin.Context) {
+	ctx := c.Request.Context()
+
 	productIdString := c.Param("productId")
 	productId, err := uuid.Parse(productIdString)
 	if err != nil {
@@ -215,7 +253,7 @@ func (r *Rest) DeleteLikeProduct(c *gin.Context) {
 		return
 	}
 
-	err = r.usecase.UserUsecase.DeleteLikeProduct(c, productId)
+	err = r.usecase.UserUsecase.DeleteLikeProduct(c, ctx, productId)
 	if err != nil {
 		response.Failed(c, err)
 		return
@@ -223,3 +261,35 @@ func (r *Rest) DeleteLikeProduct(c *gin.Context) {
 
 	response.Success(c, "success delete liked product", nil)
 }
+
+func (r *Rest) ExportUserData(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	userData, err := r.usecase.UserUsecase.ExportUserData(c, ctx)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%v.json", userData.Profile.Id))
+	response.Success(c, "success export user data", userData)
+}
+
+func (r *Rest) DeleteAccount(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	var deleteRequest domain.DeleteAccountRequest
+	err := c.ShouldBindJSON(&deleteRequest)
+	if err != nil {
+		response.Failed(c, response.NewError(http.StatusBadRequest, "failed to bind request", err))
+		return
+	}
+
+	err = r.usecase.UserUsecase.DeleteAccount(c, ctx, deleteRequest)
+	if err != nil {
+		response.Failed(c, err)
+		return
+	}
+
+	response.Success(c, "success delete account", nil)
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"intern-bcc/pkg/cache"
+	"intern-bcc/pkg/logging"
+	"intern-bcc/pkg/metrics"
+	"intern-bcc/pkg/tracing"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	cacheFreshTTL    = 5 * time.Minute
+	cacheStaleTTL    = 10 * time.Minute
+	cacheFillTimeout = 10 * time.Second
+)
+
+type cacheEnvelope struct {
+	FreshUntil time.Time       `json:"fresh_until"`
+	Data       json.RawMessage `json:"data"`
+}
+
+type cacheLoader func(ctx context.Context) (interface{}, []string, error)
+
+type listCache struct {
+	name  string
+	cache cache.ICache
+	group singleflight.Group
+}
+
+func newListCache(name string, cache cache.ICache) *listCache {
+	return &listCache{
+		name:  name,
+		cache: cache,
+	}
+}
+
+func (c *listCache) get(ctx context.Context, key string, dest interface{}, load cacheLoader) (err error) {
+	ctx, span := tracing.Start(ctx, "cache."+c.name, attribute.String("cache.key", key))
+	defer func() { tracing.End(span, err) }()
+
+	stringData, err := c.cache.Get(ctx, key)
+	if err == nil {
+		var envelope cacheEnvelope
+		if json.Unmarshal([]byte(stringData), &envelope) == nil && json.Unmarshal(envelope.Data, dest) == nil {
+			if time.Now().Before(envelope.FreshUntil) {
+				metrics.CacheRequests.WithLabelValues(c.name, "hit").Inc()
+				span.SetAttributes(attribute.String("cache.result", "hit"))
+				return nil
+			}
+
+			metrics.CacheRequests.WithLabelValues(c.name, "stale").Inc()
+			span.SetAttributes(attribute.String("cache.result", "stale"))
+			c.refresh(ctx, key, load)
+			return nil
+		}
+	}
+
+	metrics.CacheRequests.WithLabelValues(c.name, "miss").Inc()
+	span.SetAttributes(attribute.String("cache.result", "miss"))
+	data, err, _ := c.group.Do(key, func() (interface{}, error) {
+		return c.fill(ctx, key, load)
+	})
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data.([]byte), dest)
+}
+
+func (c *listCache) refresh(ctx context.Context, key string, load cacheLoader) {
+	result := c.group.DoChan(key, func() (interface{}, error) {
+		return c.fill(ctx, key, load)
+	})
+
+	go func() {
+		res := <-result
+		if res.Err != nil {
+			logging.FromContext(ctx).WithError(res.Err).WithField("cache_key", key).Warn("failed to refresh stale cache")
+		}
+	}()
+}
+
+// fill reads the tag generation before loading, so the page is not cached
+// when one of its tags is invalidated while the query runs; caching it then
+// would keep serving the data from before the write until it expires.
+func (c *listCache) fill(ctx context.Context, key string, load cacheLoader) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheFillTimeout)
+	defer cancel()
+
+	generation, cacheErr := c.cache.TagGeneration(ctx)
+	if cacheErr != nil && !errors.Is(cacheErr, cache.ErrUnavailable) {
+		return nil, cacheErr
+	}
+
+	value, tags, err := load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if cacheErr != nil {
+		logging.FromContext(ctx).WithError(cacheErr).WithField("cache_key", key).Warn("cache unavailable, serving from database")
+		return data, nil
+	}
+
+	byteEnvelope, err := json.Marshal(cacheEnvelope{
+		FreshUntil: time.Now().Add(cacheFreshTTL),
+		Data:       data,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stored, err := c.cache.SetWithTags(ctx, key, string(byteEnvelope), cacheFreshTTL+cacheStaleTTL, generation, tags...)
+	if errors.Is(err, cache.ErrUnavailable) {
+		logging.FromContext(ctx).WithError(err).WithField("cache_key", key).Warn("cache unavailable, serving from database")
+	} else if err != nil {
+		return nil, err
+	} else if !stored {
+		logging.FromContext(ctx).WithField("cache_key", key).Debug("cache invalidated while loading, not storing the result")
+	}
+
+	return data, nil
+}
@@ -0,0 +1,51 @@
+package repository
+
+import (
+	"context"
+	"intern-bcc/pkg/cache"
+	"testing"
+)
+
+func TestListCacheDoesNotStorePageInvalidatedWhileLoading(t *testing.T) {
+	ctx := context.Background()
+	memory := cache.MemoryInit(10)
+	listCache := newListCache("products", memory)
+
+	loads := 0
+	load := func(ctx context.Context) (interface{}, []string, error) {
+		loads++
+		if loads == 1 {
+			// A write commits and invalidates the listings while the
+			// first query is still running.
+			err := memory.InvalidateTags(ctx, TagProducts)
+			if err != nil {
+				return nil, nil, err
+			}
+			return []string{"old"}, []string{TagProducts}, nil
+		}
+
+		return []string{"new"}, []string{TagProducts}, nil
+	}
+
+	var page []string
+	err := listCache.get(ctx, "products", &page, load)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = listCache.get(ctx, "products", &page, load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loads != 2 || page[0] != "new" {
+		t.Fatalf("expected the page to be loaded again after the invalidation, got %v after %v loads", page, loads)
+	}
+
+	err = listCache.get(ctx, "products", &page, load)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loads != 2 {
+		t.Fatalf("expected the reloaded page to be cached, got %v loads", loads)
+	}
+}
@@ -1,26 +1,40 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"intern-bcc/domain"
+	"intern-bcc/pkg/cache"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrCategoryInUse            = errors.New("category has products or information")
+	ErrCategoryHasSubcategories = errors.New("category has subcategories")
 )
 
 type ICategoryRepository interface {
-	GetCategory(category *domain.Categories, categoryParam domain.Categories) error
-	CreateCategory(category *domain.Categories) error
+	GetCategory(ctx context.Context, category *domain.Categories, categoryParam domain.Categories) error
+	GetCategories(ctx context.Context, categories *[]domain.Categories, categoryParam domain.CategoryParam) error
+	CountSubcategories(ctx context.Context, categoryId int) (int64, error)
+	CreateCategory(ctx context.Context, category *domain.Categories) error
+	UpdateCategory(ctx context.Context, category *domain.Categories) error
+	DeleteCategory(ctx context.Context, categoryId int) error
 }
 
 type CategoryRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	cache cache.ICache
 }
 
-func NewCategoryRepository(db *gorm.DB) ICategoryRepository {
-	return &CategoryRepository{db}
+func NewCategoryRepository(db *gorm.DB, cache cache.ICache) ICategoryRepository {
+	return &CategoryRepository{db, cache}
 }
 
-func (r *CategoryRepository) GetCategory(category *domain.Categories, categoryParam domain.Categories) error {
-	err := r.db.First(category, categoryParam).Error
+func (r *CategoryRepository) GetCategory(ctx context.Context, category *domain.Categories, categoryParam domain.Categories) error {
+	err := r.db.WithContext(ctx).First(category, categoryParam).Error
 	if err != nil {
 		return err
 	}
@@ -28,11 +42,85 @@ func (r *CategoryRepository) GetCategory(category *domain.Categories, categoryPa
 	return nil
 }
 
-func (r *CategoryRepository) CreateCategory(category *domain.Categories) error {
-	err := r.db.Create(category).Error
+func (r *CategoryRepository) GetCategories(ctx context.Context, categories *[]domain.Categories, categoryParam domain.CategoryParam) error {
+	query := r.db.WithContext(ctx).Model(&domain.Categories{})
+	if categoryParam.Kind != "" {
+		query = query.Where("kind = ?", categoryParam.Kind)
+	}
+
+	err := query.Order("id").Find(categories).Error
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (r *CategoryRepository) CountSubcategories(ctx context.Context, categoryId int) (int64, error) {
+	var total int64
+	err := r.db.WithContext(ctx).Model(&domain.Categories{}).Where("parent_id = ?", categoryId).Count(&total).Error
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (r *CategoryRepository) CreateCategory(ctx context.Context, category *domain.Categories) error {
+	err := r.db.WithContext(ctx).Create(category).Error
+	if err != nil {
+		return err
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts, TagInformation)
+}
+
+func (r *CategoryRepository) UpdateCategory(ctx context.Context, category *domain.Categories) error {
+	err := r.db.WithContext(ctx).Model(&domain.Categories{}).Where("id = ?", category.Id).
+		Select("category", "parent_id").Updates(category).Error
+	if err != nil {
+		return err
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts, TagInformation)
+}
+
+// DeleteCategory locks the category while it checks for products, information
+// and subcategories, so a record created in between waits on the foreign key
+// and fails instead of pointing at a deleted category.
+func (r *CategoryRepository) DeleteCategory(ctx context.Context, categoryId int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var category domain.Categories
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&category, categoryId).Error
+		if err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{&domain.Products{}, &domain.Information{}} {
+			var total int64
+			err = tx.Unscoped().Model(model).Where("category_id = ?", categoryId).Count(&total).Error
+			if err != nil {
+				return err
+			}
+			if total > 0 {
+				return ErrCategoryInUse
+			}
+		}
+
+		var totalSubcategory int64
+		err = tx.Model(&domain.Categories{}).Where("parent_id = ?", categoryId).Count(&totalSubcategory).Error
+		if err != nil {
+			return err
+		}
+		if totalSubcategory > 0 {
+			return ErrCategoryHasSubcategories
+		}
+
+		return tx.Delete(&category).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts, TagInformation)
+}
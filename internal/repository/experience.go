@@ -1,13 +1,14 @@
 package repository
 
 import (
+	"context"
 	"intern-bcc/domain"
 
 	"gorm.io/gorm"
 )
 
 type IExperienceRepository interface {
-	AddExperience(experience *domain.Experiences) error
+	AddExperience(ctx context.Context, experience *domain.Experiences) error
 }
 
 type ExperienceRepository struct {
@@ -18,8 +19,8 @@ func NewExperienceRepository(db *gorm.DB) IExperienceRepository {
 	return &ExperienceRepository{db}
 }
 
-func (r *ExperienceRepository) AddExperience(experience *domain.Experiences) error {
-	err := r.db.Create(experience).Error
+func (r *ExperienceRepository) AddExperience(ctx context.Context, experience *domain.Experiences) error {
+	err := r.db.WithContext(ctx).Create(experience).Error
 	if err != nil {
 		return err
 	}
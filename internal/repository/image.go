@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"intern-bcc/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrImageReferenced = errors.New("image is still referenced")
+
+type IImageRepository interface {
+	CreateUploadReferences(ctx context.Context, links []string) error
+	DeleteUnreferenced(ctx context.Context, link string, uploadedBefore time.Time, remove func(ctx context.Context) error) error
+	DeleteExpiredUploadReferences(ctx context.Context, uploadedBefore time.Time) ([]string, error)
+}
+
+type ImageRepository struct {
+	db *gorm.DB
+}
+
+func NewImageRepository(db *gorm.DB) IImageRepository {
+	return &ImageRepository{db}
+}
+
+// CreateUploadReferences reserves the links of an upload before its files are
+// stored, so a concurrent delete of the same content addressed files waits for
+// it or leaves them alone.
+func (r *ImageRepository) CreateUploadReferences(ctx context.Context, links []string) error {
+	ownerId := uuid.NewString()
+	references := make([]domain.ImageReferences, 0, len(links))
+	for _, link := range links {
+		references = append(references, domain.ImageReferences{
+			Link:    link,
+			Owner:   domain.ImageOwnerUpload,
+			OwnerId: ownerId,
+		})
+	}
+
+	err := r.db.WithContext(ctx).Create(&references).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteUnreferenced calls remove unless a record, including soft deleted ones
+// that can still be restored, or an upload made after uploadedBefore uses the
+// file, and returns ErrImageReferenced when one does. The locking read also
+// locks the link's gap in the index, so nothing can start using the file
+// until remove has returned.
+func (r *ImageRepository) DeleteUnreferenced(ctx context.Context, link string, uploadedBefore time.Time, remove func(ctx context.Context) error) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var references []domain.ImageReferences
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("link = ?", link).Find(&references).Error
+		if err != nil {
+			return err
+		}
+
+		for _, reference := range references {
+			if reference.Owner != domain.ImageOwnerUpload || !reference.CreatedAt.Before(uploadedBefore) {
+				return ErrImageReferenced
+			}
+		}
+
+		return remove(ctx)
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteExpiredUploadReferences removes the references of uploads made before
+// uploadedBefore and returns their links, whose files may no longer be used.
+func (r *ImageRepository) DeleteExpiredUploadReferences(ctx context.Context, uploadedBefore time.Time) ([]string, error) {
+	var links []string
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Model(&domain.ImageReferences{}).Where("owner = ? AND created_at < ?", domain.ImageOwnerUpload, uploadedBefore)
+
+		err := query.Distinct().Pluck("link", &links).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("owner = ? AND created_at < ?", domain.ImageOwnerUpload, uploadedBefore).Delete(&domain.ImageReferences{}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return links, nil
+}
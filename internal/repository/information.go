@@ -2,13 +2,11 @@ package repository
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"time"
 
 	"intern-bcc/domain"
 
-	"intern-bcc/pkg/redis"
+	"intern-bcc/pkg/cache"
 
 	"gorm.io/gorm"
 )
@@ -16,105 +14,112 @@ import (
 type IInformationRepository interface {
 	GetArticles(ctx context.Context, articles *[]domain.Articles) error
 	GetWebinarNCompetition(ctx context.Context, webinarNCompetition *[]domain.Information) error
-	GetInformation(information *domain.Information, informationParam domain.InformationParam) error
-	CreateInformation(newInformation *domain.Information) error
-	UpdateInformation(information *domain.InformationUpdate, informationId int) error
+	GetInformation(ctx context.Context, information *domain.Information, informationParam domain.InformationParam) error
+	CreateInformation(ctx context.Context, newInformation *domain.Information) error
+	UpdateInformation(ctx context.Context, information *domain.InformationUpdate, informationId int) error
+	DeleteInformation(ctx context.Context, informationId int) error
+	RestoreInformation(ctx context.Context, informationId int) error
+	GetDeletedInformation(ctx context.Context, information *[]domain.Information) error
 }
 
 type InformationRepository struct {
-	db    *gorm.DB
-	redis redis.IRedis
+	db        *gorm.DB
+	cache     cache.ICache
+	listCache *listCache
 }
 
-func NewInformationRepository(db *gorm.DB, redis redis.IRedis) IInformationRepository {
-	return &InformationRepository{db, redis}
+func NewInformationRepository(db *gorm.DB, cache cache.ICache) IInformationRepository {
+	return &InformationRepository{db, cache, newListCache("information", cache)}
 }
 
 func (r *InformationRepository) GetArticles(ctx context.Context, articles *[]domain.Articles) error {
 	key := fmt.Sprintf(KeySetInformationNmentor, "Articles")
-	stringArticles, err := r.redis.GetRedis(ctx, key)
-	if err != nil {
-		err = r.db.Model(domain.Information{}).Where("category_id = ?", 7).Order("created_at desc").Limit(15).Find(articles).Error
+	return r.listCache.get(ctx, key, articles, func(ctx context.Context) (interface{}, []string, error) {
+		var articles []domain.Articles
+		err := r.db.WithContext(ctx).Model(domain.Information{}).Where("category_id IN (?)", r.categoryIdsByKind(ctx, domain.CategoryKindArticle)).Order("created_at desc").Limit(15).Find(&articles).Error
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 
-		byteArticles, err := json.Marshal(articles)
-		if err != nil {
-			return nil
-		}
+		return articles, []string{TagInformation}, nil
+	})
+}
 
-		err = r.redis.SetRedis(ctx, key, string(byteArticles), 5*time.Minute)
+func (r *InformationRepository) GetWebinarNCompetition(ctx context.Context, webinarNCompetition *[]domain.Information) error {
+	key := fmt.Sprintf(KeySetInformationNmentor, "WebinarNCompetition")
+	return r.listCache.get(ctx, key, webinarNCompetition, func(ctx context.Context) (interface{}, []string, error) {
+		var webinarNCompetition []domain.Information
+		err := r.db.WithContext(ctx).Model(domain.Information{}).Where("category_id IN (?)", r.categoryIdsByKind(ctx, domain.CategoryKindWebinar, domain.CategoryKindCompetition)).Limit(15).Order("created_at desc").Preload("Category").Find(&webinarNCompetition).Error
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 
-		return nil
-	}
+		return webinarNCompetition, []string{TagInformation}, nil
+	})
+}
 
-	err = json.Unmarshal([]byte(stringArticles), articles)
+func (r *InformationRepository) GetInformation(ctx context.Context, information *domain.Information, informationParam domain.InformationParam) error {
+	err := r.db.WithContext(ctx).Preload("Category").First(information, informationParam).Error
 	if err != nil {
 		return err
 	}
+
 	return nil
 }
 
-func (r *InformationRepository) GetWebinarNCompetition(ctx context.Context, webinarNCompetition *[]domain.Information) error {
-	key := fmt.Sprintf(KeySetInformationNmentor, "WebinarNCompetition")
-	stringWebinerNCompetition, err := r.redis.GetRedis(ctx, key)
-	if err != nil {
-		err := r.db.Model(domain.Information{}).Where("category_id IN (?)", []int64{8, 9}).Limit(15).Order("created_at desc").Preload("Category").Find(webinarNCompetition).Error
-		if err != nil {
-			return err
-		}
-
-		byteWebinarNCompetition, err := json.Marshal(webinarNCompetition)
-		if err != nil {
-			return nil
-		}
-
-		err = r.redis.SetRedis(ctx, key, string(byteWebinarNCompetition), 5*time.Minute)
-		if err != nil {
-			return err
-		}
+func (r *InformationRepository) CreateInformation(ctx context.Context, newInformation *domain.Information) error {
+	tx := r.db.WithContext(ctx).Begin()
 
-		return nil
+	err := r.db.WithContext(ctx).Create(newInformation).Error
+	if err != nil {
+		tx.Rollback()
+		return err
 	}
 
-	err = json.Unmarshal([]byte(stringWebinerNCompetition), webinarNCompetition)
+	tx.Commit()
+	return r.cache.InvalidateTags(ctx, TagInformation)
+}
+
+func (r *InformationRepository) UpdateInformation(ctx context.Context, information *domain.InformationUpdate, informationId int) error {
+	err := r.db.WithContext(ctx).Model(domain.Information{}).Where("id = ?", informationId).Updates(information).Error
 	if err != nil {
-		return nil
+		return err
 	}
-	return nil
+
+	return r.cache.InvalidateTags(ctx, TagInformation)
 }
 
-func (r *InformationRepository) GetInformation(information *domain.Information, informationParam domain.InformationParam) error {
-	err := r.db.First(information, informationParam).Error
+func (r *InformationRepository) DeleteInformation(ctx context.Context, informationId int) error {
+	err := r.db.WithContext(ctx).Where("id = ?", informationId).Delete(&domain.Information{}).Error
 	if err != nil {
-		return nil
+		return err
 	}
 
-	return err
+	return r.cache.InvalidateTags(ctx, TagInformation)
 }
 
-func (r *InformationRepository) CreateInformation(newInformation *domain.Information) error {
-	tx := r.db.Begin()
+func (r *InformationRepository) RestoreInformation(ctx context.Context, informationId int) error {
+	result := r.db.WithContext(ctx).Unscoped().Model(&domain.Information{}).Where("id = ? AND deleted_at IS NOT NULL", informationId).Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
 
-	err := r.db.Create(newInformation).Error
-	if err != nil {
-		tx.Rollback()
-		return err
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
 	}
 
-	tx.Commit()
-	return nil
+	return r.cache.InvalidateTags(ctx, TagInformation)
 }
 
-func (r *InformationRepository) UpdateInformation(information *domain.InformationUpdate, informationId int) error {
-	err := r.db.Model(domain.Information{}).Where("id = ?", informationId).Updates(information).Error
+func (r *InformationRepository) GetDeletedInformation(ctx context.Context, information *[]domain.Information) error {
+	err := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(information).Error
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (r *InformationRepository) categoryIdsByKind(ctx context.Context, kinds ...string) *gorm.DB {
+	return r.db.WithContext(ctx).Model(&domain.Categories{}).Select("id").Where("kind IN (?)", kinds)
+}
@@ -2,34 +2,36 @@ package repository
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"intern-bcc/domain"
-	"intern-bcc/pkg/redis"
-	"time"
+	"intern-bcc/pkg/cache"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
 type IMentorRepository interface {
-	GetMentor(mentor *domain.Mentors, mentorParam domain.MentorParam) error
+	GetMentor(ctx context.Context, mentor *domain.Mentors, mentorParam domain.MentorParam) error
 	GetMentors(ctx context.Context, mentors *[]domain.Mentors) error
-	CreateMentor(newMentor *domain.Mentors) error
-	UpdateMentor(mentor *domain.MentorUpdate, mentorId uuid.UUID) error
+	CreateMentor(ctx context.Context, newMentor *domain.Mentors) error
+	UpdateMentor(ctx context.Context, mentor *domain.MentorUpdate, mentorId uuid.UUID) error
+	DeleteMentor(ctx context.Context, mentorId uuid.UUID) error
+	RestoreMentor(ctx context.Context, mentorId uuid.UUID) error
+	GetDeletedMentors(ctx context.Context, mentors *[]domain.Mentors) error
 }
 
 type MentorRepository struct {
-	db    *gorm.DB
-	redis redis.IRedis
+	db        *gorm.DB
+	cache     cache.ICache
+	listCache *listCache
 }
 
-func NewMentorRepository(db *gorm.DB, redis redis.IRedis) IMentorRepository {
-	return &MentorRepository{db, redis}
+func NewMentorRepository(db *gorm.DB, cache cache.ICache) IMentorRepository {
+	return &MentorRepository{db, cache, newListCache("mentors", cache)}
 }
 
-func (r *MentorRepository) GetMentor(mentor *domain.Mentors, mentorParam domain.MentorParam) error {
-	err := r.db.Preload("Experiences", func(db *gorm.DB) *gorm.DB {
+func (r *MentorRepository) GetMentor(ctx context.Context, mentor *domain.Mentors, mentorParam domain.MentorParam) error {
+	err := r.db.WithContext(ctx).Preload("Experiences", func(db *gorm.DB) *gorm.DB {
 		return db.Order("created_at DESC")
 	}, func(db *gorm.DB) *gorm.DB {
 		return db.Limit(3)
@@ -43,45 +45,59 @@ func (r *MentorRepository) GetMentor(mentor *domain.Mentors, mentorParam domain.
 
 func (r *MentorRepository) GetMentors(ctx context.Context, mentors *[]domain.Mentors) error {
 	key := fmt.Sprintf(KeySetInformationNmentor, "Mentors")
-	stringMentors, err := r.redis.GetRedis(ctx, key)
-	if err != nil {
-		err = r.db.Limit(15).Order("created_at desc").Find(mentors).Error
+	return r.listCache.get(ctx, key, mentors, func(ctx context.Context) (interface{}, []string, error) {
+		var mentors []domain.Mentors
+		err := r.db.WithContext(ctx).Limit(15).Order("created_at desc").Find(&mentors).Error
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 
-		byteMentors, err := json.Marshal(mentors)
-		if err != nil {
-			return err
-		}
-
-		err = r.redis.SetRedis(ctx, key, string(byteMentors), 5*time.Minute)
-		if err != nil {
-			return err
-		}
+		return mentors, []string{TagMentors}, nil
+	})
+}
 
-		return nil
+func (r *MentorRepository) CreateMentor(ctx context.Context, newMentor *domain.Mentors) error {
+	err := r.db.WithContext(ctx).Create(newMentor).Error
+	if err != nil {
+		return err
 	}
 
-	err = json.Unmarshal([]byte(stringMentors), mentors)
+	return r.cache.InvalidateTags(ctx, TagMentors)
+}
+
+func (r *MentorRepository) UpdateMentor(ctx context.Context, mentor *domain.MentorUpdate, mentorId uuid.UUID) error {
+	err := r.db.WithContext(ctx).Model(domain.Mentors{}).Where("id = ?", mentorId).Updates(mentor).Error
 	if err != nil {
-		return nil
+		return err
 	}
 
-	return nil
+	return r.cache.InvalidateTags(ctx, TagMentors)
 }
 
-func (r *MentorRepository) CreateMentor(newMentor *domain.Mentors) error {
-	err := r.db.Create(newMentor).Error
+func (r *MentorRepository) DeleteMentor(ctx context.Context, mentorId uuid.UUID) error {
+	err := r.db.WithContext(ctx).Where("id = ?", mentorId).Delete(&domain.Mentors{}).Error
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return r.cache.InvalidateTags(ctx, TagMentors)
+}
+
+func (r *MentorRepository) RestoreMentor(ctx context.Context, mentorId uuid.UUID) error {
+	result := r.db.WithContext(ctx).Unscoped().Model(&domain.Mentors{}).Where("id = ? AND deleted_at IS NOT NULL", mentorId).Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return r.cache.InvalidateTags(ctx, TagMentors)
 }
 
-func (r *MentorRepository) UpdateMentor(mentor *domain.MentorUpdate, mentorId uuid.UUID) error {
-	err := r.db.Model(domain.Mentors{}).Where("id = ?", mentorId).Updates(mentor).Error
+func (r *MentorRepository) GetDeletedMentors(ctx context.Context, mentors *[]domain.Mentors) error {
+	err := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(mentors).Error
 	if err != nil {
 		return err
 	}
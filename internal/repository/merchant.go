@@ -2,9 +2,11 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"intern-bcc/domain"
-	"intern-bcc/pkg/redis"
+	"intern-bcc/pkg/cache"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,24 +14,32 @@ import (
 )
 
 type IMerchantRepository interface {
-	GetMerchant(merchant *domain.Merchants, param domain.MerchantParam) error
-	CreateMerchant(newMerchant *domain.Merchants) error
-	UpdateMerchant(updateMerchant *domain.UpdateMerchant, merchantId uuid.UUID) error
+	GetMerchant(ctx context.Context, merchant *domain.Merchants, param domain.MerchantParam) error
+	CreateMerchant(ctx context.Context, newMerchant *domain.Merchants) error
+	UpdateMerchant(ctx context.Context, updateMerchant *domain.UpdateMerchant, merchantId uuid.UUID) error
 	CreateOTP(ctx context.Context, id uuid.UUID, otp string) error
 	GetOTP(ctx context.Context, userId uuid.UUID) (string, error)
+	DeleteOTP(ctx context.Context, userId uuid.UUID) error
+	GetOTPAttempt(ctx context.Context, userId uuid.UUID) (int64, error)
+	IncrOTPAttempt(ctx context.Context, userId uuid.UUID, lockout time.Duration) (int64, error)
+	DeleteOTPAttempt(ctx context.Context, userId uuid.UUID) error
+	GetDeletedMerchant(ctx context.Context, merchant *domain.Merchants, param domain.MerchantParam) error
+	GetDeletedMerchants(ctx context.Context, merchants *[]domain.Merchants) error
+	DeleteMerchant(ctx context.Context, merchantId uuid.UUID) error
+	RestoreMerchant(ctx context.Context, merchantId uuid.UUID) error
 }
 
 type MerchantRepository struct {
 	db    *gorm.DB
-	redis redis.IRedis
+	cache cache.ICache
 }
 
-func NewMerchantRepository(db *gorm.DB, redis redis.IRedis) IMerchantRepository {
-	return &MerchantRepository{db, redis}
+func NewMerchantRepository(db *gorm.DB, cache cache.ICache) IMerchantRepository {
+	return &MerchantRepository{db, cache}
 }
 
-func (r *MerchantRepository) GetMerchant(merchant *domain.Merchants, param domain.MerchantParam) error {
-	err := r.db.Preload("University").Preload("Province").First(merchant, param).Error
+func (r *MerchantRepository) GetMerchant(ctx context.Context, merchant *domain.Merchants, param domain.MerchantParam) error {
+	err := r.db.WithContext(ctx).Preload("University").Preload("Province").First(merchant, param).Error
 	if err != nil {
 		return err
 	}
@@ -37,8 +47,8 @@ func (r *MerchantRepository) GetMerchant(merchant *domain.Merchants, param domai
 	return nil
 }
 
-func (r *MerchantRepository) CreateMerchant(newMerchant *domain.Merchants) error {
-	err := r.db.Create(newMerchant).Error
+func (r *MerchantRepository) CreateMerchant(ctx context.Context, newMerchant *domain.Merchants) error {
+	err := r.db.WithContext(ctx).Create(newMerchant).Error
 	if err != nil {
 		return err
 	}
@@ -46,18 +56,23 @@ func (r *MerchantRepository) CreateMerchant(newMerchant *domain.Merchants) error
 	return nil
 }
 
-func (r *MerchantRepository) UpdateMerchant(updateMerchant *domain.UpdateMerchant, merchantId uuid.UUID) error {
-	err := r.db.Model(domain.Merchants{}).Where("id = ?", merchantId).Updates(updateMerchant).Error
+func (r *MerchantRepository) UpdateMerchant(ctx context.Context, updateMerchant *domain.UpdateMerchant, merchantId uuid.UUID) error {
+	err := r.db.WithContext(ctx).Model(domain.Merchants{}).Where("id = ?", merchantId).Updates(updateMerchant).Error
 	if err != nil {
 		return err
 	}
 
-	return nil
+	tags := []string{fmt.Sprintf(TagMerchant, merchantId)}
+	if updateMerchant.Latitude != nil || updateMerchant.UniversityId != 0 {
+		tags = append(tags, TagProducts)
+	}
+
+	return r.cache.InvalidateTags(ctx, tags...)
 }
 
 func (r *MerchantRepository) CreateOTP(ctx context.Context, userId uuid.UUID, otp string) error{
 	key := fmt.Sprintf(KeySetOtp, userId)
-	err := r.redis.SetRedis(ctx, key, otp, 2*time.Minute)
+	err := r.cache.Set(ctx, key, otp, 2*time.Minute)
 	if err != nil {
 		return err
 	}
@@ -67,10 +82,116 @@ func (r *MerchantRepository) CreateOTP(ctx context.Context, userId uuid.UUID, ot
 
 func (r *MerchantRepository) GetOTP(ctx context.Context, userId uuid.UUID) (string, error) {
 	key := fmt.Sprintf(KeySetOtp, userId)
-	otpString, err := r.redis.GetRedis(ctx, key)
+	otpString, err := r.cache.Get(ctx, key)
 	if err != nil {
 		return "", err
 	}
 
 	return otpString, nil
 }
+
+func (r *MerchantRepository) DeleteOTP(ctx context.Context, userId uuid.UUID) error {
+	key := fmt.Sprintf(KeySetOtp, userId)
+	err := r.cache.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *MerchantRepository) GetOTPAttempt(ctx context.Context, userId uuid.UUID) (int64, error) {
+	key := fmt.Sprintf(KeySetOtpAttempt, userId)
+	attemptString, err := r.cache.Get(ctx, key)
+	if errors.Is(err, cache.ErrMiss) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	attempt, err := strconv.ParseInt(attemptString, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return attempt, nil
+}
+
+func (r *MerchantRepository) IncrOTPAttempt(ctx context.Context, userId uuid.UUID, lockout time.Duration) (int64, error) {
+	key := fmt.Sprintf(KeySetOtpAttempt, userId)
+	attempt, err := r.cache.Incr(ctx, key, lockout)
+	if err != nil {
+		return 0, err
+	}
+
+	return attempt, nil
+}
+
+func (r *MerchantRepository) DeleteOTPAttempt(ctx context.Context, userId uuid.UUID) error {
+	key := fmt.Sprintf(KeySetOtpAttempt, userId)
+	err := r.cache.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *MerchantRepository) GetDeletedMerchant(ctx context.Context, merchant *domain.Merchants, param domain.MerchantParam) error {
+	err := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(merchant, param).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *MerchantRepository) GetDeletedMerchants(ctx context.Context, merchants *[]domain.Merchants) error {
+	err := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(merchants).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *MerchantRepository) DeleteMerchant(ctx context.Context, merchantId uuid.UUID) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		deletedAt := time.Now()
+
+		err := tx.Model(&domain.Products{}).Where("merchant_id = ?", merchantId).Update("deleted_at", deletedAt).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.Merchants{}).Where("id = ?", merchantId).Update("deleted_at", deletedAt).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts, fmt.Sprintf(TagMerchant, merchantId))
+}
+
+func (r *MerchantRepository) RestoreMerchant(ctx context.Context, merchantId uuid.UUID) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var merchant domain.Merchants
+		err := tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", merchantId).First(&merchant).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Unscoped().Model(&domain.Products{}).Where("merchant_id = ? AND deleted_at = ?", merchantId, merchant.DeletedAt).Update("deleted_at", nil).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Unscoped().Model(&domain.Merchants{}).Where("id = ?", merchantId).Update("deleted_at", nil).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts, fmt.Sprintf(TagMerchant, merchantId))
+}
@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"intern-bcc/domain"
-	"intern-bcc/pkg/redis"
-	"time"
+	"intern-bcc/pkg/cache"
+	"intern-bcc/pkg/geo"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,20 +14,24 @@ import (
 )
 
 type IProductRepository interface {
-	GetProduct(product *domain.Products, productParam domain.ProductParam) error
+	GetProduct(ctx context.Context, product *domain.Products, productParam domain.ProductParam) error
 	GetProducts(c *gin.Context, ctx context.Context, product *[]domain.Products, productParam domain.ProductParam) error
-	GetTotalProduct(totalProduct *int64) error
-	CreateProduct(newProduct *domain.Products) error
-	UpdateProduct(product *domain.ProductUpdate, productId uuid.UUID) error
+	GetTotalProduct(ctx context.Context, totalProduct *int64) error
+	CreateProduct(ctx context.Context, newProduct *domain.Products) error
+	UpdateProduct(ctx context.Context, product *domain.ProductUpdate, productId uuid.UUID) error
+	DeleteProduct(ctx context.Context, productId uuid.UUID) error
+	RestoreProduct(ctx context.Context, productId uuid.UUID) error
+	GetDeletedProducts(ctx context.Context, products *[]domain.Products) error
 }
 
 type ProductRepository struct {
-	db    *gorm.DB
-	redis redis.IRedis
+	db        *gorm.DB
+	cache     cache.ICache
+	listCache *listCache
 }
 
-func NewProductRepository(db *gorm.DB, redis redis.IRedis) IProductRepository {
-	return &ProductRepository{db, redis}
+func NewProductRepository(db *gorm.DB, cache cache.ICache) IProductRepository {
+	return &ProductRepository{db, cache, newListCache("products", cache)}
 }
 
 func (r *ProductRepository) GetProducts(c *gin.Context, ctx context.Context, product *[]domain.Products, productParam domain.ProductParam) error {
@@ -37,74 +41,141 @@ func (r *ProductRepository) GetProducts(c *gin.Context, ctx context.Context, pro
 	}
 
 	key := fmt.Sprintf(KeySetProducts, string(byteParam))
-	stringData, err := r.redis.GetRedis(ctx, key)
-	if err != nil {
-		err := r.db.
-			Joins("JOIN merchants ON merchants.id = products.merchant_id").
+	return r.listCache.get(ctx, key, product, func(ctx context.Context) (interface{}, []string, error) {
+		query := r.db.WithContext(ctx).
+			Joins("JOIN merchants ON merchants.id = products.merchant_id AND merchants.deleted_at IS NULL").
 			Joins("JOIN universities ON universities.id = merchants.university_id").
 			Joins("JOIN provinces ON provinces.id = merchants.province_id").
-			Where("IF(? != 0, universities.id = ?, 1) AND IF(? != 0, provinces.id = ?, 1)", productParam.UniversityId, productParam.UniversityId, productParam.ProvinceId, productParam.ProvinceId).
+			Where("IF(? != 0, universities.id = ?, 1) AND IF(? != 0, provinces.id = ?, 1) AND IF(? != 0, merchants.regency_id = ?, 1)", productParam.UniversityId, productParam.UniversityId, productParam.ProvinceId, productParam.ProvinceId, productParam.RegencyId, productParam.RegencyId)
+
+		if productParam.Latitude != nil && productParam.Longitude != nil {
+			query = nearby(query, *productParam.Latitude, *productParam.Longitude, productParam.Radius)
+			if productParam.Sort == domain.ProductSortDistance {
+				query = query.Order("distance")
+			}
+		}
+
+		var products []domain.Products
+		err := query.
 			Limit(Limit).
 			Offset(productParam.Offset).
 			Preload("Merchant.University").
 			Preload("Merchant.Province").
-			Find(&product, productParam).Error
+			Find(&products, productParam).Error
 		if err != nil {
-			return err
+			return nil, nil, err
 		}
 
-		byteProduct, err := json.Marshal(product)
-		if err != nil {
-			return err
+		tags := []string{TagProducts}
+		merchantTags := make(map[uuid.UUID]bool)
+		for _, p := range products {
+			if !merchantTags[p.MerchantId] {
+				merchantTags[p.MerchantId] = true
+				tags = append(tags, fmt.Sprintf(TagMerchant, p.MerchantId))
+			}
 		}
 
-		err = r.redis.SetRedis(ctx, key, string(byteProduct), 5*time.Minute)
-		if err != nil {
-			return err
-		}
+		return products, tags, nil
+	})
+}
 
-		return nil
+func (r *ProductRepository) GetProduct(ctx context.Context, product *domain.Products, productParam domain.ProductParam) error {
+	err := r.db.WithContext(ctx).Preload("Category").Preload("Merchant.University").Preload("Merchant.Province").
+		Preload("Photos", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, created_at")
+		}).
+		First(product, productParam).Error
+	if err != nil {
+		return err
 	}
 
-	err = json.Unmarshal([]byte(stringData), &product)
+	return nil
+}
+
+func (r *ProductRepository) GetTotalProduct(ctx context.Context, totalProduct *int64) error {
+	err := r.db.WithContext(ctx).Model(domain.Products{}).Count(totalProduct).Error
 	if err != nil {
 		return err
 	}
+
 	return nil
 }
 
-func (r *ProductRepository) GetProduct(product *domain.Products, productParam domain.ProductParam) error {
-	err := r.db.Preload("Category").Preload("Merchant.University").Preload("Merchant.Province").First(product, productParam).Error
+func (r *ProductRepository) CreateProduct(ctx context.Context, newProduct *domain.Products) error {
+	err := r.db.WithContext(ctx).Create(newProduct).Error
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return r.cache.InvalidateTags(ctx, TagProducts)
 }
 
-func (r *ProductRepository) GetTotalProduct(totalProduct *int64) error {
-	err := r.db.Model(domain.Products{}).Count(totalProduct).Error
+func (r *ProductRepository) UpdateProduct(ctx context.Context, product *domain.ProductUpdate, productId uuid.UUID) error {
+	var oldProduct domain.Products
+	err := r.db.WithContext(ctx).Select("merchant_id").First(&oldProduct, "id = ?", productId).Error
 	if err != nil {
 		return err
 	}
 
-	return nil
+	err = r.db.WithContext(ctx).Model(domain.Products{}).Where("id = ?", productId).Updates(product).Error
+	if err != nil {
+		return err
+	}
+
+	tags := []string{fmt.Sprintf(TagMerchant, oldProduct.MerchantId)}
+	if product.Name != "" || product.Category != 0 {
+		tags = append(tags, TagProducts)
+	}
+
+	return r.cache.InvalidateTags(ctx, tags...)
 }
 
-func (r *ProductRepository) CreateProduct(newProduct *domain.Products) error {
-	err := r.db.Create(newProduct).Error
+func (r *ProductRepository) DeleteProduct(ctx context.Context, productId uuid.UUID) error {
+	err := r.db.WithContext(ctx).Where("id = ?", productId).Delete(&domain.Products{}).Error
 	if err != nil {
 		return err
 	}
 
-	return nil
+	return r.cache.InvalidateTags(ctx, TagProducts)
 }
 
-func (r *ProductRepository) UpdateProduct(product *domain.ProductUpdate, productId uuid.UUID) error {
-	err := r.db.Model(domain.Products{}).Where("id = ?", productId).Updates(product).Error
+func (r *ProductRepository) RestoreProduct(ctx context.Context, productId uuid.UUID) error {
+	result := r.db.WithContext(ctx).Unscoped().Model(&domain.Products{}).Where("id = ? AND deleted_at IS NOT NULL", productId).Update("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts)
+}
+
+func (r *ProductRepository) GetDeletedProducts(ctx context.Context, products *[]domain.Products) error {
+	err := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").Order("deleted_at desc").Find(products).Error
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+// nearby keeps products whose merchant lies within radiusKm of the point, using
+// the merchant location or, when it has none, the location of its university.
+// The bounding box compares the raw columns so idx_merchants_location and
+// idx_universities_location can narrow the rows before the distance is computed.
+func nearby(query *gorm.DB, latitude float64, longitude float64, radiusKm float64) *gorm.DB {
+	const merchantLatitude = "COALESCE(merchants.latitude, universities.latitude)"
+	const merchantLongitude = "COALESCE(merchants.longitude, universities.longitude)"
+	distance := fmt.Sprintf("ST_Distance_Sphere(POINT(%v, %v), POINT(?, ?)) / 1000", merchantLongitude, merchantLatitude)
+
+	box := geo.Around(latitude, longitude, radiusKm)
+	return query.
+		Select("products.*, "+distance+" AS distance", longitude, latitude).
+		Where("(merchants.latitude BETWEEN ? AND ? AND merchants.longitude BETWEEN ? AND ?) OR "+
+			"(merchants.latitude IS NULL AND universities.latitude BETWEEN ? AND ? AND universities.longitude BETWEEN ? AND ?)",
+			box.MinLatitude, box.MaxLatitude, box.MinLongitude, box.MaxLongitude,
+			box.MinLatitude, box.MaxLatitude, box.MinLongitude, box.MaxLongitude).
+		Where(distance+" <= ?", longitude, latitude, radiusKm)
+}
@@ -0,0 +1,178 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"intern-bcc/domain"
+	"intern-bcc/pkg/cache"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var ErrPhotoLimitReached = errors.New("photo limit reached")
+
+type IProductPhotoRepository interface {
+	GetPhoto(ctx context.Context, photo *domain.ProductPhotos, productId uuid.UUID, photoId uuid.UUID) error
+	GetPhotos(ctx context.Context, photos *[]domain.ProductPhotos, productId uuid.UUID) error
+	CreatePhoto(ctx context.Context, photo *domain.ProductPhotos, maxPhotos int64) error
+	ReplacePhoto(ctx context.Context, photo *domain.ProductPhotos, image domain.UploadedImage) error
+	ReorderPhotos(ctx context.Context, productId uuid.UUID, photoIds []uuid.UUID) error
+	SetPrimaryPhoto(ctx context.Context, productId uuid.UUID, photoId uuid.UUID) error
+	DeletePhoto(ctx context.Context, photo *domain.ProductPhotos) error
+}
+
+type ProductPhotoRepository struct {
+	db    *gorm.DB
+	cache cache.ICache
+}
+
+func NewProductPhotoRepository(db *gorm.DB, cache cache.ICache) IProductPhotoRepository {
+	return &ProductPhotoRepository{db, cache}
+}
+
+func (r *ProductPhotoRepository) GetPhoto(ctx context.Context, photo *domain.ProductPhotos, productId uuid.UUID, photoId uuid.UUID) error {
+	err := r.db.WithContext(ctx).First(photo, "id = ? AND product_id = ?", photoId, productId).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *ProductPhotoRepository) GetPhotos(ctx context.Context, photos *[]domain.ProductPhotos, productId uuid.UUID) error {
+	err := r.db.WithContext(ctx).Where("product_id = ?", productId).Order("position, created_at").Find(photos).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// CreatePhoto locks the product row while it counts the photos, so concurrent
+// uploads can not push a product past maxPhotos.
+func (r *ProductPhotoRepository) CreatePhoto(ctx context.Context, photo *domain.ProductPhotos, maxPhotos int64) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").First(&domain.Products{}, "id = ?", photo.ProductId).Error
+		if err != nil {
+			return err
+		}
+
+		var total int64
+		err = tx.Model(&domain.ProductPhotos{}).Where("product_id = ?", photo.ProductId).Count(&total).Error
+		if err != nil {
+			return err
+		}
+		if total >= maxPhotos {
+			return ErrPhotoLimitReached
+		}
+
+		err = tx.Model(&domain.ProductPhotos{}).Where("product_id = ?", photo.ProductId).
+			Select("COALESCE(MAX(position) + 1, 0)").Scan(&photo.Position).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Create(photo).Error
+		if err != nil {
+			return err
+		}
+
+		return syncPrimaryPhoto(tx, photo.ProductId)
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts)
+}
+
+func (r *ProductPhotoRepository) ReplacePhoto(ctx context.Context, photo *domain.ProductPhotos, image domain.UploadedImage) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(photo).Updates(map[string]interface{}{
+			"url":       image.Original,
+			"medium":    image.Sizes.Medium,
+			"thumbnail": image.Sizes.Thumbnail,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return syncPrimaryPhoto(tx, photo.ProductId)
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts)
+}
+
+func (r *ProductPhotoRepository) ReorderPhotos(ctx context.Context, productId uuid.UUID, photoIds []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for position, photoId := range photoIds {
+			err := tx.Model(&domain.ProductPhotos{}).Where("id = ? AND product_id = ?", photoId, productId).Update("position", position).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+func (r *ProductPhotoRepository) SetPrimaryPhoto(ctx context.Context, productId uuid.UUID, photoId uuid.UUID) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&domain.ProductPhotos{}).Where("product_id = ?", productId).
+			Update("is_primary", gorm.Expr("id = ?", photoId)).Error
+		if err != nil {
+			return err
+		}
+
+		return syncPrimaryPhoto(tx, productId)
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts)
+}
+
+func (r *ProductPhotoRepository) DeletePhoto(ctx context.Context, photo *domain.ProductPhotos) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Delete(photo).Error
+		if err != nil {
+			return err
+		}
+
+		return syncPrimaryPhoto(tx, photo.ProductId)
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts)
+}
+
+// syncPrimaryPhoto promotes the first photo when a product has photos but no
+// primary one, and mirrors the primary photo to products.product_photo.
+func syncPrimaryPhoto(tx *gorm.DB, productId uuid.UUID) error {
+	var primary domain.ProductPhotos
+	err := tx.Where("product_id = ?", productId).Order("is_primary desc, position, created_at").Limit(1).Find(&primary).Error
+	if err != nil {
+		return err
+	}
+
+	if primary.Id != uuid.Nil && !primary.IsPrimary {
+		err = tx.Model(&primary).Update("is_primary", true).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Model(&domain.Products{}).Where("id = ?", productId).Updates(map[string]interface{}{
+		"product_photo":           primary.Url,
+		"product_photo_medium":    primary.Sizes.Medium,
+		"product_photo_thumbnail": primary.Sizes.Thumbnail,
+	}).Error
+}
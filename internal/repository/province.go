@@ -1,26 +1,48 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"intern-bcc/domain"
+	"intern-bcc/pkg/cache"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+var (
+	ErrProvinceHasMerchants = errors.New("province has merchants")
+	ErrProvinceHasRegencies = errors.New("province has regencies")
 )
 
 type IProvinceRepository interface {
-	GetProvince(province *domain.Province, provinceParam domain.Province) error
-	CreateProvince(province *domain.Province) error
+	GetProvince(ctx context.Context, province *domain.Province, provinceParam domain.Province) error
+	GetProvinces(ctx context.Context, provinces *[]domain.Province) error
+	CreateProvince(ctx context.Context, province *domain.Province) error
+	UpdateProvince(ctx context.Context, province *domain.Province) error
+	DeleteProvince(ctx context.Context, provinceId int) error
 }
 
 type ProvinceRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	cache cache.ICache
+}
+
+func NewProvinceRepository(db *gorm.DB, cache cache.ICache) IProvinceRepository {
+	return &ProvinceRepository{db, cache}
 }
 
-func NewProvinceRepository(db *gorm.DB) IProvinceRepository {
-	return &ProvinceRepository{db}
+func (r *ProvinceRepository) GetProvince(ctx context.Context, province *domain.Province, provinceParam domain.Province) error {
+	err := r.db.WithContext(ctx).First(province, provinceParam).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
 }
 
-func (r *ProvinceRepository) GetProvince(province *domain.Province, provinceParam domain.Province) error {
-	err := r.db.First(province, provinceParam).Error
+func (r *ProvinceRepository) GetProvinces(ctx context.Context, provinces *[]domain.Province) error {
+	err := r.db.WithContext(ctx).Order("province").Find(provinces).Error
 	if err != nil {
 		return err
 	}
@@ -28,11 +50,58 @@ func (r *ProvinceRepository) GetProvince(province *domain.Province, provincePara
 	return nil
 }
 
-func (r *ProvinceRepository) CreateProvince(province *domain.Province) error {
-	err := r.db.Create(province).Error
+func (r *ProvinceRepository) CreateProvince(ctx context.Context, province *domain.Province) error {
+	err := r.db.WithContext(ctx).Create(province).Error
 	if err != nil {
 		return err
 	}
 
 	return nil
 }
+
+func (r *ProvinceRepository) UpdateProvince(ctx context.Context, province *domain.Province) error {
+	err := r.db.WithContext(ctx).Model(&domain.Province{}).Where("id = ?", province.Id).Update("province", province.Province).Error
+	if err != nil {
+		return err
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts)
+}
+
+// DeleteProvince locks the province while it checks for merchants and
+// regencies, so a merchant created in between waits on the foreign key and
+// fails instead of pointing at a deleted province.
+func (r *ProvinceRepository) DeleteProvince(ctx context.Context, provinceId int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var province domain.Province
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&province, provinceId).Error
+		if err != nil {
+			return err
+		}
+
+		var totalMerchant int64
+		err = tx.Unscoped().Model(&domain.Merchants{}).Where("province_id = ?", provinceId).Count(&totalMerchant).Error
+		if err != nil {
+			return err
+		}
+		if totalMerchant > 0 {
+			return ErrProvinceHasMerchants
+		}
+
+		var totalRegency int64
+		err = tx.Model(&domain.Regencies{}).Where("province_id = ?", provinceId).Count(&totalRegency).Error
+		if err != nil {
+			return err
+		}
+		if totalRegency > 0 {
+			return ErrProvinceHasRegencies
+		}
+
+		return tx.Delete(&province).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts)
+}
@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"intern-bcc/domain"
+
+	"gorm.io/gorm"
+)
+
+type IRegionRepository interface {
+	GetRegency(ctx context.Context, regency *domain.Regencies, regencyParam domain.Regencies) error
+	GetRegencies(ctx context.Context, regencies *[]domain.Regencies, provinceId int) error
+	FindRegenciesByName(ctx context.Context, regencies *[]domain.Regencies, provinceId int, names []string) error
+	GetDistrict(ctx context.Context, district *domain.Districts, districtParam domain.Districts) error
+	GetDistricts(ctx context.Context, districts *[]domain.Districts, regencyId int) error
+}
+
+type RegionRepository struct {
+	db *gorm.DB
+}
+
+func NewRegionRepository(db *gorm.DB) IRegionRepository {
+	return &RegionRepository{db}
+}
+
+func (r *RegionRepository) GetRegency(ctx context.Context, regency *domain.Regencies, regencyParam domain.Regencies) error {
+	err := r.db.WithContext(ctx).First(regency, regencyParam).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *RegionRepository) GetRegencies(ctx context.Context, regencies *[]domain.Regencies, provinceId int) error {
+	err := r.db.WithContext(ctx).Where("province_id = ?", provinceId).Order("code").Find(regencies).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *RegionRepository) FindRegenciesByName(ctx context.Context, regencies *[]domain.Regencies, provinceId int, names []string) error {
+	err := r.db.WithContext(ctx).Where("province_id = ? AND name IN (?)", provinceId, names).Order("code").Find(regencies).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *RegionRepository) GetDistrict(ctx context.Context, district *domain.Districts, districtParam domain.Districts) error {
+	err := r.db.WithContext(ctx).First(district, districtParam).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *RegionRepository) GetDistricts(ctx context.Context, districts *[]domain.Districts, regencyId int) error {
+	err := r.db.WithContext(ctx).Where("regency_id = ?", regencyId).Order("code").Find(districts).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -1,58 +1,86 @@
 package repository
 
 import (
-	"intern-bcc/pkg/redis"
+	"intern-bcc/pkg/cache"
 
 	"gorm.io/gorm"
 )
 
 const (
 	KeySetOtp                = "otp:set:id:%v"
+	KeySetOtpAttempt         = "otp:attempt:id:%v"
 	KeySetPasswordRecovery   = "recovery:set:name:%v"
 	KeySetInformationNmentor = "get:all:%v"
 	KeySetProducts           = "get:all:product:%v"
+	KeySetTwoFactorEnroll    = "2fa:enroll:id:%v"
+	KeySetTwoFactorChallenge = "2fa:challenge:%v"
+	KeySetTwoFactorUsedCode  = "2fa:used:id:%v:code:%v"
+	KeySetTwoFactorAttempt   = "2fa:attempt:id:%v"
+	KeySetPendingUpload      = "upload:pending:id:%v"
 	Limit                    = 6
 )
 
+const (
+	TagProducts    = "product"
+	TagMerchant    = "merchant:%v"
+	TagMentors     = "mentor"
+	TagInformation = "information"
+)
+
 type Repository struct {
-	UserRepository        IUserRepository
-	ProductRepository     IProductRepository
-	TransactionRepository ITransactionRepository
-	MerchantSQLRepository IMerchantRepository
-	MentorRepository      IMentorRepository
-	ExperienceRepository  IExperienceRepository
-	CategoryRepository    ICategoryRepository
-	InformationRepository IInformationRepository
-	UniversityRepository  IUniversityRepository
-	ProvinceRepository    IProvinceRepository
+	UserRepository         IUserRepository
+	ProductRepository      IProductRepository
+	ProductPhotoRepository IProductPhotoRepository
+	ImageRepository        IImageRepository
+	TransactionRepository  ITransactionRepository
+	MerchantSQLRepository  IMerchantRepository
+	MentorRepository       IMentorRepository
+	ExperienceRepository   IExperienceRepository
+	CategoryRepository     ICategoryRepository
+	InformationRepository  IInformationRepository
+	UniversityRepository   IUniversityRepository
+	ProvinceRepository     IProvinceRepository
+	RegionRepository       IRegionRepository
+	TwoFactorRepository    ITwoFactorRepository
+	UploadRepository       IUploadRepository
 }
 
 type RepositoryParam struct {
-	Redis redis.IRedis
+	Cache cache.ICache
 }
 
 func NewRepository(db *gorm.DB, repositoryParam RepositoryParam) *Repository {
-	userRepository := NewUserRepository(db, repositoryParam.Redis)
-	productRepository := NewProductRepository(db, repositoryParam.Redis)
+	userRepository := NewUserRepository(db, repositoryParam.Cache)
+	productRepository := NewProductRepository(db, repositoryParam.Cache)
+	productPhotoRepository := NewProductPhotoRepository(db, repositoryParam.Cache)
+	imageRepository := NewImageRepository(db)
 	transactionRepository := NewTransactionRepository(db)
-	merchantSQLRepository := NewMerchantRepository(db, repositoryParam.Redis)
-	mentorRepository := NewMentorRepository(db, repositoryParam.Redis)
+	merchantSQLRepository := NewMerchantRepository(db, repositoryParam.Cache)
+	mentorRepository := NewMentorRepository(db, repositoryParam.Cache)
 	experienceRepository := NewExperienceRepository(db)
-	categoryRepository := NewCategoryRepository(db)
-	informationRepository := NewInformationRepository(db, repositoryParam.Redis)
-	universityRepository := NewUniversityRepository(db)
-	provinceRepository := NewProvinceRepository(db)
+	categoryRepository := NewCategoryRepository(db, repositoryParam.Cache)
+	informationRepository := NewInformationRepository(db, repositoryParam.Cache)
+	universityRepository := NewUniversityRepository(db, repositoryParam.Cache)
+	provinceRepository := NewProvinceRepository(db, repositoryParam.Cache)
+	regionRepository := NewRegionRepository(db)
+	twoFactorRepository := NewTwoFactorRepository(db, repositoryParam.Cache)
+	uploadRepository := NewUploadRepository(repositoryParam.Cache)
 
 	return &Repository{
-		UserRepository:        userRepository,
-		ProductRepository:     productRepository,
-		TransactionRepository: transactionRepository,
-		MerchantSQLRepository: merchantSQLRepository,
-		MentorRepository:      mentorRepository,
-		ExperienceRepository:  experienceRepository,
-		CategoryRepository:    categoryRepository,
-		InformationRepository: informationRepository,
-		UniversityRepository:  universityRepository,
-		ProvinceRepository:    provinceRepository,
+		UserRepository:         userRepository,
+		ProductRepository:      productRepository,
+		ProductPhotoRepository: productPhotoRepository,
+		ImageRepository:        imageRepository,
+		TransactionRepository:  transactionRepository,
+		MerchantSQLRepository:  merchantSQLRepository,
+		MentorRepository:       mentorRepository,
+		ExperienceRepository:   experienceRepository,
+		CategoryRepository:     categoryRepository,
+		InformationRepository:  informationRepository,
+		UniversityRepository:   universityRepository,
+		ProvinceRepository:     provinceRepository,
+		RegionRepository:       regionRepository,
+		TwoFactorRepository:    twoFactorRepository,
+		UploadRepository:       uploadRepository,
 	}
 }
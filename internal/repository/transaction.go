@@ -1,15 +1,16 @@
 package repository
 
 import (
+	"context"
 	"intern-bcc/domain"
 
 	"gorm.io/gorm"
 )
 
 type ITransactionRepository interface {
-	GetTransaction(transaction *domain.Transactions) error
-	CreateTransaction(newTransaction *domain.Transactions) error
-	UpdateTransaction(transaction *domain.Transactions) error
+	GetTransaction(ctx context.Context, transaction *domain.Transactions) error
+	CreateTransaction(ctx context.Context, newTransaction *domain.Transactions) error
+	UpdateTransaction(ctx context.Context, transaction *domain.Transactions) error
 }
 
 type TransactionsRepository struct {
@@ -20,8 +21,8 @@ func NewTransactionRepository(db *gorm.DB) ITransactionRepository {
 	return &TransactionsRepository{db}
 }
 
-func (r *TransactionsRepository) GetTransaction(transaction *domain.Transactions) error {
-	err := r.db.First(transaction, transaction).Error
+func (r *TransactionsRepository) GetTransaction(ctx context.Context, transaction *domain.Transactions) error {
+	err := r.db.WithContext(ctx).First(transaction, transaction).Error
 	if err != nil {
 		return err
 	}
@@ -29,8 +30,8 @@ func (r *TransactionsRepository) GetTransaction(transaction *domain.Transactions
 	return nil
 }
 
-func (r *TransactionsRepository) CreateTransaction(newTransaction *domain.Transactions) error {
-	err := r.db.Create(newTransaction).Error
+func (r *TransactionsRepository) CreateTransaction(ctx context.Context, newTransaction *domain.Transactions) error {
+	err := r.db.WithContext(ctx).Create(newTransaction).Error
 	if err != nil {
 		return err
 	}
@@ -38,8 +39,8 @@ func (r *TransactionsRepository) CreateTransaction(newTransaction *domain.Transa
 	return nil
 }
 
-func (r *TransactionsRepository) UpdateTransaction(transaction *domain.Transactions) error {
-	err := r.db.Save(transaction).Error
+func (r *TransactionsRepository) UpdateTransaction(ctx context.Context, transaction *domain.Transactions) error {
+	err := r.db.WithContext(ctx).Save(transaction).Error
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,233 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"intern-bcc/domain"
+	"intern-bcc/pkg/cache"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type ITwoFactorRepository interface {
+	CreateEnrollment(ctx context.Context, userId uuid.UUID, secret string) error
+	GetEnrollment(ctx context.Context, userId uuid.UUID) (string, error)
+	DeleteEnrollment(ctx context.Context, userId uuid.UUID) error
+	CreateChallenge(ctx context.Context, challenge string, userId uuid.UUID) error
+	GetChallenge(ctx context.Context, challenge string) (string, error)
+	DeleteChallenge(ctx context.Context, challenge string) error
+	MarkCodeUsed(ctx context.Context, userId uuid.UUID, code string) (bool, error)
+	GetAttempt(ctx context.Context, userId uuid.UUID) (int64, error)
+	IncrAttempt(ctx context.Context, userId uuid.UUID, lockout time.Duration) (int64, error)
+	DeleteAttempt(ctx context.Context, userId uuid.UUID) error
+	EnableTwoFactor(ctx context.Context, userId uuid.UUID, secret string, recoveryCodes []domain.RecoveryCodes) error
+	DisableTwoFactor(ctx context.Context, userId uuid.UUID) error
+	ReplaceRecoveryCodes(ctx context.Context, userId uuid.UUID, recoveryCodes []domain.RecoveryCodes) error
+	GetRecoveryCodes(ctx context.Context, recoveryCodes *[]domain.RecoveryCodes, userId uuid.UUID) error
+	DeleteRecoveryCode(ctx context.Context, recoveryCodeId int) error
+}
+
+type TwoFactorRepository struct {
+	db    *gorm.DB
+	cache cache.ICache
+}
+
+func NewTwoFactorRepository(db *gorm.DB, cache cache.ICache) ITwoFactorRepository {
+	return &TwoFactorRepository{db, cache}
+}
+
+func (r *TwoFactorRepository) CreateEnrollment(ctx context.Context, userId uuid.UUID, secret string) error {
+	key := fmt.Sprintf(KeySetTwoFactorEnroll, userId)
+	err := r.cache.Set(ctx, key, secret, 10*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) GetEnrollment(ctx context.Context, userId uuid.UUID) (string, error) {
+	key := fmt.Sprintf(KeySetTwoFactorEnroll, userId)
+	secret, err := r.cache.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	return secret, nil
+}
+
+func (r *TwoFactorRepository) DeleteEnrollment(ctx context.Context, userId uuid.UUID) error {
+	key := fmt.Sprintf(KeySetTwoFactorEnroll, userId)
+	err := r.cache.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) CreateChallenge(ctx context.Context, challenge string, userId uuid.UUID) error {
+	key := fmt.Sprintf(KeySetTwoFactorChallenge, challenge)
+	err := r.cache.Set(ctx, key, userId.String(), 5*time.Minute)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) GetChallenge(ctx context.Context, challenge string) (string, error) {
+	key := fmt.Sprintf(KeySetTwoFactorChallenge, challenge)
+	userId, err := r.cache.Get(ctx, key)
+	if err != nil {
+		return "", err
+	}
+
+	return userId, nil
+}
+
+func (r *TwoFactorRepository) DeleteChallenge(ctx context.Context, challenge string) error {
+	key := fmt.Sprintf(KeySetTwoFactorChallenge, challenge)
+	err := r.cache.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// MarkCodeUsed records code as used and reports false when it already was,
+// so two requests racing with the same code cannot both pass.
+func (r *TwoFactorRepository) MarkCodeUsed(ctx context.Context, userId uuid.UUID, code string) (bool, error) {
+	key := fmt.Sprintf(KeySetTwoFactorUsedCode, userId, code)
+	marked, err := r.cache.SetNX(ctx, key, "1", 90*time.Second)
+	if err != nil {
+		return false, err
+	}
+
+	return marked, nil
+}
+
+func (r *TwoFactorRepository) GetAttempt(ctx context.Context, userId uuid.UUID) (int64, error) {
+	key := fmt.Sprintf(KeySetTwoFactorAttempt, userId)
+	attemptString, err := r.cache.Get(ctx, key)
+	if errors.Is(err, cache.ErrMiss) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	attempt, err := strconv.ParseInt(attemptString, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	return attempt, nil
+}
+
+func (r *TwoFactorRepository) IncrAttempt(ctx context.Context, userId uuid.UUID, lockout time.Duration) (int64, error) {
+	key := fmt.Sprintf(KeySetTwoFactorAttempt, userId)
+	attempt, err := r.cache.Incr(ctx, key, lockout)
+	if err != nil {
+		return 0, err
+	}
+
+	return attempt, nil
+}
+
+func (r *TwoFactorRepository) DeleteAttempt(ctx context.Context, userId uuid.UUID) error {
+	key := fmt.Sprintf(KeySetTwoFactorAttempt, userId)
+	err := r.cache.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) EnableTwoFactor(ctx context.Context, userId uuid.UUID, secret string, recoveryCodes []domain.RecoveryCodes) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(domain.Users{}).Where("id = ?", userId).Updates(map[string]interface{}{
+			"two_factor":     true,
+			"two_factor_key": secret,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", userId).Delete(&domain.RecoveryCodes{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&recoveryCodes).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) DisableTwoFactor(ctx context.Context, userId uuid.UUID) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(domain.Users{}).Where("id = ?", userId).Updates(map[string]interface{}{
+			"two_factor":     false,
+			"two_factor_key": "",
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Where("user_id = ?", userId).Delete(&domain.RecoveryCodes{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) ReplaceRecoveryCodes(ctx context.Context, userId uuid.UUID, recoveryCodes []domain.RecoveryCodes) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("user_id = ?", userId).Delete(&domain.RecoveryCodes{}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&recoveryCodes).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *TwoFactorRepository) GetRecoveryCodes(ctx context.Context, recoveryCodes *[]domain.RecoveryCodes, userId uuid.UUID) error {
+	err := r.db.WithContext(ctx).Where("user_id = ?", userId).Find(recoveryCodes).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// DeleteRecoveryCode returns gorm.ErrRecordNotFound when the code is already
+// gone, which means a concurrent request used it first.
+func (r *TwoFactorRepository) DeleteRecoveryCode(ctx context.Context, recoveryCodeId int) error {
+	result := r.db.WithContext(ctx).Delete(&domain.RecoveryCodes{}, recoveryCodeId)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return nil
+}
@@ -1,26 +1,38 @@
 package repository
 
 import (
+	"context"
+	"errors"
 	"intern-bcc/domain"
+	"intern-bcc/pkg/cache"
+	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
-type IUniversityRepository interface{
-	GetUniversity(university *domain.Universities, universityParam domain.Universities) error
-	CreateUniversity(university *domain.Universities) error
+var ErrUniversityHasMerchants = errors.New("university has merchants")
+
+type IUniversityRepository interface {
+	GetUniversity(ctx context.Context, university *domain.Universities, universityParam domain.Universities) error
+	GetUniversities(ctx context.Context, universities *[]domain.Universities, universityParam domain.UniversityParam) error
+	CreateUniversity(ctx context.Context, university *domain.Universities) error
+	ImportUniversities(ctx context.Context, names []string) (int, error)
+	UpdateUniversity(ctx context.Context, university *domain.Universities) error
+	DeleteUniversity(ctx context.Context, universityId int) error
 }
 
 type UniversityRepository struct {
-	db *gorm.DB
+	db    *gorm.DB
+	cache cache.ICache
 }
 
-func NewUniversityRepository(db *gorm.DB) IUniversityRepository {
-	return &UniversityRepository{db}
+func NewUniversityRepository(db *gorm.DB, cache cache.ICache) IUniversityRepository {
+	return &UniversityRepository{db, cache}
 }
 
-func (r *UniversityRepository) GetUniversity(university *domain.Universities, universityParam domain.Universities) error {
-	err := r.db.First(university, universityParam).Error
+func (r *UniversityRepository) GetUniversity(ctx context.Context, university *domain.Universities, universityParam domain.Universities) error {
+	err := r.db.WithContext(ctx).First(university, universityParam).Error
 	if err != nil {
 		return err
 	}
@@ -28,11 +40,111 @@ func (r *UniversityRepository) GetUniversity(university *domain.Universities, un
 	return nil
 }
 
-func (r *UniversityRepository) CreateUniversity(university *domain.Universities) error{
-	err := r.db.Create(university).Error
+func (r *UniversityRepository) GetUniversities(ctx context.Context, universities *[]domain.Universities, universityParam domain.UniversityParam) error {
+	query := r.db.WithContext(ctx).Model(&domain.Universities{})
+	if universityParam.Search != "" {
+		query = query.Where("university LIKE ?", escapeLike(universityParam.Search)+"%")
+	}
+	if universityParam.Limit > 0 {
+		query = query.Limit(universityParam.Limit)
+	}
+
+	err := query.Order("university").Find(universities).Error
 	if err != nil {
 		return err
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+func (r *UniversityRepository) CreateUniversity(ctx context.Context, university *domain.Universities) error {
+	err := r.db.WithContext(ctx).Create(university).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *UniversityRepository) ImportUniversities(ctx context.Context, names []string) (int, error) {
+	var created int
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []string
+		err := tx.Model(&domain.Universities{}).Where("university IN (?)", names).Pluck("university", &existing).Error
+		if err != nil {
+			return err
+		}
+
+		known := make(map[string]bool, len(existing))
+		for _, name := range existing {
+			known[strings.ToLower(name)] = true
+		}
+
+		var universities []domain.Universities
+		for _, name := range names {
+			if known[strings.ToLower(name)] {
+				continue
+			}
+			universities = append(universities, domain.Universities{University: name})
+		}
+
+		if len(universities) == 0 {
+			return nil
+		}
+
+		err = tx.CreateInBatches(&universities, 500).Error
+		if err != nil {
+			return err
+		}
+
+		created = len(universities)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return created, nil
+}
+
+func (r *UniversityRepository) UpdateUniversity(ctx context.Context, university *domain.Universities) error {
+	err := r.db.WithContext(ctx).Model(&domain.Universities{}).Where("id = ?", university.Id).Select("university", "latitude", "longitude").Updates(university).Error
+	if err != nil {
+		return err
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts)
+}
+
+// DeleteUniversity locks the university while it checks for merchants, so a
+// merchant created in between waits on the foreign key and fails instead of
+// pointing at a deleted university.
+func (r *UniversityRepository) DeleteUniversity(ctx context.Context, universityId int) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var university domain.Universities
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&university, universityId).Error
+		if err != nil {
+			return err
+		}
+
+		var totalMerchant int64
+		err = tx.Unscoped().Model(&domain.Merchants{}).Where("university_id = ?", universityId).Count(&totalMerchant).Error
+		if err != nil {
+			return err
+		}
+		if totalMerchant > 0 {
+			return ErrUniversityHasMerchants
+		}
+
+		return tx.Delete(&university).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	return r.cache.InvalidateTags(ctx, TagProducts)
+}
+
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
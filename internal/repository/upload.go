@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"intern-bcc/domain"
+	"intern-bcc/pkg/cache"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type IUploadRepository interface {
+	CreatePendingUpload(ctx context.Context, upload domain.PendingUpload, ttl time.Duration) error
+	GetPendingUpload(ctx context.Context, upload *domain.PendingUpload, uploadId uuid.UUID) error
+	DeletePendingUpload(ctx context.Context, uploadId uuid.UUID) error
+}
+
+type UploadRepository struct {
+	cache cache.ICache
+}
+
+func NewUploadRepository(cache cache.ICache) IUploadRepository {
+	return &UploadRepository{cache}
+}
+
+func (r *UploadRepository) CreatePendingUpload(ctx context.Context, upload domain.PendingUpload, ttl time.Duration) error {
+	data, err := json.Marshal(upload)
+	if err != nil {
+		return err
+	}
+
+	key := fmt.Sprintf(KeySetPendingUpload, upload.Id)
+	err = r.cache.Set(ctx, key, string(data), ttl)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *UploadRepository) GetPendingUpload(ctx context.Context, upload *domain.PendingUpload, uploadId uuid.UUID) error {
+	key := fmt.Sprintf(KeySetPendingUpload, uploadId)
+	data, err := r.cache.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal([]byte(data), upload)
+}
+
+func (r *UploadRepository) DeletePendingUpload(ctx context.Context, uploadId uuid.UUID) error {
+	key := fmt.Sprintf(KeySetPendingUpload, uploadId)
+	err := r.cache.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -4,7 +4,7 @@ import (
 	"context"
 	"fmt"
 	"intern-bcc/domain"
-	"intern-bcc/pkg/redis"
+	"intern-bcc/pkg/cache"
 	"time"
 
 	"github.com/google/uuid"
@@ -12,31 +12,34 @@ import (
 )
 
 type IUserRepository interface {
-	GetUser(user *domain.Users, param domain.UserParam) error
-	GetLikeProduct(likedProduct *domain.LikeProduct, likeProductParam domain.LikeProduct) error
-	GetLikeProducts(user *domain.Users, userId uuid.UUID) error
-	GetOwnProducts(user *domain.Users, userId uuid.UUID) error
-	GetOwnMentors(user *domain.Users, userId uuid.UUID) error
-	Register(newUser *domain.Users) error
-	UpdateUser(userUpdate *domain.UserUpdate, userId uuid.UUID) error
-	LikeProduct(likeProduct *domain.LikeProduct) error
-	DeleteLikeProduct(likedProduct *domain.LikeProduct) error
-	CreateHasMentor(mentor *domain.HasMentor) error
+	GetUser(ctx context.Context, user *domain.Users, param domain.UserParam) error
+	GetLikeProduct(ctx context.Context, likedProduct *domain.LikeProduct, likeProductParam domain.LikeProduct) error
+	GetLikeProducts(ctx context.Context, user *domain.Users, userId uuid.UUID) error
+	GetOwnProducts(ctx context.Context, user *domain.Users, userId uuid.UUID) error
+	GetOwnMentors(ctx context.Context, user *domain.Users, userId uuid.UUID) error
+	Register(ctx context.Context, newUser *domain.Users) error
+	UpdateUser(ctx context.Context, userUpdate *domain.UserUpdate, userId uuid.UUID) error
+	LikeProduct(ctx context.Context, likeProduct *domain.LikeProduct) error
+	DeleteLikeProduct(ctx context.Context, likedProduct *domain.LikeProduct) error
+	CreateHasMentor(ctx context.Context, mentor *domain.HasMentor) error
 	CreatePasswordVerification(ctx context.Context, emailVerHash string, userName string) error
 	GetPasswordVerification(ctx context.Context, userName string) (string, error)
+	DeletePasswordVerification(ctx context.Context, userName string) error
+	GetUserData(ctx context.Context, user *domain.Users, userId uuid.UUID) error
+	DeleteUser(ctx context.Context, user domain.Users) error
 }
 
 type UserRepository struct {
 	db    *gorm.DB
-	redis redis.IRedis
+	cache cache.ICache
 }
 
-func NewUserRepository(db *gorm.DB, redis redis.IRedis) IUserRepository {
-	return &UserRepository{db, redis}
+func NewUserRepository(db *gorm.DB, cache cache.ICache) IUserRepository {
+	return &UserRepository{db, cache}
 }
 
-func (r *UserRepository) GetUser(user *domain.Users, param domain.UserParam) error {
-	err := r.db.First(user, &param).Error
+func (r *UserRepository) GetUser(ctx context.Context, user *domain.Users, param domain.UserParam) error {
+	err := r.db.WithContext(ctx).First(user, &param).Error
 	if err != nil {
 		return err
 	}
@@ -44,8 +47,8 @@ func (r *UserRepository) GetUser(user *domain.Users, param domain.UserParam) err
 	return nil
 }
 
-func (r *UserRepository) GetLikeProduct(likedProduct *domain.LikeProduct, likeProductParam domain.LikeProduct) error {
-	err := r.db.Table("user_like_product").First(likedProduct, likeProductParam).Error
+func (r *UserRepository) GetLikeProduct(ctx context.Context, likedProduct *domain.LikeProduct, likeProductParam domain.LikeProduct) error {
+	err := r.db.WithContext(ctx).Table("user_like_product").First(likedProduct, likeProductParam).Error
 	if err != nil {
 		return err
 	}
@@ -53,8 +56,8 @@ func (r *UserRepository) GetLikeProduct(likedProduct *domain.LikeProduct, likePr
 	return nil
 }
 
-func (r *UserRepository) GetLikeProducts(user *domain.Users, userId uuid.UUID) error {
-	err := r.db.Model(domain.Users{}).Preload("LikeProduct.Merchant.University").Find(user, "id = ?", userId).Error
+func (r *UserRepository) GetLikeProducts(ctx context.Context, user *domain.Users, userId uuid.UUID) error {
+	err := r.db.WithContext(ctx).Model(domain.Users{}).Preload("LikeProduct.Merchant.University").Find(user, "id = ?", userId).Error
 	if err != nil {
 		return err
 	}
@@ -62,8 +65,8 @@ func (r *UserRepository) GetLikeProducts(user *domain.Users, userId uuid.UUID) e
 	return nil
 }
 
-func (r *UserRepository) GetOwnProducts(user *domain.Users, userId uuid.UUID) error {
-	err := r.db.Preload("Merchant.University").Preload("Merchant.Products").Find(user, "id = ?", userId).Error
+func (r *UserRepository) GetOwnProducts(ctx context.Context, user *domain.Users, userId uuid.UUID) error {
+	err := r.db.WithContext(ctx).Preload("Merchant.University").Preload("Merchant.Products").Find(user, "id = ?", userId).Error
 	if err != nil {
 		return err
 	}
@@ -71,8 +74,8 @@ func (r *UserRepository) GetOwnProducts(user *domain.Users, userId uuid.UUID) er
 	return nil
 }
 
-func (r *UserRepository) GetOwnMentors(user *domain.Users, userId uuid.UUID) error {
-	err := r.db.Preload("HasMentors").Find(user, "id = ?", userId).Error
+func (r *UserRepository) GetOwnMentors(ctx context.Context, user *domain.Users, userId uuid.UUID) error {
+	err := r.db.WithContext(ctx).Preload("HasMentors").Find(user, "id = ?", userId).Error
 	if err != nil {
 		return err
 	}
@@ -80,8 +83,8 @@ func (r *UserRepository) GetOwnMentors(user *domain.Users, userId uuid.UUID) err
 	return nil
 }
 
-func (r *UserRepository) Register(newUser *domain.Users) error {
-	err := r.db.Create(newUser).Error
+func (r *UserRepository) Register(ctx context.Context, newUser *domain.Users) error {
+	err := r.db.WithContext(ctx).Create(newUser).Error
 	if err != nil {
 		return err
 	}
@@ -89,9 +92,9 @@ func (r *UserRepository) Register(newUser *domain.Users) error {
 	return nil
 }
 
-func (r *UserRepository) UpdateUser(userUpdate *domain.UserUpdate, userId uuid.UUID) error {
+func (r *UserRepository) UpdateUser(ctx context.Context, userUpdate *domain.UserUpdate, userId uuid.UUID) error {
 	var user domain.Users
-	err := r.db.Model(&user).Where("id = ?", userId).Updates(userUpdate).Error
+	err := r.db.WithContext(ctx).Model(&user).Where("id = ?", userId).Updates(userUpdate).Error
 	if err != nil {
 		return err
 	}
@@ -99,8 +102,8 @@ func (r *UserRepository) UpdateUser(userUpdate *domain.UserUpdate, userId uuid.U
 	return nil
 }
 
-func (r *UserRepository) LikeProduct(likeProduct *domain.LikeProduct) error {
-	err := r.db.Table("user_like_product").Create(likeProduct).Error
+func (r *UserRepository) LikeProduct(ctx context.Context, likeProduct *domain.LikeProduct) error {
+	err := r.db.WithContext(ctx).Table("user_like_product").Create(likeProduct).Error
 	if err != nil {
 		return err
 	}
@@ -108,8 +111,8 @@ func (r *UserRepository) LikeProduct(likeProduct *domain.LikeProduct) error {
 	return nil
 }
 
-func (r *UserRepository) DeleteLikeProduct(likedProduct *domain.LikeProduct) error {
-	err := r.db.Table("user_like_product").Delete(likedProduct, likedProduct).Error
+func (r *UserRepository) DeleteLikeProduct(ctx context.Context, likedProduct *domain.LikeProduct) error {
+	err := r.db.WithContext(ctx).Table("user_like_product").Delete(likedProduct, likedProduct).Error
 	if err != nil {
 		return err
 	}
@@ -117,8 +120,8 @@ func (r *UserRepository) DeleteLikeProduct(likedProduct *domain.LikeProduct) err
 	return nil
 }
 
-func (r *UserRepository) CreateHasMentor(mentor *domain.HasMentor) error {
-	err := r.db.Table("has_mentors").Create(mentor).Error
+func (r *UserRepository) CreateHasMentor(ctx context.Context, mentor *domain.HasMentor) error {
+	err := r.db.WithContext(ctx).Table("has_mentors").Create(mentor).Error
 	if err != nil {
 		return err
 	}
@@ -128,7 +131,7 @@ func (r *UserRepository) CreateHasMentor(mentor *domain.HasMentor) error {
 
 func (r *UserRepository) CreatePasswordVerification(ctx context.Context, emailVerHash string, userName string) error {
 	key := fmt.Sprintf(KeySetPasswordRecovery, userName)
-	err := r.redis.SetRedis(ctx, key, emailVerHash, 2*time.Minute)
+	err := r.cache.Set(ctx, key, emailVerHash, 2*time.Minute)
 	if err != nil {
 		return err
 	}
@@ -138,7 +141,7 @@ func (r *UserRepository) CreatePasswordVerification(ctx context.Context, emailVe
 
 func (r *UserRepository) GetPasswordVerification(ctx context.Context, userName string) (string, error) {
 	key := fmt.Sprintf(KeySetPasswordRecovery, userName)
-	emailVerHash, err := r.redis.GetRedis(ctx, key)
+	emailVerHash, err := r.cache.Get(ctx, key)
 	if err != nil {
 		return "", err
 	}
@@ -146,4 +149,96 @@ func (r *UserRepository) GetPasswordVerification(ctx context.Context, userName s
 	return emailVerHash, nil
 }
 
+func (r *UserRepository) DeletePasswordVerification(ctx context.Context, userName string) error {
+	key := fmt.Sprintf(KeySetPasswordRecovery, userName)
+	err := r.cache.Delete(ctx, key)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *UserRepository) GetUserData(ctx context.Context, user *domain.Users, userId uuid.UUID) error {
+	err := r.db.WithContext(ctx).
+		Preload("LikeProduct.Merchant.University").
+		Preload("Merchant", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Preload("Merchant.University").
+		Preload("Merchant.Province").
+		Preload("Merchant.Products", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Preload("Merchant.Products.Category").
+		Preload("Merchant.Products.Photos").
+		Preload("Transactions.Mentor", func(db *gorm.DB) *gorm.DB {
+			return db.Unscoped()
+		}).
+		Preload("HasMentors").
+		First(user, "id = ?", userId).Error
+	if err != nil {
+		return err
+	}
 
+	return nil
+}
+
+func (r *UserRepository) DeleteUser(ctx context.Context, user domain.Users) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Table("user_like_product").Where("user_id = ?", user.Id).Delete(&domain.LikeProduct{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Table("has_mentors").Where("user_id = ?", user.Id).Delete(&domain.HasMentor{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Where("user_id = ?", user.Id).Delete(&domain.RecoveryCodes{}).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&domain.Transactions{}).Where("user_id = ?", user.Id).Update("user_id", gorm.Expr("NULL")).Error
+		if err != nil {
+			return err
+		}
+
+		if user.Merchant.Id != uuid.Nil {
+			productIds := tx.Unscoped().Model(&domain.Products{}).Select("id").Where("merchant_id = ?", user.Merchant.Id)
+
+			err = tx.Table("user_like_product").Where("product_id IN (?)", productIds).Delete(&domain.LikeProduct{}).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Where("product_id IN (?)", productIds).Delete(&domain.ProductPhotos{}).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Unscoped().Where("merchant_id = ?", user.Merchant.Id).Delete(&domain.Products{}).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Unscoped().Where("id = ?", user.Merchant.Id).Delete(&domain.Merchants{}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Where("id = ?", user.Id).Delete(&domain.Users{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	if user.Merchant.Id != uuid.Nil {
+		return r.cache.InvalidateTags(ctx, TagProducts, fmt.Sprintf(TagMerchant, user.Merchant.Id))
+	}
+
+	return nil
+}
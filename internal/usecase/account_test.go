@@ -0,0 +1,180 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"intern-bcc/domain"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+func (r *fakeUserRepository) GetUserData(ctx context.Context, user *domain.Users, userId uuid.UUID) error {
+	return r.GetUser(ctx, user, domain.UserParam{Id: userId})
+}
+
+func (r *fakeUserRepository) DeleteUser(ctx context.Context, user domain.Users) error {
+	*r.events = append(*r.events, "delete user "+user.Id.String())
+	delete(r.users, user.Id)
+	return nil
+}
+
+type accountFixture struct {
+	user        domain.Users
+	users       *fakeUserRepository
+	jwt         *fakeJwt
+	events      *[]string
+	userUsecase IUserUsecase
+}
+
+// newAccountFixture returns a merchant account with a liked product, a
+// transaction, a mentor and products, one of them in the trash.
+func newAccountFixture(t *testing.T) accountFixture {
+	t.Helper()
+
+	password, err := bcrypt.GenerateFromPassword([]byte("rahasia123"), bcrypt.MinCost)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mentor := domain.Mentors{Id: uuid.New(), Name: "Mentor", CurrentJob: "Engineer"}
+	user := domain.Users{
+		Id:                  uuid.New(),
+		Name:                "Budi",
+		Email:               "budi@example.com",
+		Password:            string(password),
+		TwoFactorKey:        "SECRETKEY",
+		ProfilePicture:      "http://storage/profile.jpg",
+		ProfilePictureSizes: domain.ImageRenditions{Medium: "http://storage/profile-medium.jpg", Thumbnail: "http://storage/profile-thumb.jpg"},
+		LikeProduct:         []domain.Products{{Id: uuid.New(), Name: "Liked"}},
+		Transactions:        []domain.Transactions{{Id: uuid.New(), Mentor: mentor, Price: 50000, IsPayed: true}},
+		HasMentors:          []domain.Mentors{mentor},
+		Merchant: domain.Merchants{
+			Id:            uuid.New(),
+			MerchantName:  "Toko Budi",
+			MerchantPhoto: "http://storage/merchant.jpg",
+			Products: []domain.Products{
+				{
+					Id:     uuid.New(),
+					Name:   "Keripik",
+					Photos: []domain.ProductPhotos{{Url: "http://storage/keripik.jpg"}, {Url: "http://storage/keripik-2.jpg"}},
+				},
+				{
+					Id:           uuid.New(),
+					Name:         "Dihapus",
+					ProductPhoto: "http://storage/old.jpg",
+					DeletedAt:    gorm.DeletedAt{Time: time.Now(), Valid: true},
+				},
+			},
+		},
+	}
+
+	store, images, events := newImageFixture()
+	users := &fakeUserRepository{users: map[uuid.UUID]domain.Users{user.Id: user}, events: events}
+	jwt := &fakeJwt{user: user}
+
+	return accountFixture{
+		user:        user,
+		users:       users,
+		jwt:         jwt,
+		events:      events,
+		userUsecase: NewUserUsecase(users, nil, nil, jwt, images, store, nil, "http://localhost"),
+	}
+}
+
+func TestDeleteAccountChecksThePassword(t *testing.T) {
+	ctx := context.Background()
+	f := newAccountFixture(t)
+
+	err := f.userUsecase.DeleteAccount(nil, ctx, domain.DeleteAccountRequest{Password: "salah"})
+	assertErrorCode(t, err, http.StatusUnauthorized)
+
+	admin := f.user
+	admin.IsAdmin = true
+	f.jwt.user = admin
+	err = f.userUsecase.DeleteAccount(nil, ctx, domain.DeleteAccountRequest{Password: "rahasia123"})
+	assertErrorCode(t, err, http.StatusForbidden)
+
+	if len(*f.events) > 0 || len(f.users.users) != 1 {
+		t.Fatalf("expected the account to be kept, got %v", *f.events)
+	}
+}
+
+func TestDeleteAccountDeletesRecordsBeforeFiles(t *testing.T) {
+	ctx := context.Background()
+	f := newAccountFixture(t)
+
+	err := f.userUsecase.DeleteAccount(nil, ctx, domain.DeleteAccountRequest{Password: "rahasia123"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(f.users.users) != 0 {
+		t.Fatal("expected the user to be deleted")
+	}
+
+	want := []string{
+		"delete user " + f.user.Id.String(),
+		"delete http://storage/profile.jpg",
+		"delete http://storage/profile-medium.jpg",
+		"delete http://storage/profile-thumb.jpg",
+		"delete http://storage/merchant.jpg",
+		"delete http://storage/keripik.jpg",
+		"delete http://storage/keripik-2.jpg",
+		"delete http://storage/old.jpg",
+	}
+	if !slices.Equal(*f.events, want) {
+		t.Fatalf("expected\n%v\ngot\n%v", want, *f.events)
+	}
+}
+
+func TestExportUserDataIncludesEverythingButSecrets(t *testing.T) {
+	ctx := context.Background()
+	f := newAccountFixture(t)
+
+	export, err := f.userUsecase.ExportUserData(nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if export.Profile.Id != f.user.Id || export.Profile.Email != f.user.Email {
+		t.Fatalf("expected the profile of the login user, got %+v", export.Profile)
+	}
+	if export.Merchant == nil || export.Merchant.MerchantName != "Toko Budi" {
+		t.Fatalf("expected the merchant, got %+v", export.Merchant)
+	}
+	if len(export.Products) != 2 || len(export.LikedProducts) != 1 || len(export.Transactions) != 1 || len(export.Mentors) != 1 {
+		t.Fatalf("expected every product, including deleted ones, likes, transactions and mentors, got %+v", export)
+	}
+	if export.Transactions[0].MentorName != "Mentor" {
+		t.Fatalf("expected the transaction to name its mentor, got %+v", export.Transactions[0])
+	}
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, secret := range []string{f.user.Password, f.user.TwoFactorKey} {
+		if strings.Contains(string(data), secret) {
+			t.Fatalf("expected the export not to contain %q", secret)
+		}
+	}
+
+	// An account without a merchant exports no merchant at all.
+	user := f.user
+	user.Merchant = domain.Merchants{}
+	f.users.users[user.Id] = user
+	export, err = f.userUsecase.ExportUserData(nil, ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if export.Merchant != nil || len(export.Products) != 0 {
+		t.Fatalf("expected no merchant, got %+v", export.Merchant)
+	}
+}
@@ -0,0 +1,16 @@
+package usecase
+
+import (
+	"errors"
+	"intern-bcc/pkg/cache"
+	"intern-bcc/pkg/response"
+	"net/http"
+)
+
+func cacheError(err error, code int, message string) error {
+	if errors.Is(err, cache.ErrUnavailable) {
+		return response.NewError(http.StatusServiceUnavailable, "service temporarily unavailable, please try again later", err)
+	}
+
+	return response.NewError(code, message, err)
+}
@@ -1,14 +1,21 @@
 package usecase
 
 import (
+	"context"
+	"errors"
 	"intern-bcc/domain"
 	"intern-bcc/internal/repository"
 	"intern-bcc/pkg/response"
 	"net/http"
+
+	"gorm.io/gorm"
 )
 
 type ICategoryUsecase interface {
-	CreateCategory(categoryRequest domain.Categories) error
+	GetCategories(ctx context.Context, categoryParam domain.CategoryParam) ([]domain.CategoryResponse, error)
+	CreateCategory(ctx context.Context, categoryRequest domain.CategoryRequest) error
+	UpdateCategory(ctx context.Context, categoryId int, categoryUpdate domain.CategoryUpdate) (domain.CategoryResponse, error)
+	DeleteCategory(ctx context.Context, categoryId int) error
 }
 
 type CategoryUsecase struct {
@@ -19,15 +26,131 @@ func NewCategoryUsecase(categoryRepository repository.ICategoryRepository) ICate
 	return &CategoryUsecase{categoryRepository}
 }
 
-func (u *CategoryUsecase) CreateCategory(categoryRequest domain.Categories) error {
+func (u *CategoryUsecase) GetCategories(ctx context.Context, categoryParam domain.CategoryParam) ([]domain.CategoryResponse, error) {
+	var categories []domain.Categories
+	err := u.categoryRepository.GetCategories(ctx, &categories, categoryParam)
+	if err != nil {
+		return nil, response.NewError(http.StatusInternalServerError, "an error occured when get categories", err)
+	}
+
+	categoryResponses := []domain.CategoryResponse{}
+	for _, category := range categories {
+		categoryResponses = append(categoryResponses, categoryResponse(category))
+	}
+
+	return categoryResponses, nil
+}
+
+func (u *CategoryUsecase) CreateCategory(ctx context.Context, categoryRequest domain.CategoryRequest) error {
 	newCategory := domain.Categories{
 		Category: categoryRequest.Category,
+		Kind:     categoryRequest.Kind,
+		ParentId: categoryRequest.ParentId,
+	}
+
+	err := u.validateParent(ctx, newCategory)
+	if err != nil {
+		return err
 	}
 
-	err := u.categoryRepository.CreateCategory(&newCategory)
+	err = u.categoryRepository.CreateCategory(ctx, &newCategory)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when creating category", err)
 	}
 
 	return nil
 }
+
+func (u *CategoryUsecase) UpdateCategory(ctx context.Context, categoryId int, categoryUpdate domain.CategoryUpdate) (domain.CategoryResponse, error) {
+	var category domain.Categories
+	err := u.categoryRepository.GetCategory(ctx, &category, domain.Categories{Id: categoryId})
+	if err != nil {
+		return domain.CategoryResponse{}, response.NewError(http.StatusNotFound, "category not found", err)
+	}
+
+	if categoryUpdate.Category != "" {
+		category.Category = categoryUpdate.Category
+	}
+	if categoryUpdate.RemoveParent && categoryUpdate.ParentId != nil {
+		return domain.CategoryResponse{}, response.NewError(http.StatusBadRequest, "parent_id and remove_parent can not be used together", errors.New("conflicting parent category update"))
+	}
+	if categoryUpdate.ParentId != nil {
+		category.ParentId = categoryUpdate.ParentId
+	}
+	if categoryUpdate.RemoveParent {
+		category.ParentId = nil
+	}
+
+	err = u.validateParent(ctx, category)
+	if err != nil {
+		return domain.CategoryResponse{}, err
+	}
+
+	err = u.categoryRepository.UpdateCategory(ctx, &category)
+	if err != nil {
+		return domain.CategoryResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when update category", err)
+	}
+
+	return categoryResponse(category), nil
+}
+
+func (u *CategoryUsecase) DeleteCategory(ctx context.Context, categoryId int) error {
+	err := u.categoryRepository.DeleteCategory(ctx, categoryId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return response.NewError(http.StatusNotFound, "category not found", err)
+	}
+	if errors.Is(err, repository.ErrCategoryInUse) || errors.Is(err, repository.ErrCategoryHasSubcategories) {
+		return response.NewError(http.StatusConflict, "category is still in use", err)
+	}
+	if err != nil {
+		return response.NewError(http.StatusInternalServerError, "an error occured when delete category", err)
+	}
+
+	return nil
+}
+
+func (u *CategoryUsecase) validateParent(ctx context.Context, category domain.Categories) error {
+	if category.ParentId == nil {
+		return nil
+	}
+
+	if *category.ParentId == category.Id {
+		return response.NewError(http.StatusBadRequest, "category can not be its own parent", errors.New("invalid parent category"))
+	}
+
+	var parent domain.Categories
+	err := u.categoryRepository.GetCategory(ctx, &parent, domain.Categories{Id: *category.ParentId})
+	if err != nil {
+		return response.NewError(http.StatusNotFound, "parent category not found", err)
+	}
+
+	if parent.ParentId != nil {
+		return response.NewError(http.StatusBadRequest, "parent category must be a top level category", errors.New("subcategories can not be nested"))
+	}
+
+	if parent.Kind != category.Kind {
+		return response.NewError(http.StatusBadRequest, "parent category must have the same kind", errors.New("category kind mismatch"))
+	}
+
+	if category.Id != 0 {
+		subcategories, err := u.categoryRepository.CountSubcategories(ctx, category.Id)
+		if err != nil {
+			return response.NewError(http.StatusInternalServerError, "an error occured when validate parent category", err)
+		}
+
+		if subcategories > 0 {
+			return response.NewError(http.StatusBadRequest, "category with subcategories can not have a parent", errors.New("subcategories can not be nested"))
+		}
+	}
+
+	return nil
+}
+
+func categoryResponse(category domain.Categories) domain.CategoryResponse {
+	return domain.CategoryResponse{
+		Id:       category.Id,
+		Category: category.Category,
+		Kind:     category.Kind,
+		ParentId: category.ParentId,
+	}
+}
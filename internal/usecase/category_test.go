@@ -0,0 +1,101 @@
+package usecase
+
+import (
+	"context"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"net/http"
+	"testing"
+
+	"gorm.io/gorm"
+)
+
+type fakeCategoryRepository struct {
+	repository.ICategoryRepository
+	categories map[int]domain.Categories
+	deleteErr  error
+}
+
+func (r *fakeCategoryRepository) GetCategory(ctx context.Context, category *domain.Categories, categoryParam domain.Categories) error {
+	found, ok := r.categories[categoryParam.Id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	*category = found
+	return nil
+}
+
+func (r *fakeCategoryRepository) CountSubcategories(ctx context.Context, categoryId int) (int64, error) {
+	var total int64
+	for _, category := range r.categories {
+		if category.ParentId != nil && *category.ParentId == categoryId {
+			total++
+		}
+	}
+
+	return total, nil
+}
+
+func (r *fakeCategoryRepository) UpdateCategory(ctx context.Context, category *domain.Categories) error {
+	r.categories[category.Id] = *category
+	return nil
+}
+
+func (r *fakeCategoryRepository) DeleteCategory(ctx context.Context, categoryId int) error {
+	return r.deleteErr
+}
+
+func newCategoryFixture() (*fakeCategoryRepository, ICategoryUsecase) {
+	parentId := 1
+	categoryRepository := &fakeCategoryRepository{categories: map[int]domain.Categories{
+		1: {Id: 1, Category: "Food", Kind: domain.CategoryKindProduct},
+		2: {Id: 2, Category: "Snack", Kind: domain.CategoryKindProduct, ParentId: &parentId},
+	}}
+
+	return categoryRepository, NewCategoryUsecase(categoryRepository)
+}
+
+func TestUpdateCategoryKeepsParentWhenOmitted(t *testing.T) {
+	ctx := context.Background()
+	categories, categoryUsecase := newCategoryFixture()
+
+	category, err := categoryUsecase.UpdateCategory(ctx, 2, domain.CategoryUpdate{Category: "Snacks"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if category.ParentId == nil || *category.ParentId != 1 || categories.categories[2].ParentId == nil {
+		t.Fatalf("expected a rename to keep the parent, got %v", category.ParentId)
+	}
+
+	category, err = categoryUsecase.UpdateCategory(ctx, 2, domain.CategoryUpdate{RemoveParent: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if category.ParentId != nil || categories.categories[2].ParentId != nil {
+		t.Fatalf("expected remove_parent to clear the parent, got %v", category.ParentId)
+	}
+
+	parentId := 1
+	_, err = categoryUsecase.UpdateCategory(ctx, 2, domain.CategoryUpdate{ParentId: &parentId, RemoveParent: true})
+	assertErrorCode(t, err, http.StatusBadRequest)
+}
+
+func TestDeleteCategoryInUseIsConflict(t *testing.T) {
+	ctx := context.Background()
+
+	for _, test := range []struct {
+		err  error
+		code int
+	}{
+		{repository.ErrCategoryInUse, http.StatusConflict},
+		{repository.ErrCategoryHasSubcategories, http.StatusConflict},
+		{gorm.ErrRecordNotFound, http.StatusNotFound},
+	} {
+		categories, categoryUsecase := newCategoryFixture()
+		categories.deleteErr = test.err
+
+		err := categoryUsecase.DeleteCategory(ctx, 1)
+		assertErrorCode(t, err, test.code)
+	}
+}
@@ -1,6 +1,7 @@
 package usecase
 
 import (
+	"context"
 	"intern-bcc/domain"
 	"intern-bcc/internal/repository"
 	"intern-bcc/pkg/response"
@@ -8,7 +9,7 @@ import (
 )
 
 type IExperieceUsecase interface {
-	AddExperience(experience domain.Experiences, mentorParam domain.MentorParam) error
+	AddExperience(ctx context.Context, experience domain.Experiences, mentorParam domain.MentorParam) error
 }
 
 type ExperienceUsecase struct {
@@ -19,13 +20,13 @@ func NewExperienceRepository(experienceRepository repository.IExperienceReposito
 	return &ExperienceUsecase{experienceRepository}
 }
 
-func (u *ExperienceUsecase) AddExperience(experienceRequest domain.Experiences, mentorParam domain.MentorParam) error {
+func (u *ExperienceUsecase) AddExperience(ctx context.Context, experienceRequest domain.Experiences, mentorParam domain.MentorParam) error {
 	experience := domain.Experiences{
 		Experience: experienceRequest.Experience,
 		MentorId:   mentorParam.Id,
 	}
 
-	err := u.experienceRepository.AddExperience(&experience)
+	err := u.experienceRepository.AddExperience(ctx, &experience)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when create experience", err)
 	}
@@ -0,0 +1,118 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/imaging"
+	"intern-bcc/pkg/logging"
+	"intern-bcc/pkg/response"
+	"intern-bcc/pkg/storage"
+	"mime/multipart"
+	"net/http"
+	"time"
+)
+
+// imageUploadGrace is how long the files of an upload are kept for it, which
+// covers the time until its record is saved. A file left unused after that is
+// deleted by the upload sweep.
+const imageUploadGrace = time.Hour
+
+// uploadImage validates an uploaded image and stores all of its renditions
+// under content addressed keys. The links are reserved before the files are
+// stored, so deleting the same image for another record cannot remove them
+// before this record is saved. Files of an upload that fails are deleted by
+// the upload sweep once the reservation expires.
+func uploadImage(ctx context.Context, store storage.IStorage, imageRepository repository.IImageRepository, file *multipart.FileHeader) (domain.UploadedImage, error) {
+	processed, err := imaging.Process(file)
+	if err != nil {
+		return domain.UploadedImage{}, imageError(err)
+	}
+
+	renditions := []imaging.Encoded{processed.Original, processed.Medium, processed.Thumbnail}
+	keys := make([]string, len(renditions))
+	links := make([]string, len(renditions))
+	for i, rendition := range renditions {
+		keys[i] = storage.Key(rendition.Data, rendition.Extension)
+		links[i] = store.Link(keys[i])
+	}
+
+	err = imageRepository.CreateUploadReferences(ctx, links)
+	if err != nil {
+		return domain.UploadedImage{}, response.NewError(http.StatusInternalServerError, "failed to upload photo", err)
+	}
+
+	for i, rendition := range renditions {
+		_, err = store.Upload(ctx, keys[i], rendition.ContentType, rendition.Data)
+		if err != nil {
+			return domain.UploadedImage{}, response.NewError(http.StatusInternalServerError, "failed to upload photo", err)
+		}
+	}
+
+	return domain.UploadedImage{
+		Original: links[0],
+		Sizes:    domain.ImageRenditions{Medium: links[1], Thumbnail: links[2]},
+	}, nil
+}
+
+// removeImage deletes the stored files of an image that no record references
+// anymore. A failure only leaves orphaned files, so it is logged instead of
+// returned.
+func removeImage(ctx context.Context, store storage.IStorage, imageRepository repository.IImageRepository, image domain.UploadedImage) {
+	for _, link := range image.Links() {
+		err := deleteUnusedFile(ctx, store, imageRepository, link)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).WithField("photo", link).Error("failed to delete unused photo")
+		}
+	}
+}
+
+// deleteUnusedFile deletes a stored file unless a record or a recent upload
+// still uses it, which happens when the same image was uploaded for several
+// records. The check and the delete run in one transaction.
+func deleteUnusedFile(ctx context.Context, store storage.IStorage, imageRepository repository.IImageRepository, link string) error {
+	err := imageRepository.DeleteUnreferenced(ctx, link, time.Now().Add(-imageUploadGrace), func(ctx context.Context) error {
+		return store.Delete(ctx, link)
+	})
+	if errors.Is(err, repository.ErrImageReferenced) {
+		return nil
+	}
+	if errors.Is(err, storage.ErrNotManaged) {
+		logging.FromContext(ctx).WithField("photo", link).Warn("skipped deleting photo kept by another storage")
+		return nil
+	}
+
+	return err
+}
+
+// sweepImages deletes the files of uploads older than imageUploadGrace that no
+// record ended up using, such as a failed upload or a photo replaced again
+// right after it was uploaded.
+func sweepImages(ctx context.Context, store storage.IStorage, imageRepository repository.IImageRepository) error {
+	links, err := imageRepository.DeleteExpiredUploadReferences(ctx, time.Now().Add(-imageUploadGrace))
+	if err != nil {
+		return err
+	}
+
+	for _, link := range links {
+		err = deleteUnusedFile(ctx, store, imageRepository, link)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).WithField("photo", link).Error("failed to delete unused photo")
+		}
+	}
+
+	return nil
+}
+
+func imageError(err error) error {
+	switch {
+	case errors.Is(err, imaging.ErrTooLarge):
+		return response.NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("photo must be at most %d MB", imaging.MaxUploadSize>>20), err)
+	case errors.Is(err, imaging.ErrUnsupportedType):
+		return response.NewError(http.StatusUnsupportedMediaType, "photo must be a jpeg, png, gif or webp image", err)
+	default:
+		return response.NewError(http.StatusBadRequest, "photo is not a valid image", err)
+	}
+}
@@ -0,0 +1,152 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/png"
+	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/storage"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeStorage records what is stored and deleted, in the order it happened.
+type fakeStorage struct {
+	storage.IStorage
+	mu     sync.Mutex
+	events *[]string
+}
+
+func (s *fakeStorage) Upload(ctx context.Context, key string, contentType string, data []byte) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	*s.events = append(*s.events, "upload "+s.Link(key))
+	return s.Link(key), nil
+}
+
+func (s *fakeStorage) Link(key string) string {
+	return "http://storage/" + key
+}
+
+func (s *fakeStorage) Delete(ctx context.Context, link string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	*s.events = append(*s.events, "delete "+link)
+	return nil
+}
+
+// fakeImageRepository treats every link reserved by an upload as in use until
+// it expires, and nothing else.
+type fakeImageRepository struct {
+	mu       sync.Mutex
+	events   *[]string
+	reserved map[string]time.Time
+}
+
+func (r *fakeImageRepository) CreateUploadReferences(ctx context.Context, links []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, link := range links {
+		*r.events = append(*r.events, "reserve "+link)
+		r.reserved[link] = time.Now()
+	}
+
+	return nil
+}
+
+func (r *fakeImageRepository) DeleteUnreferenced(ctx context.Context, link string, uploadedBefore time.Time, remove func(ctx context.Context) error) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if reservedAt, ok := r.reserved[link]; ok && !reservedAt.Before(uploadedBefore) {
+		return repository.ErrImageReferenced
+	}
+
+	return remove(ctx)
+}
+
+func (r *fakeImageRepository) DeleteExpiredUploadReferences(ctx context.Context, uploadedBefore time.Time) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var links []string
+	for link, reservedAt := range r.reserved {
+		if reservedAt.Before(uploadedBefore) {
+			links = append(links, link)
+			delete(r.reserved, link)
+		}
+	}
+
+	return links, nil
+}
+
+func newImageFixture() (*fakeStorage, *fakeImageRepository, *[]string) {
+	events := &[]string{}
+	return &fakeStorage{events: events}, &fakeImageRepository{events: events, reserved: make(map[string]time.Time)}, events
+}
+
+func testImage(t *testing.T) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 8, 8)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func TestUploadImageReservesLinksBeforeStoring(t *testing.T) {
+	ctx := context.Background()
+	store, images, events := newImageFixture()
+
+	file, err := fileHeader("photo.png", "image/png", testImage(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	uploaded, err := uploadImage(ctx, store, images, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, link := range uploaded.Links() {
+		reserved := slices.Index(*events, "reserve "+link)
+		stored := slices.Index(*events, "upload "+link)
+		if reserved < 0 || stored < 0 || reserved > stored {
+			t.Fatalf("expected %v to be reserved before it is stored, got %v", link, *events)
+		}
+	}
+
+	// Replacing the image right away, as another record with the same
+	// content could, must not delete the files the upload still needs.
+	removeImage(ctx, store, images, uploaded)
+	if slices.ContainsFunc(*events, func(event string) bool { return strings.HasPrefix(event, "delete") }) {
+		t.Fatalf("expected a recent upload to keep its files, got %v", *events)
+	}
+}
+
+func TestSweepImagesDeletesExpiredUnusedUploads(t *testing.T) {
+	ctx := context.Background()
+	store, images, events := newImageFixture()
+
+	images.reserved["http://storage/old.jpg"] = time.Now().Add(-2 * imageUploadGrace)
+	images.reserved["http://storage/new.jpg"] = time.Now()
+
+	err := sweepImages(ctx, store, images)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !slices.Equal(*events, []string{"delete http://storage/old.jpg"}) {
+		t.Fatalf("expected only the expired upload to be deleted, got %v", *events)
+	}
+}
@@ -3,37 +3,38 @@ package usecase
 import (
 	"context"
 	"errors"
-	"fmt"
 	"intern-bcc/domain"
 	"intern-bcc/internal/repository"
 	"intern-bcc/pkg/response"
-	"intern-bcc/pkg/supabase"
+	"intern-bcc/pkg/storage"
 	"mime/multipart"
 	"net/http"
-	"strings"
 	"time"
 )
 
 type IInformationUsecase interface {
 	GetInformations(ctx context.Context) (domain.InformationResponses, error)
-	GetArticle(informationParam domain.InformationParam) (domain.Article, error)
-	CreateInformation(informationRequest domain.InformationRequest) error
-	UpdateInformation(informationParam domain.InformationParam, informationUpdate domain.InformationUpdate) error
-	UploadInformationPhoto(informationParam domain.InformationParam, informationPhoto *multipart.FileHeader) error
+	GetArticle(ctx context.Context, informationParam domain.InformationParam) (domain.Article, error)
+	CreateInformation(ctx context.Context, informationRequest domain.InformationRequest) error
+	UpdateInformation(ctx context.Context, informationParam domain.InformationParam, informationUpdate domain.InformationUpdate) error
+	UploadInformationPhoto(ctx context.Context, informationParam domain.InformationParam, informationPhoto *multipart.FileHeader) error
+	DeleteInformation(ctx context.Context, informationParam domain.InformationParam) error
 }
 
 type InformationUsecase struct {
 	informationRepository repository.IInformationRepository
 	categoryRepository    repository.ICategoryRepository
-	supabase              supabase.ISupabase
+	imageRepository       repository.IImageRepository
+	storage               storage.IStorage
 }
 
 func NewInformatinUsecase(informationRepository repository.IInformationRepository, categoryRepository repository.ICategoryRepository,
-	supabase supabase.ISupabase) IInformationUsecase {
+	imageRepository repository.IImageRepository, storage storage.IStorage) IInformationUsecase {
 	return &InformationUsecase{
 		informationRepository: informationRepository,
 		categoryRepository:    categoryRepository,
-		supabase:              supabase,
+		imageRepository:       imageRepository,
+		storage:               storage,
 	}
 }
 
@@ -57,7 +58,7 @@ func (u *InformationUsecase) GetInformations(ctx context.Context) (domain.Inform
 			Id:               i.Id,
 			Title:            i.Title,
 			Category:         i.Category.Category,
-			InformationPhoto: i.InformationPhoto,
+			InformationPhoto: i.InformationPhotoSizes.Or(i.InformationPhoto).Thumbnail,
 		}
 
 		webinarNCompetitions = append(webinarNCompetitions, webinarnCompetition)
@@ -71,37 +72,38 @@ func (u *InformationUsecase) GetInformations(ctx context.Context) (domain.Inform
 	return informationResponses, nil
 }
 
-func (u *InformationUsecase) GetArticle(informationParam domain.InformationParam) (domain.Article, error) {
+func (u *InformationUsecase) GetArticle(ctx context.Context, informationParam domain.InformationParam) (domain.Article, error) {
 	var information domain.Information
-	err := u.informationRepository.GetInformation(&information, informationParam)
+	err := u.informationRepository.GetInformation(ctx, &information, informationParam)
 	if err != nil {
 		return domain.Article{}, response.NewError(http.StatusInternalServerError, "an error occured when get artivle", err)
 	}
 
-	if information.CategoryId != 7 {
+	if information.Category.Kind != domain.CategoryKindArticle {
 		return domain.Article{}, response.NewError(http.StatusBadRequest, "an error occured when get article", errors.New("category id is not article"))
 	}
 
 	article := domain.Article{
-		Id:               information.Id,
-		Title:            information.Title,
-		Content:          information.Content,
-		InformationPhoto: information.InformationPhoto,
-		CreatedAt:        information.CreatedAt.Format(time.RFC822Z),
-		UpdatedAt:        information.UpdatedAt.Format(time.RFC822Z),
+		Id:                    information.Id,
+		Title:                 information.Title,
+		Content:               information.Content,
+		InformationPhoto:      information.InformationPhoto,
+		InformationPhotoSizes: information.InformationPhotoSizes.Or(information.InformationPhoto),
+		CreatedAt:             information.CreatedAt.Format(time.RFC822Z),
+		UpdatedAt:             information.UpdatedAt.Format(time.RFC822Z),
 	}
 
 	return article, err
 }
 
-func (u *InformationUsecase) CreateInformation(informationRequest domain.InformationRequest) error {
+func (u *InformationUsecase) CreateInformation(ctx context.Context, informationRequest domain.InformationRequest) error {
 	var category domain.Categories
-	err := u.categoryRepository.GetCategory(&category, domain.Categories{Category: informationRequest.Category})
+	err := u.categoryRepository.GetCategory(ctx, &category, domain.Categories{Category: informationRequest.Category})
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "category not found", err)
 	}
 
-	if category.Id < 7 || category.Id > 9 {
+	if !category.IsInformation() {
 		return response.NewError(http.StatusBadRequest, "can not use this category for information", errors.New("can not use product category for information"))
 	}
 
@@ -112,7 +114,7 @@ func (u *InformationUsecase) CreateInformation(informationRequest domain.Informa
 		Content:    informationRequest.Content,
 	}
 
-	err = u.informationRepository.CreateInformation(&newInformation)
+	err = u.informationRepository.CreateInformation(ctx, &newInformation)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when create information", err)
 	}
@@ -120,24 +122,24 @@ func (u *InformationUsecase) CreateInformation(informationRequest domain.Informa
 	return nil
 }
 
-func (u *InformationUsecase) UpdateInformation(informationParam domain.InformationParam, informationUpdate domain.InformationUpdate) error {
+func (u *InformationUsecase) UpdateInformation(ctx context.Context, informationParam domain.InformationParam, informationUpdate domain.InformationUpdate) error {
 	var information domain.Information
-	err := u.informationRepository.GetInformation(&information, informationParam)
+	err := u.informationRepository.GetInformation(ctx, &information, informationParam)
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "an error occured when get information", err)
 	}
 
-	if information.CategoryId != 7 {
+	if information.Category.Kind != domain.CategoryKindArticle {
 		return response.NewError(http.StatusBadRequest, "update failed", errors.New("only can update atricle"))
 	}
 
-	err = u.informationRepository.UpdateInformation(&informationUpdate, information.Id)
+	err = u.informationRepository.UpdateInformation(ctx, &informationUpdate, information.Id)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when update information", err)
 	}
 
 	var updatedInformation domain.Information
-	err = u.informationRepository.GetInformation(&updatedInformation, informationParam)
+	err = u.informationRepository.GetInformation(ctx, &updatedInformation, informationParam)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when get updated information", err)
 	}
@@ -145,38 +147,49 @@ func (u *InformationUsecase) UpdateInformation(informationParam domain.Informati
 	return nil
 }
 
-func (u *InformationUsecase) UploadInformationPhoto(informationParam domain.InformationParam, informationPhoto *multipart.FileHeader) error {
+func (u *InformationUsecase) UploadInformationPhoto(ctx context.Context, informationParam domain.InformationParam, informationPhoto *multipart.FileHeader) error {
 	var information domain.Information
-	err := u.informationRepository.GetInformation(&information, informationParam)
+	err := u.informationRepository.GetInformation(ctx, &information, informationParam)
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "an error occured when get information", err)
 	}
 
-	if information.InformationPhoto != "" {
-		err = u.supabase.Delete(information.InformationPhoto)
-		if err != nil {
-			return response.NewError(http.StatusInternalServerError, "an error occured when deleting old information photo", err)
-		}
-	}
-
-	informationPhoto.Filename = fmt.Sprintf("%v-%v", time.Now().String(), informationPhoto.Filename)
-	informationPhoto.Filename = strings.Replace(informationPhoto.Filename, " ", "-", -1)
-
-	newInformationPhoto, err := u.supabase.Upload(informationPhoto)
+	newInformationPhoto, err := uploadImage(ctx, u.storage, u.imageRepository, informationPhoto)
 	if err != nil {
-		return response.NewError(http.StatusInternalServerError, "an error occured when upload photo", err)
+		return err
 	}
 
-	err = u.informationRepository.UpdateInformation(&domain.InformationUpdate{InformationPhoto: newInformationPhoto}, information.Id)
+	err = u.informationRepository.UpdateInformation(ctx, &domain.InformationUpdate{
+		InformationPhoto:      newInformationPhoto.Original,
+		InformationPhotoSizes: newInformationPhoto.Sizes,
+	}, information.Id)
 	if err != nil {
+		removeImage(ctx, u.storage, u.imageRepository, newInformationPhoto)
 		return response.NewError(http.StatusInternalServerError, "an error occured when update information", err)
 	}
 
+	removeImage(ctx, u.storage, u.imageRepository, domain.UploadedImage{Original: information.InformationPhoto, Sizes: information.InformationPhotoSizes})
+
 	var updatedInformation domain.Information
-	err = u.informationRepository.GetInformation(&updatedInformation, informationParam)
+	err = u.informationRepository.GetInformation(ctx, &updatedInformation, informationParam)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when get updated information", err)
 	}
 
 	return nil
 }
+
+func (u *InformationUsecase) DeleteInformation(ctx context.Context, informationParam domain.InformationParam) error {
+	var information domain.Information
+	err := u.informationRepository.GetInformation(ctx, &information, informationParam)
+	if err != nil {
+		return response.NewError(http.StatusNotFound, "an error occured when get information", err)
+	}
+
+	err = u.informationRepository.DeleteInformation(ctx, information.Id)
+	if err != nil {
+		return response.NewError(http.StatusInternalServerError, "an error occured when delete information", err)
+	}
+
+	return nil
+}
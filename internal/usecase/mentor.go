@@ -2,45 +2,45 @@ package usecase
 
 import (
 	"context"
-	"fmt"
 	"intern-bcc/domain"
 	"intern-bcc/internal/repository"
 	"intern-bcc/pkg/jwt"
 	"intern-bcc/pkg/response"
-	"intern-bcc/pkg/supabase"
+	"intern-bcc/pkg/storage"
 	"mime/multipart"
 	"net/http"
-	"strings"
-	"time"
 
 	"github.com/google/uuid"
 )
 
 type IMentorUsecase interface {
-	GetMentor(mentorParam domain.MentorParam) (domain.MentorResponse, error)
+	GetMentor(ctx context.Context, mentorParam domain.MentorParam) (domain.MentorResponse, error)
 	GetMentors(ctx context.Context) ([]domain.MentorResponses, error)
-	CreateMentor(mentorRequest domain.MentorRequest) error
-	UpdateMentor(mentorParam domain.MentorParam, mentorUpdate domain.MentorUpdate) error
-	UploadMentorPhoto(mentorParam domain.MentorParam, mentorPicture *multipart.FileHeader) error
+	CreateMentor(ctx context.Context, mentorRequest domain.MentorRequest) error
+	UpdateMentor(ctx context.Context, mentorParam domain.MentorParam, mentorUpdate domain.MentorUpdate) error
+	UploadMentorPhoto(ctx context.Context, mentorParam domain.MentorParam, mentorPicture *multipart.FileHeader) error
+	DeleteMentor(ctx context.Context, mentorParam domain.MentorParam) error
 }
 
 type MentorUsecase struct {
 	mentorRepository repository.IMentorRepository
 	jwt              jwt.IJwt
-	supabase         supabase.ISupabase
+	imageRepository  repository.IImageRepository
+	storage          storage.IStorage
 }
 
-func NewMentorUsecase(mentorRepository repository.IMentorRepository, jwt jwt.IJwt, supabase supabase.ISupabase) IMentorUsecase {
+func NewMentorUsecase(mentorRepository repository.IMentorRepository, jwt jwt.IJwt, imageRepository repository.IImageRepository, storage storage.IStorage) IMentorUsecase {
 	return &MentorUsecase{
 		mentorRepository: mentorRepository,
 		jwt:              jwt,
-		supabase:         supabase,
+		imageRepository:  imageRepository,
+		storage:          storage,
 	}
 }
 
-func (u *MentorUsecase) GetMentor(mentorParam domain.MentorParam) (domain.MentorResponse, error) {
+func (u *MentorUsecase) GetMentor(ctx context.Context, mentorParam domain.MentorParam) (domain.MentorResponse, error) {
 	var mentor domain.Mentors
-	err := u.mentorRepository.GetMentor(&mentor, mentorParam)
+	err := u.mentorRepository.GetMentor(ctx, &mentor, mentorParam)
 	if err != nil {
 		return domain.MentorResponse{}, response.NewError(http.StatusNotFound, "an error occured when get mentors", err)
 	}
@@ -79,7 +79,7 @@ func (u *MentorUsecase) GetMentors(ctx context.Context) ([]domain.MentorResponse
 			Name:          m.Name,
 			CurrentJob:    m.CurrentJob,
 			Price:         m.Price,
-			MentorPicture: m.MentorPicture,
+			MentorPicture: m.MentorPictureSizes.Or(m.MentorPicture).Thumbnail,
 		}
 
 		mentorResponses = append(mentorResponses, mentorResponse)
@@ -88,7 +88,7 @@ func (u *MentorUsecase) GetMentors(ctx context.Context) ([]domain.MentorResponse
 	return mentorResponses, nil
 }
 
-func (u *MentorUsecase) CreateMentor(mentorRequest domain.MentorRequest) error {
+func (u *MentorUsecase) CreateMentor(ctx context.Context, mentorRequest domain.MentorRequest) error {
 	newMentor := domain.Mentors{
 		Id:          uuid.New(),
 		Name:        mentorRequest.Name,
@@ -97,7 +97,7 @@ func (u *MentorUsecase) CreateMentor(mentorRequest domain.MentorRequest) error {
 		Price:       mentorRequest.Price,
 	}
 
-	err := u.mentorRepository.CreateMentor(&newMentor)
+	err := u.mentorRepository.CreateMentor(ctx, &newMentor)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when create mentor", err)
 	}
@@ -105,20 +105,20 @@ func (u *MentorUsecase) CreateMentor(mentorRequest domain.MentorRequest) error {
 	return nil
 }
 
-func (u *MentorUsecase) UpdateMentor(mentorParam domain.MentorParam, mentorUpdate domain.MentorUpdate) error {
+func (u *MentorUsecase) UpdateMentor(ctx context.Context, mentorParam domain.MentorParam, mentorUpdate domain.MentorUpdate) error {
 	var mentor domain.Mentors
-	err := u.mentorRepository.GetMentor(&mentor, mentorParam)
+	err := u.mentorRepository.GetMentor(ctx, &mentor, mentorParam)
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "an error occured when get mentor", err)
 	}
 
-	err = u.mentorRepository.UpdateMentor(&mentorUpdate, mentor.Id)
+	err = u.mentorRepository.UpdateMentor(ctx, &mentorUpdate, mentor.Id)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when update mentor", err)
 	}
 
 	var updatedMentor domain.Mentors
-	err = u.mentorRepository.GetMentor(&updatedMentor, mentorParam)
+	err = u.mentorRepository.GetMentor(ctx, &updatedMentor, mentorParam)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when get updated mentor", err)
 	}
@@ -126,38 +126,49 @@ func (u *MentorUsecase) UpdateMentor(mentorParam domain.MentorParam, mentorUpdat
 	return nil
 }
 
-func (u *MentorUsecase) UploadMentorPhoto(mentorParam domain.MentorParam, mentorPicture *multipart.FileHeader) error {
+func (u *MentorUsecase) UploadMentorPhoto(ctx context.Context, mentorParam domain.MentorParam, mentorPicture *multipart.FileHeader) error {
 	var mentor domain.Mentors
-	err := u.mentorRepository.GetMentor(&mentor, mentorParam)
+	err := u.mentorRepository.GetMentor(ctx, &mentor, mentorParam)
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "an error occured when get mentor", err)
 	}
 
-	if mentor.MentorPicture != "" {
-		err = u.supabase.Delete(mentor.MentorPicture)
-		if err != nil {
-			return response.NewError(http.StatusInternalServerError, "an error occured when delete old mentor picture", err)
-		}
-	}
-
-	mentorPicture.Filename = fmt.Sprintf("%v-%v", time.Now().String(), mentorPicture.Filename)
-	mentorPicture.Filename = strings.Replace(mentorPicture.Filename, " ", "-", -1)
-
-	newMentorPicture, err := u.supabase.Upload(mentorPicture)
+	newMentorPicture, err := uploadImage(ctx, u.storage, u.imageRepository, mentorPicture)
 	if err != nil {
-		return response.NewError(http.StatusInternalServerError, "an error occured when upload photo", err)
+		return err
 	}
 
-	err = u.mentorRepository.UpdateMentor(&domain.MentorUpdate{MentorPicture: newMentorPicture}, mentor.Id)
+	err = u.mentorRepository.UpdateMentor(ctx, &domain.MentorUpdate{
+		MentorPicture:      newMentorPicture.Original,
+		MentorPictureSizes: newMentorPicture.Sizes,
+	}, mentor.Id)
 	if err != nil {
+		removeImage(ctx, u.storage, u.imageRepository, newMentorPicture)
 		return response.NewError(http.StatusInternalServerError, "an error occured when update mentor", err)
 	}
 
+	removeImage(ctx, u.storage, u.imageRepository, domain.UploadedImage{Original: mentor.MentorPicture, Sizes: mentor.MentorPictureSizes})
+
 	var updatedMentor domain.Mentors
-	err = u.mentorRepository.GetMentor(&updatedMentor, mentorParam)
+	err = u.mentorRepository.GetMentor(ctx, &updatedMentor, mentorParam)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when get updated mentor", err)
 	}
 
 	return nil
 }
+
+func (u *MentorUsecase) DeleteMentor(ctx context.Context, mentorParam domain.MentorParam) error {
+	var mentor domain.Mentors
+	err := u.mentorRepository.GetMentor(ctx, &mentor, mentorParam)
+	if err != nil {
+		return response.NewError(http.StatusNotFound, "an error occured when get mentor", err)
+	}
+
+	err = u.mentorRepository.DeleteMentor(ctx, mentor.Id)
+	if err != nil {
+		return response.NewError(http.StatusInternalServerError, "an error occured when delete mentor", err)
+	}
+
+	return nil
+}
@@ -2,18 +2,17 @@ package usecase
 
 import (
 	"context"
+	"crypto/subtle"
 	"errors"
-	"fmt"
 	"intern-bcc/domain"
 	"intern-bcc/internal/repository"
 	"intern-bcc/pkg/gomail"
 	"intern-bcc/pkg/jwt"
+	"intern-bcc/pkg/logging"
 	"intern-bcc/pkg/response"
-	"intern-bcc/pkg/supabase"
-	"math/rand"
+	"intern-bcc/pkg/storage"
 	"mime/multipart"
 	"net/http"
-	"strconv"
 	"strings"
 	"time"
 
@@ -21,65 +20,80 @@ import (
 	"github.com/google/uuid"
 )
 
+const (
+	maxOtpAttempt = 5
+	otpLockout    = 15 * time.Minute
+)
+
 type IMerchantUsecase interface {
-	GetMerchant(c *gin.Context) (domain.MerchantProfileResponse, error)
-	CreateMerchant(c *gin.Context, merchantRequest domain.MerchantRequest) error
+	GetMerchant(c *gin.Context, ctx context.Context) (domain.MerchantProfileResponse, error)
+	CreateMerchant(c *gin.Context, ctx context.Context, merchantRequest domain.MerchantRequest) error
 	SendOtp(c *gin.Context, ctx context.Context) error
 	VerifyOtp(c *gin.Context, ctx context.Context, verifyOtp domain.MerchantVerify) error
-	UpdateMerchant(c *gin.Context, merchantId uuid.UUID, updateMerchant domain.UpdateMerchant) (domain.MerchantProfileResponse, error)
-	UploadMerchantPhoto(c *gin.Context, merchantId uuid.UUID, merchantPhoto *multipart.FileHeader) (domain.MerchantProfileResponse, error)
+	UpdateMerchant(c *gin.Context, ctx context.Context, updateMerchant domain.UpdateMerchant) (domain.MerchantProfileResponse, error)
+	UploadMerchantPhoto(c *gin.Context, ctx context.Context, merchantPhoto *multipart.FileHeader) (domain.MerchantProfileResponse, error)
+	DeleteOwnMerchant(c *gin.Context, ctx context.Context) error
+	DeleteMerchant(c *gin.Context, ctx context.Context, merchantId uuid.UUID) error
 }
 
 type MerchantUsecase struct {
 	merchantRepository   repository.IMerchantRepository
 	provinceRepository   repository.IProvinceRepository
 	universityRepository repository.IUniversityRepository
+	regionRepository     repository.IRegionRepository
 	jwt                  jwt.IJwt
 	goMail               gomail.IGoMail
-	supabase             supabase.ISupabase
+	imageRepository      repository.IImageRepository
+	storage              storage.IStorage
 }
 
 func NewMerchantUsecase(merchantRepository repository.IMerchantRepository,
-	jwt jwt.IJwt, goMail gomail.IGoMail, supabase supabase.ISupabase,
-	universityRepository repository.IUniversityRepository, provinceRepository repository.IProvinceRepository) IMerchantUsecase {
+	jwt jwt.IJwt, goMail gomail.IGoMail, imageRepository repository.IImageRepository, storage storage.IStorage,
+	universityRepository repository.IUniversityRepository, provinceRepository repository.IProvinceRepository,
+	regionRepository repository.IRegionRepository) IMerchantUsecase {
 	return &MerchantUsecase{
 		merchantRepository:   merchantRepository,
 		provinceRepository:   provinceRepository,
 		universityRepository: universityRepository,
+		regionRepository:     regionRepository,
 		jwt:                  jwt,
 		goMail:               goMail,
-		supabase:             supabase,
+		imageRepository:      imageRepository,
+		storage:              storage,
 	}
 }
 
-func (u *MerchantUsecase) GetMerchant(c *gin.Context) (domain.MerchantProfileResponse, error) {
+func (u *MerchantUsecase) GetMerchant(c *gin.Context, ctx context.Context) (domain.MerchantProfileResponse, error) {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return domain.MerchantProfileResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
 	}
 
 	var merchant domain.Merchants
-	err = u.merchantRepository.GetMerchant(&merchant, domain.MerchantParam{UserId: user.Id})
+	err = u.merchantRepository.GetMerchant(ctx, &merchant, domain.MerchantParam{UserId: user.Id})
 	if err != nil {
 		return domain.MerchantProfileResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when get merchant", err)
 	}
 
 	merchantResponse := domain.MerchantProfileResponse{
-		Id:            merchant.Id,
-		MerchantName:  merchant.MerchantName,
-		Province:      merchant.Province.Province,
-		City:          merchant.City,
-		University:    merchant.University.University,
-		Faculty:       merchant.Faculty,
-		PhoneNumber:   merchant.PhoneNumber,
-		Instagram:     merchant.Instagram,
-		MerchantPhoto: merchant.MerchantPhoto,
+		Id:                 merchant.Id,
+		MerchantName:       merchant.MerchantName,
+		Province:           merchant.Province.Province,
+		City:               merchant.City,
+		University:         merchant.University.University,
+		Faculty:            merchant.Faculty,
+		Latitude:           merchant.Latitude,
+		Longitude:          merchant.Longitude,
+		PhoneNumber:        merchant.PhoneNumber,
+		Instagram:          merchant.Instagram,
+		MerchantPhoto:      merchant.MerchantPhoto,
+		MerchantPhotoSizes: merchant.MerchantPhotoSizes.Or(merchant.MerchantPhoto),
 	}
 
 	return merchantResponse, nil
 }
 
-func (u *MerchantUsecase) CreateMerchant(c *gin.Context, merchantRequest domain.MerchantRequest) error {
+func (u *MerchantUsecase) CreateMerchant(c *gin.Context, ctx context.Context, merchantRequest domain.MerchantRequest) error {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "an error occured when get login user", err)
@@ -90,19 +104,30 @@ func (u *MerchantUsecase) CreateMerchant(c *gin.Context, merchantRequest domain.
 	}
 
 	var province domain.Province
-	err = u.provinceRepository.GetProvince(&province, domain.Province{Province: merchantRequest.Province})
+	err = u.provinceRepository.GetProvince(ctx, &province, domain.Province{Province: merchantRequest.Province})
 	if err != nil {
 		return response.NewError(http.StatusBadRequest, "province does not exist", err)
 	}
 
+	city, regencyId, districtId, err := u.resolveRegion(ctx, province, merchantRequest)
+	if err != nil {
+		return err
+	}
+
 	var university domain.Universities
-	err = u.universityRepository.GetUniversity(&university, domain.Universities{University: merchantRequest.University})
+	err = u.universityRepository.GetUniversity(ctx, &university, domain.Universities{University: merchantRequest.University})
 	if err != nil {
 		return response.NewError(http.StatusBadRequest, "university does not exist", err)
 	}
 
+	var deletedMerchant domain.Merchants
+	err = u.merchantRepository.GetDeletedMerchant(ctx, &deletedMerchant, domain.MerchantParam{UserId: user.Id})
+	if err == nil {
+		return response.NewError(http.StatusBadRequest, "an error occured when create merchant", errors.New("your merchant was deleted, please contact admin to restore it"))
+	}
+
 	var merchant domain.Merchants
-	err = u.merchantRepository.GetMerchant(&merchant, domain.MerchantParam{UserId: user.Id})
+	err = u.merchantRepository.GetMerchant(ctx, &merchant, domain.MerchantParam{UserId: user.Id})
 
 	if merchant.IsActive {
 		return response.NewError(http.StatusBadRequest, "an error occured when create merchant", errors.New("you already have merchant"))
@@ -113,12 +138,16 @@ func (u *MerchantUsecase) CreateMerchant(c *gin.Context, merchantRequest domain.
 			UniversityId: university.Id,
 			Faculty:      merchantRequest.Faculty,
 			ProvinceId:   province.Id,
-			City:         merchantRequest.City,
+			City:         city,
+			RegencyId:    regencyId,
+			DistrictId:   districtId,
+			Latitude:     merchantRequest.Latitude,
+			Longitude:    merchantRequest.Longitude,
 			PhoneNumber:  merchantRequest.PhoneNumber,
 			Instagram:    merchantRequest.Instagram,
 		}
 
-		err = u.merchantRepository.UpdateMerchant(&updateMerchant, merchant.Id)
+		err = u.merchantRepository.UpdateMerchant(ctx, &updateMerchant, merchant.Id)
 		if err != nil {
 			return response.NewError(http.StatusInternalServerError, "an error occured when update product", err)
 		}
@@ -133,12 +162,16 @@ func (u *MerchantUsecase) CreateMerchant(c *gin.Context, merchantRequest domain.
 		UniversityId: university.Id,
 		Faculty:      merchantRequest.Faculty,
 		ProvinceId:   province.Id,
-		City:         merchantRequest.City,
+		City:         city,
+		RegencyId:    regencyId,
+		DistrictId:   districtId,
+		Latitude:     merchantRequest.Latitude,
+		Longitude:    merchantRequest.Longitude,
 		PhoneNumber:  merchantRequest.PhoneNumber,
 		Instagram:    merchantRequest.Instagram,
 	}
 
-	err = u.merchantRepository.CreateMerchant(&newMerchant)
+	err = u.merchantRepository.CreateMerchant(ctx, &newMerchant)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an errpr occured when create product", err)
 	}
@@ -153,25 +186,36 @@ func (u *MerchantUsecase) SendOtp(c *gin.Context, ctx context.Context) error {
 	}
 
 	var merchant domain.Merchants
-	err = u.merchantRepository.GetMerchant(&merchant, domain.MerchantParam{UserId: user.Id})
+	err = u.merchantRepository.GetMerchant(ctx, &merchant, domain.MerchantParam{UserId: user.Id})
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "please create your merchant before verify", err)
 	}
 
-	otp := rand.Intn(999999-100000) + 100000
-	otpString := strconv.Itoa(otp)
+	attempt, err := u.merchantRepository.GetOTPAttempt(ctx, user.Id)
+	if err != nil {
+		return cacheError(err, http.StatusInternalServerError, "an error occured when get otp attempt")
+	}
+
+	if attempt >= maxOtpAttempt {
+		return response.NewError(http.StatusTooManyRequests, "too many wrong otp, please try again later", errors.New("otp verification locked"))
+	}
 
-	err = u.merchantRepository.CreateOTP(ctx, user.Id, otpString)
+	otpString, err := generateRandomString(6, "0123456789")
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when make otp", err)
 	}
 
+	err = u.merchantRepository.CreateOTP(ctx, user.Id, otpString)
+	if err != nil {
+		return cacheError(err, http.StatusInternalServerError, "an error occured when make otp")
+	}
+
 	subject := "Verify Merchant Code"
 	htmlBody := `<html>
 	<p>Berikut adalah kode otp mu <strong>` + otpString + `</strong></p>
 	</html>`
 
-	err = u.goMail.SendGoMail(subject, htmlBody, user.Email)
+	err = u.goMail.SendGoMail(ctx, subject, htmlBody, user.Email)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "failed to send otp email", err)
 	}
@@ -185,126 +229,262 @@ func (u *MerchantUsecase) VerifyOtp(c *gin.Context, ctx context.Context, verifyO
 		return response.NewError(http.StatusNotFound, "an error occured when get login user", err)
 	}
 
+	attempt, err := u.merchantRepository.GetOTPAttempt(ctx, user.Id)
+	if err != nil {
+		return cacheError(err, http.StatusInternalServerError, "an error occured when get otp attempt")
+	}
+
+	if attempt >= maxOtpAttempt {
+		return response.NewError(http.StatusTooManyRequests, "too many wrong otp, please try again later", errors.New("otp verification locked"))
+	}
+
 	stringOtp, err := u.merchantRepository.GetOTP(ctx, user.Id)
 	if err != nil {
-		return response.NewError(http.StatusInternalServerError, "an error occured when get otp", err)
+		return cacheError(err, http.StatusInternalServerError, "an error occured when get otp")
 	}
 
-	if verifyOtp.VerifyOtp != stringOtp {
+	if subtle.ConstantTimeCompare([]byte(verifyOtp.VerifyOtp), []byte(stringOtp)) != 1 {
+		attempt, err = u.merchantRepository.IncrOTPAttempt(ctx, user.Id, otpLockout)
+		if err != nil {
+			return cacheError(err, http.StatusInternalServerError, "an error occured when count otp attempt")
+		}
+
+		if attempt >= maxOtpAttempt {
+			logging.FromContext(ctx).WithField("user_id", user.Id).Warn("otp verification locked after repeated wrong codes")
+
+			err = u.merchantRepository.DeleteOTP(ctx, user.Id)
+			if err != nil {
+				return cacheError(err, http.StatusInternalServerError, "an error occured when delete otp")
+			}
+
+			return response.NewError(http.StatusTooManyRequests, "too many wrong otp, please try again later", errors.New("otp verification locked"))
+		}
+
 		return response.NewError(http.StatusUnauthorized, "invalid token", errors.New("wrong token"))
 	}
 
+	err = u.merchantRepository.DeleteOTP(ctx, user.Id)
+	if err != nil {
+		return cacheError(err, http.StatusInternalServerError, "an error occured when delete otp")
+	}
+
+	err = u.merchantRepository.DeleteOTPAttempt(ctx, user.Id)
+	if err != nil {
+		return cacheError(err, http.StatusInternalServerError, "an error occured when reset otp attempt")
+	}
+
 	var merchant domain.Merchants
-	err = u.merchantRepository.GetMerchant(&merchant, domain.MerchantParam{UserId: user.Id})
+	err = u.merchantRepository.GetMerchant(ctx, &merchant, domain.MerchantParam{UserId: user.Id})
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "an error occured when get merchant", err)
 	}
 
 	merchant.IsActive = true
 
-	err = u.merchantRepository.UpdateMerchant(&domain.UpdateMerchant{IsActive: true}, merchant.Id)
+	err = u.merchantRepository.UpdateMerchant(ctx, &domain.UpdateMerchant{IsActive: true}, merchant.Id)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when update account", err)
 	}
 
+	logging.FromContext(ctx).WithField("merchant_id", merchant.Id).Info("merchant verified")
+
 	return nil
 }
 
-func (u *MerchantUsecase) UpdateMerchant(c *gin.Context, merchantId uuid.UUID, updateMerchant domain.UpdateMerchant) (domain.MerchantProfileResponse, error) {
+func (u *MerchantUsecase) UpdateMerchant(c *gin.Context, ctx context.Context, updateMerchant domain.UpdateMerchant) (domain.MerchantProfileResponse, error) {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return domain.MerchantProfileResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
 	}
 
 	var merchant domain.Merchants
-	err = u.merchantRepository.GetMerchant(&merchant, domain.MerchantParam{Id: merchantId})
+	err = u.merchantRepository.GetMerchant(ctx, &merchant, domain.MerchantParam{UserId: user.Id})
 	if err != nil {
 		return domain.MerchantProfileResponse{}, response.NewError(http.StatusNotFound, "an error occured when get merchant", err)
 	}
 
-	if user.Id != merchant.UserId {
-		return domain.MerchantProfileResponse{}, response.NewError(http.StatusUnauthorized, "access denied", errors.New("can not edit other people merchant"))
-	}
-
-	err = u.merchantRepository.UpdateMerchant(&updateMerchant, merchant.Id)
+	err = u.merchantRepository.UpdateMerchant(ctx, &updateMerchant, merchant.Id)
 	if err != nil {
 		return domain.MerchantProfileResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when update merchant", err)
 	}
 
 	var updatedMerchant domain.Merchants
-	err = u.merchantRepository.GetMerchant(&updatedMerchant, domain.MerchantParam{Id: merchant.Id})
+	err = u.merchantRepository.GetMerchant(ctx, &updatedMerchant, domain.MerchantParam{Id: merchant.Id})
 	if err != nil {
 		return domain.MerchantProfileResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when get updated merchant", err)
 	}
 
 	updatedMerchantResponse := domain.MerchantProfileResponse{
-		Id:            updatedMerchant.Id,
-		MerchantName:  updatedMerchant.MerchantName,
-		Province:      updatedMerchant.Province.Province,
-		City:          updatedMerchant.City,
-		University:    updatedMerchant.University.University,
-		Faculty:       updatedMerchant.Faculty,
-		PhoneNumber:   updatedMerchant.PhoneNumber,
-		Instagram:     updatedMerchant.Instagram,
-		MerchantPhoto: updatedMerchant.MerchantPhoto,
+		Id:                 updatedMerchant.Id,
+		MerchantName:       updatedMerchant.MerchantName,
+		Province:           updatedMerchant.Province.Province,
+		City:               updatedMerchant.City,
+		University:         updatedMerchant.University.University,
+		Faculty:            updatedMerchant.Faculty,
+		Latitude:           updatedMerchant.Latitude,
+		Longitude:          updatedMerchant.Longitude,
+		PhoneNumber:        updatedMerchant.PhoneNumber,
+		Instagram:          updatedMerchant.Instagram,
+		MerchantPhoto:      updatedMerchant.MerchantPhoto,
+		MerchantPhotoSizes: updatedMerchant.MerchantPhotoSizes.Or(updatedMerchant.MerchantPhoto),
 	}
 
 	return updatedMerchantResponse, nil
 }
 
-func (u *MerchantUsecase) UploadMerchantPhoto(c *gin.Context, merchantId uuid.UUID, merchantPhoto *multipart.FileHeader) (domain.MerchantProfileResponse, error) {
+func (u *MerchantUsecase) UploadMerchantPhoto(c *gin.Context, ctx context.Context, merchantPhoto *multipart.FileHeader) (domain.MerchantProfileResponse, error) {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return domain.MerchantProfileResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
 	}
 
 	var merchant domain.Merchants
-	err = u.merchantRepository.GetMerchant(&merchant, domain.MerchantParam{Id: merchantId})
+	err = u.merchantRepository.GetMerchant(ctx, &merchant, domain.MerchantParam{UserId: user.Id})
 	if err != nil {
 		return domain.MerchantProfileResponse{}, response.NewError(http.StatusNotFound, "an error occured when get merchant", err)
 	}
 
-	if user.Id != merchant.UserId {
-		return domain.MerchantProfileResponse{}, response.NewError(http.StatusUnauthorized, "access denied", errors.New("can not edit other people merchant"))
-	}
-
-	if merchant.MerchantPhoto != "" {
-		err = u.supabase.Delete(merchant.MerchantPhoto)
-		if err != nil {
-			return domain.MerchantProfileResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when delete old merchant photo", err)
-		}
-	}
-
-	merchantPhoto.Filename = fmt.Sprintf("%v-%v", time.Now().String(), merchantPhoto.Filename)
-	merchantPhoto.Filename = strings.Replace(merchantPhoto.Filename, " ", "-", -1)
-
-	newMerchantPhoto, err := u.supabase.Upload(merchantPhoto)
+	newMerchantPhoto, err := uploadImage(ctx, u.storage, u.imageRepository, merchantPhoto)
 	if err != nil {
-		return domain.MerchantProfileResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when upload photo", err)
+		return domain.MerchantProfileResponse{}, err
 	}
 
-	err = u.merchantRepository.UpdateMerchant(&domain.UpdateMerchant{MerchantPhoto: newMerchantPhoto}, merchant.Id)
+	err = u.merchantRepository.UpdateMerchant(ctx, &domain.UpdateMerchant{
+		MerchantPhoto:      newMerchantPhoto.Original,
+		MerchantPhotoSizes: newMerchantPhoto.Sizes,
+	}, merchant.Id)
 	if err != nil {
+		removeImage(ctx, u.storage, u.imageRepository, newMerchantPhoto)
 		return domain.MerchantProfileResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when update merchant photo", err)
 	}
 
+	removeImage(ctx, u.storage, u.imageRepository, domain.UploadedImage{Original: merchant.MerchantPhoto, Sizes: merchant.MerchantPhotoSizes})
+
 	var updatedMerchant domain.Merchants
-	err = u.merchantRepository.GetMerchant(&updatedMerchant, domain.MerchantParam{Id: merchant.Id})
+	err = u.merchantRepository.GetMerchant(ctx, &updatedMerchant, domain.MerchantParam{Id: merchant.Id})
 	if err != nil {
 		return domain.MerchantProfileResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when get updated merchant", err)
 	}
 
 	updatedMerchantResponse := domain.MerchantProfileResponse{
-		Id:            updatedMerchant.Id,
-		MerchantName:  updatedMerchant.MerchantName,
-		Province:      updatedMerchant.Province.Province,
-		City:          updatedMerchant.City,
-		University:    updatedMerchant.University.University,
-		Faculty:       updatedMerchant.Faculty,
-		PhoneNumber:   updatedMerchant.PhoneNumber,
-		Instagram:     updatedMerchant.Instagram,
-		MerchantPhoto: updatedMerchant.MerchantPhoto,
+		Id:                 updatedMerchant.Id,
+		MerchantName:       updatedMerchant.MerchantName,
+		Province:           updatedMerchant.Province.Province,
+		City:               updatedMerchant.City,
+		University:         updatedMerchant.University.University,
+		Faculty:            updatedMerchant.Faculty,
+		Latitude:           updatedMerchant.Latitude,
+		Longitude:          updatedMerchant.Longitude,
+		PhoneNumber:        updatedMerchant.PhoneNumber,
+		Instagram:          updatedMerchant.Instagram,
+		MerchantPhoto:      updatedMerchant.MerchantPhoto,
+		MerchantPhotoSizes: updatedMerchant.MerchantPhotoSizes.Or(updatedMerchant.MerchantPhoto),
 	}
 
 	return updatedMerchantResponse, nil
 }
+
+func (u *MerchantUsecase) DeleteOwnMerchant(c *gin.Context, ctx context.Context) error {
+	user, err := u.jwt.GetLoginUser(c)
+	if err != nil {
+		return response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	var merchant domain.Merchants
+	err = u.merchantRepository.GetMerchant(ctx, &merchant, domain.MerchantParam{UserId: user.Id})
+	if err != nil {
+		return response.NewError(http.StatusNotFound, "an error occured when get merchant", err)
+	}
+
+	return u.deleteMerchant(ctx, merchant)
+}
+
+func (u *MerchantUsecase) DeleteMerchant(c *gin.Context, ctx context.Context, merchantId uuid.UUID) error {
+	user, err := u.jwt.GetLoginUser(c)
+	if err != nil {
+		return response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	var merchant domain.Merchants
+	err = u.merchantRepository.GetMerchant(ctx, &merchant, domain.MerchantParam{Id: merchantId})
+	if err != nil {
+		return response.NewError(http.StatusNotFound, "an error occured when get merchant", err)
+	}
+
+	err = authorizeOwnerOrAdmin(user, merchant.UserId)
+	if err != nil {
+		return err
+	}
+
+	return u.deleteMerchant(ctx, merchant)
+}
+
+func (u *MerchantUsecase) deleteMerchant(ctx context.Context, merchant domain.Merchants) error {
+	err := u.merchantRepository.DeleteMerchant(ctx, merchant.Id)
+	if err != nil {
+		return response.NewError(http.StatusInternalServerError, "an error occured when delete merchant", err)
+	}
+
+	return nil
+}
+
+// resolveRegion links the merchant to a regency when one matches. The bundled
+// region data is not complete yet, so a city name that matches no regency is
+// kept as free text and linked by the regions seeder once its regency exists.
+func (u *MerchantUsecase) resolveRegion(ctx context.Context, province domain.Province, merchantRequest domain.MerchantRequest) (string, *int, *int, error) {
+	var regency domain.Regencies
+	if merchantRequest.RegencyId != 0 {
+		err := u.regionRepository.GetRegency(ctx, &regency, domain.Regencies{Id: merchantRequest.RegencyId})
+		if err != nil {
+			return "", nil, nil, response.NewError(http.StatusBadRequest, "city does not exist", err)
+		}
+
+		if regency.ProvinceId != province.Id {
+			return "", nil, nil, response.NewError(http.StatusBadRequest, "city is not in the selected province", errors.New("regency belongs to another province"))
+		}
+	} else {
+		city := strings.TrimSpace(merchantRequest.City)
+
+		var regencies []domain.Regencies
+		err := u.regionRepository.FindRegenciesByName(ctx, &regencies, province.Id, []string{city, "Kota " + city, "Kabupaten " + city})
+		if err != nil {
+			return "", nil, nil, response.NewError(http.StatusInternalServerError, "an error occured when find city", err)
+		}
+
+		for _, r := range regencies {
+			if strings.EqualFold(r.Name, city) {
+				regencies = []domain.Regencies{r}
+				break
+			}
+		}
+
+		switch len(regencies) {
+		case 0:
+			if merchantRequest.DistrictId != 0 {
+				return "", nil, nil, response.NewError(http.StatusBadRequest, "district needs a city from the region list", errors.New("regency not found"))
+			}
+
+			return city, nil, nil, nil
+		case 1:
+			regency = regencies[0]
+		default:
+			return "", nil, nil, response.NewError(http.StatusBadRequest, "city is ambiguous, please choose a regency_id", errors.New("more than one regency matches"))
+		}
+	}
+
+	if merchantRequest.DistrictId == 0 {
+		return regency.Name, &regency.Id, nil, nil
+	}
+
+	var district domain.Districts
+	err := u.regionRepository.GetDistrict(ctx, &district, domain.Districts{Id: merchantRequest.DistrictId})
+	if err != nil {
+		return "", nil, nil, response.NewError(http.StatusBadRequest, "district does not exist", err)
+	}
+
+	if district.RegencyId != regency.Id {
+		return "", nil, nil, response.NewError(http.StatusBadRequest, "district is not in the selected city", errors.New("district belongs to another regency"))
+	}
+
+	return regency.Name, &regency.Id, &district.Id, nil
+}
@@ -0,0 +1,26 @@
+package usecase
+
+import (
+	"errors"
+	"intern-bcc/domain"
+	"intern-bcc/pkg/response"
+	"net/http"
+
+	"github.com/google/uuid"
+)
+
+func authorizeOwner(user domain.Users, ownerId uuid.UUID) error {
+	if user.Id != ownerId {
+		return response.NewError(http.StatusForbidden, "access denied", errors.New("resource belongs to another account"))
+	}
+
+	return nil
+}
+
+func authorizeOwnerOrAdmin(user domain.Users, ownerId uuid.UUID) error {
+	if user.IsAdmin {
+		return nil
+	}
+
+	return authorizeOwner(user, ownerId)
+}
@@ -0,0 +1,272 @@
+package usecase
+
+import (
+	"context"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/jwt"
+	"net/http"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// fakeJwt logs every request in as user.
+type fakeJwt struct {
+	jwt.IJwt
+	user domain.Users
+}
+
+func (j *fakeJwt) GetLoginUser(c *gin.Context) (domain.Users, error) {
+	return j.user, nil
+}
+
+// fakeProductRepository keeps products in memory and records every write.
+type fakeProductRepository struct {
+	repository.IProductRepository
+	products map[uuid.UUID]domain.Products
+	writes   []string
+}
+
+func (r *fakeProductRepository) GetProduct(ctx context.Context, product *domain.Products, productParam domain.ProductParam) error {
+	found, ok := r.products[productParam.Id]
+	if !ok || found.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+
+	*product = found
+	return nil
+}
+
+func (r *fakeProductRepository) UpdateProduct(ctx context.Context, product *domain.ProductUpdate, productId uuid.UUID) error {
+	r.writes = append(r.writes, "update "+productId.String())
+	return nil
+}
+
+func (r *fakeProductRepository) DeleteProduct(ctx context.Context, productId uuid.UUID) error {
+	r.writes = append(r.writes, "delete "+productId.String())
+
+	product := r.products[productId]
+	product.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.products[productId] = product
+	return nil
+}
+
+func (r *fakeProductRepository) RestoreProduct(ctx context.Context, productId uuid.UUID) error {
+	product, ok := r.products[productId]
+	if !ok || !product.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+
+	r.writes = append(r.writes, "restore "+productId.String())
+	product.DeletedAt = gorm.DeletedAt{}
+	r.products[productId] = product
+	return nil
+}
+
+func (r *fakeProductRepository) GetDeletedProducts(ctx context.Context, products *[]domain.Products) error {
+	for _, product := range r.products {
+		if product.DeletedAt.Valid {
+			*products = append(*products, product)
+		}
+	}
+
+	return nil
+}
+
+// fakeMerchantRepository keeps merchants in memory and records every write.
+type fakeMerchantRepository struct {
+	repository.IMerchantRepository
+	merchants map[uuid.UUID]domain.Merchants
+	writes    []string
+}
+
+func (r *fakeMerchantRepository) GetMerchant(ctx context.Context, merchant *domain.Merchants, param domain.MerchantParam) error {
+	for _, found := range r.merchants {
+		if !found.DeletedAt.Valid && (found.Id == param.Id || found.UserId == param.UserId) {
+			*merchant = found
+			return nil
+		}
+	}
+
+	return gorm.ErrRecordNotFound
+}
+
+func (r *fakeMerchantRepository) DeleteMerchant(ctx context.Context, merchantId uuid.UUID) error {
+	r.writes = append(r.writes, "delete "+merchantId.String())
+
+	merchant := r.merchants[merchantId]
+	merchant.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	r.merchants[merchantId] = merchant
+	return nil
+}
+
+func (r *fakeMerchantRepository) RestoreMerchant(ctx context.Context, merchantId uuid.UUID) error {
+	merchant, ok := r.merchants[merchantId]
+	if !ok || !merchant.DeletedAt.Valid {
+		return gorm.ErrRecordNotFound
+	}
+
+	r.writes = append(r.writes, "restore "+merchantId.String())
+	merchant.DeletedAt = gorm.DeletedAt{}
+	r.merchants[merchantId] = merchant
+	return nil
+}
+
+func (r *fakeMerchantRepository) GetDeletedMerchants(ctx context.Context, merchants *[]domain.Merchants) error {
+	for _, merchant := range r.merchants {
+		if merchant.DeletedAt.Valid {
+			*merchants = append(*merchants, merchant)
+		}
+	}
+
+	return nil
+}
+
+type fakeUploadRepository struct {
+	repository.IUploadRepository
+	uploads map[uuid.UUID]domain.PendingUpload
+}
+
+func (r *fakeUploadRepository) GetPendingUpload(ctx context.Context, upload *domain.PendingUpload, uploadId uuid.UUID) error {
+	*upload = r.uploads[uploadId]
+	return nil
+}
+
+type ownershipFixture struct {
+	owner              domain.Users
+	other              domain.Users
+	admin              domain.Users
+	merchant           domain.Merchants
+	product            domain.Products
+	productRepository  *fakeProductRepository
+	merchantRepository *fakeMerchantRepository
+	jwt                *fakeJwt
+	storage            *fakeStorage
+	events             *[]string
+	productUsecase     IProductUsecase
+	merchantUsecase    IMerchantUsecase
+}
+
+func newOwnershipFixture() ownershipFixture {
+	owner := domain.Users{Id: uuid.New(), Name: "owner"}
+	merchant := domain.Merchants{Id: uuid.New(), UserId: owner.Id, MerchantName: "Toko", IsActive: true}
+	product := domain.Products{Id: uuid.New(), Name: "Keripik", MerchantId: merchant.Id, Merchant: merchant}
+
+	store, images, events := newImageFixture()
+	productRepository := &fakeProductRepository{products: map[uuid.UUID]domain.Products{product.Id: product}}
+	merchantRepository := &fakeMerchantRepository{merchants: map[uuid.UUID]domain.Merchants{merchant.Id: merchant}}
+	categoryRepository := &fakeCategoryRepository{categories: map[int]domain.Categories{
+		1: {Id: 1, Category: "Food", Kind: domain.CategoryKindProduct},
+	}}
+	jwt := &fakeJwt{}
+
+	return ownershipFixture{
+		owner:              owner,
+		other:              domain.Users{Id: uuid.New(), Name: "other"},
+		admin:              domain.Users{Id: uuid.New(), Name: "admin", IsAdmin: true},
+		merchant:           merchant,
+		product:            product,
+		productRepository:  productRepository,
+		merchantRepository: merchantRepository,
+		jwt:                jwt,
+		storage:            store,
+		events:             events,
+		productUsecase:     NewProductUsecase(productRepository, nil, jwt, merchantRepository, categoryRepository, images, store),
+		merchantUsecase:    NewMerchantUsecase(merchantRepository, jwt, nil, images, store, nil, nil, nil),
+	}
+}
+
+func TestProductOwnershipComesFromTheToken(t *testing.T) {
+	ctx := context.Background()
+	f := newOwnershipFixture()
+
+	file, err := fileHeader("photo.png", "image/png", testImage(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.jwt.user = f.other
+	_, err = f.productUsecase.GetOwnProduct(nil, ctx, domain.ProductParam{Id: f.product.Id})
+	assertErrorCode(t, err, http.StatusForbidden)
+	_, err = f.productUsecase.UpdateProduct(nil, ctx, f.product.Id, domain.ProductUpdate{Name: "Stolen", Category: 1})
+	assertErrorCode(t, err, http.StatusForbidden)
+	_, err = f.productUsecase.AddProductPhoto(nil, ctx, f.product.Id, file)
+	assertErrorCode(t, err, http.StatusForbidden)
+	_, err = f.productUsecase.DeleteProductPhoto(nil, ctx, f.product.Id, uuid.New())
+	assertErrorCode(t, err, http.StatusForbidden)
+	err = f.productUsecase.DeleteProduct(nil, ctx, f.product.Id)
+	assertErrorCode(t, err, http.StatusForbidden)
+
+	if len(f.productRepository.writes) > 0 || len(*f.events) > 0 {
+		t.Fatalf("expected another user to change nothing, got %v and %v", f.productRepository.writes, *f.events)
+	}
+
+	// Admins may take a product down, but not edit it as if they owned it.
+	f.jwt.user = f.admin
+	_, err = f.productUsecase.UpdateProduct(nil, ctx, f.product.Id, domain.ProductUpdate{Name: "Edited", Category: 1})
+	assertErrorCode(t, err, http.StatusForbidden)
+
+	f.jwt.user = f.owner
+	_, err = f.productUsecase.UpdateProduct(nil, ctx, f.product.Id, domain.ProductUpdate{Name: "Keripik Pedas", Category: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f.jwt.user = f.admin
+	err = f.productUsecase.DeleteProduct(nil, ctx, f.product.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []string{"update " + f.product.Id.String(), "delete " + f.product.Id.String()}
+	if !slices.Equal(f.productRepository.writes, want) {
+		t.Fatalf("expected %v, got %v", want, f.productRepository.writes)
+	}
+}
+
+func TestDeleteMerchantRequiresOwnerOrAdmin(t *testing.T) {
+	ctx := context.Background()
+
+	f := newOwnershipFixture()
+	f.jwt.user = f.other
+	err := f.merchantUsecase.DeleteMerchant(nil, ctx, f.merchant.Id)
+	assertErrorCode(t, err, http.StatusForbidden)
+	if len(f.merchantRepository.writes) > 0 {
+		t.Fatalf("expected another user to change nothing, got %v", f.merchantRepository.writes)
+	}
+
+	for _, admin := range []bool{false, true} {
+		f := newOwnershipFixture()
+		f.jwt.user = f.owner
+		if admin {
+			f.jwt.user = f.admin
+		}
+
+		err := f.merchantUsecase.DeleteMerchant(nil, ctx, f.merchant.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !slices.Equal(f.merchantRepository.writes, []string{"delete " + f.merchant.Id.String()}) {
+			t.Fatalf("expected the merchant to be deleted, got %v", f.merchantRepository.writes)
+		}
+	}
+}
+
+func TestOpenUploadRejectsAnotherUsersUpload(t *testing.T) {
+	ctx := context.Background()
+	f := newOwnershipFixture()
+
+	target := domain.UploadTarget{Entity: domain.UploadEntityUser, Field: "profile_picture"}
+	upload := domain.PendingUpload{Id: uuid.New(), UserId: f.owner.Id, Target: target, Key: "uploads/a.png"}
+	uploads := &fakeUploadRepository{uploads: map[uuid.UUID]domain.PendingUpload{upload.Id: upload}}
+	uploadUsecase := NewUploadUsecase(uploads, nil, f.jwt, f.storage)
+
+	f.jwt.user = f.other
+	_, err := uploadUsecase.OpenUpload(nil, ctx, target, upload.Id)
+	assertErrorCode(t, err, http.StatusForbidden)
+}
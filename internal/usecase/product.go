@@ -6,51 +6,67 @@ import (
 	"fmt"
 	"intern-bcc/domain"
 	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/geo"
 	"intern-bcc/pkg/jwt"
 	"intern-bcc/pkg/response"
-	"intern-bcc/pkg/supabase"
+	"intern-bcc/pkg/storage"
 	"math"
 	"mime/multipart"
 	"net/http"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+const (
+	productDefaultRadius    = 10
+	productMaxRadius        = 100
+	productGeohashPrecision = 7
+)
+
 type IProductUsecase interface {
-	GetProduct(productParam domain.ProductParam) (domain.ProductResponse, error)
+	GetProduct(ctx context.Context, productParam domain.ProductParam) (domain.ProductResponse, error)
 	GetProducts(c *gin.Context, ctx context.Context, productParam domain.ProductParam) ([]domain.ProductResponses, error)
-	GetOwnProduct(productParam domain.ProductParam) (domain.ProductProfileResponse, error)
-	CreateProduct(c *gin.Context, productRequest domain.ProductRequest) error
-	UpdateProduct(c *gin.Context, productId uuid.UUID, updateProduct domain.ProductUpdate) (domain.ProductProfileResponse, error)
-	UploadProductPhoto(c *gin.Context, productId uuid.UUID, productPhoto *multipart.FileHeader) (domain.ProductProfileResponse, error)
+	GetOwnProduct(c *gin.Context, ctx context.Context, productParam domain.ProductParam) (domain.ProductProfileResponse, error)
+	CreateProduct(c *gin.Context, ctx context.Context, productRequest domain.ProductRequest) error
+	UpdateProduct(c *gin.Context, ctx context.Context, productId uuid.UUID, updateProduct domain.ProductUpdate) (domain.ProductProfileResponse, error)
+	UploadProductPhoto(c *gin.Context, ctx context.Context, productId uuid.UUID, productPhoto *multipart.FileHeader) (domain.ProductProfileResponse, error)
+	AddProductPhoto(c *gin.Context, ctx context.Context, productId uuid.UUID, productPhoto *multipart.FileHeader) ([]domain.ProductPhotoResponse, error)
+	ReplaceProductPhoto(c *gin.Context, ctx context.Context, productId uuid.UUID, photoId uuid.UUID, productPhoto *multipart.FileHeader) ([]domain.ProductPhotoResponse, error)
+	ReorderProductPhotos(c *gin.Context, ctx context.Context, productId uuid.UUID, photoOrder domain.ProductPhotoOrder) ([]domain.ProductPhotoResponse, error)
+	SetPrimaryProductPhoto(c *gin.Context, ctx context.Context, productId uuid.UUID, photoId uuid.UUID) ([]domain.ProductPhotoResponse, error)
+	DeleteProductPhoto(c *gin.Context, ctx context.Context, productId uuid.UUID, photoId uuid.UUID) ([]domain.ProductPhotoResponse, error)
+	DeleteProduct(c *gin.Context, ctx context.Context, productId uuid.UUID) error
 }
 
 type ProductUsecase struct {
-	productRepository  repository.IProductRepository
-	merchantRepository repository.IMerchantRepository
-	categoryRepository repository.ICategoryRepository
-	jwt                jwt.IJwt
-	supabase           supabase.ISupabase
+	productRepository      repository.IProductRepository
+	productPhotoRepository repository.IProductPhotoRepository
+	merchantRepository     repository.IMerchantRepository
+	categoryRepository     repository.ICategoryRepository
+	jwt                    jwt.IJwt
+	imageRepository        repository.IImageRepository
+	storage                storage.IStorage
 }
 
-func NewProductUsecase(productRepository repository.IProductRepository, jwt jwt.IJwt,
+func NewProductUsecase(productRepository repository.IProductRepository, productPhotoRepository repository.IProductPhotoRepository, jwt jwt.IJwt,
 	merchantRepository repository.IMerchantRepository, categoryRepository repository.ICategoryRepository,
-	supabase supabase.ISupabase) IProductUsecase {
+	imageRepository repository.IImageRepository, storage storage.IStorage) IProductUsecase {
 	return &ProductUsecase{
-		productRepository:  productRepository,
-		jwt:                jwt,
-		merchantRepository: merchantRepository,
-		categoryRepository: categoryRepository,
-		supabase:           supabase,
+		productRepository:      productRepository,
+		productPhotoRepository: productPhotoRepository,
+		jwt:                    jwt,
+		merchantRepository:     merchantRepository,
+		categoryRepository:     categoryRepository,
+		imageRepository:        imageRepository,
+		storage:                storage,
 	}
 }
 
-func (u *ProductUsecase) GetProduct(productParam domain.ProductParam) (domain.ProductResponse, error) {
+func (u *ProductUsecase) GetProduct(ctx context.Context, productParam domain.ProductParam) (domain.ProductResponse, error) {
 	var product domain.Products
-	err := u.productRepository.GetProduct(&product, productParam)
+	err := u.productRepository.GetProduct(ctx, &product, productParam)
 	if err != nil {
 		return domain.ProductResponse{}, response.NewError(http.StatusNotFound, "an error occured when get product", err)
 	}
@@ -59,18 +75,20 @@ func (u *ProductUsecase) GetProduct(productParam domain.ProductParam) (domain.Pr
 	linkWhatsApp := fmt.Sprintf("https://wa.me/%v", countryPhoneNumber)
 
 	productResponse := domain.ProductResponse{
-		Id:           product.Id,
-		Name:         product.Name,
-		Description:  product.Description,
-		Price:        product.Price,
-		ProductPhoto: product.ProductPhoto,
-		MerchantName: product.Merchant.MerchantName,
-		University:   product.Merchant.University.University,
-		Faculty:      product.Merchant.Faculty,
-		Province:     product.Merchant.Province.Province,
-		City:         product.Merchant.City,
-		WhatsApp:     linkWhatsApp,
-		Instagram:    product.Merchant.Instagram,
+		Id:                product.Id,
+		Name:              product.Name,
+		Description:       product.Description,
+		Price:             product.Price,
+		ProductPhoto:      product.ProductPhoto,
+		ProductPhotoSizes: product.ProductPhotoSizes.Or(product.ProductPhoto),
+		Photos:            productPhotoResponses(product.Photos),
+		MerchantName:      product.Merchant.MerchantName,
+		University:        product.Merchant.University.University,
+		Faculty:           product.Merchant.Faculty,
+		Province:          product.Merchant.Province.Province,
+		City:              product.Merchant.City,
+		WhatsApp:          linkWhatsApp,
+		Instagram:         product.Merchant.Instagram,
 	}
 
 	return productResponse, nil
@@ -84,8 +102,13 @@ func (u *ProductUsecase) GetProducts(c *gin.Context, ctx context.Context, produc
 	offSet := (productParam.Page - 1) * 6
 	productParam.Offset = offSet
 
+	err := normalizeNearby(&productParam)
+	if err != nil {
+		return []domain.ProductResponses{}, err
+	}
+
 	var totalProduct int64
-	err := u.productRepository.GetTotalProduct(&totalProduct)
+	err = u.productRepository.GetTotalProduct(ctx, &totalProduct)
 	if err != nil {
 		return []domain.ProductResponses{}, response.NewError(http.StatusInternalServerError, "failed to get total product", err)
 	}
@@ -110,7 +133,8 @@ func (u *ProductUsecase) GetProducts(c *gin.Context, ctx context.Context, produc
 			MerchantName: p.Merchant.MerchantName,
 			University:   p.Merchant.University.University,
 			Price:        p.Price,
-			ProductPhoto: p.ProductPhoto,
+			ProductPhoto: p.ProductPhotoSizes.Or(p.ProductPhoto).Thumbnail,
+			Distance:     p.Distance,
 		}
 
 		productResponses = append(productResponses, productResponse)
@@ -119,33 +143,39 @@ func (u *ProductUsecase) GetProducts(c *gin.Context, ctx context.Context, produc
 	return productResponses, nil
 }
 
-func (u *ProductUsecase) GetOwnProduct(productParam domain.ProductParam) (domain.ProductProfileResponse, error) {
-	var product domain.Products
-	err := u.productRepository.GetProduct(&product, productParam)
+func (u *ProductUsecase) GetOwnProduct(c *gin.Context, ctx context.Context, productParam domain.ProductParam) (domain.ProductProfileResponse, error) {
+	user, err := u.jwt.GetLoginUser(c)
+	if err != nil {
+		return domain.ProductProfileResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	product, err := u.getOwnedProduct(ctx, user, productParam.Id)
 	if err != nil {
-		return domain.ProductProfileResponse{}, response.NewError(http.StatusNotFound, "an error occured when get product", err)
+		return domain.ProductProfileResponse{}, err
 	}
 
 	productResponse := domain.ProductProfileResponse{
-		Id:           product.Id,
-		Name:         product.Name,
-		Description:  product.Description,
-		Category:     product.Category.Category,
-		Price:        product.Price,
-		ProductPhoto: product.ProductPhoto,
+		Id:                product.Id,
+		Name:              product.Name,
+		Description:       product.Description,
+		Category:          product.Category.Category,
+		Price:             product.Price,
+		ProductPhoto:      product.ProductPhoto,
+		ProductPhotoSizes: product.ProductPhotoSizes.Or(product.ProductPhoto),
+		Photos:            productPhotoResponses(product.Photos),
 	}
 
 	return productResponse, nil
 }
 
-func (u *ProductUsecase) CreateProduct(c *gin.Context, productRequest domain.ProductRequest) error {
+func (u *ProductUsecase) CreateProduct(c *gin.Context, ctx context.Context, productRequest domain.ProductRequest) error {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "an error occured when get login user", err)
 	}
 
 	var merchant domain.Merchants
-	err = u.merchantRepository.GetMerchant(&merchant, domain.MerchantParam{UserId: user.Id})
+	err = u.merchantRepository.GetMerchant(ctx, &merchant, domain.MerchantParam{UserId: user.Id})
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "an error occured when get merchant", err)
 	}
@@ -155,12 +185,12 @@ func (u *ProductUsecase) CreateProduct(c *gin.Context, productRequest domain.Pro
 	}
 
 	var category domain.Categories
-	err = u.categoryRepository.GetCategory(&category, domain.Categories{Id: productRequest.Category})
+	err = u.categoryRepository.GetCategory(ctx, &category, domain.Categories{Id: productRequest.Category})
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "category not found", err)
 	}
 
-	if category.Id > 6 {
+	if category.Kind != domain.CategoryKindProduct {
 		return response.NewError(http.StatusBadRequest, "can no use this category for product", errors.New("can not use information category"))
 
 	}
@@ -174,7 +204,7 @@ func (u *ProductUsecase) CreateProduct(c *gin.Context, productRequest domain.Pro
 		CategoryId:  category.Id,
 	}
 
-	err = u.productRepository.CreateProduct(&newProduct)
+	err = u.productRepository.CreateProduct(ctx, &newProduct)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when creating product", err)
 	}
@@ -182,117 +212,168 @@ func (u *ProductUsecase) CreateProduct(c *gin.Context, productRequest domain.Pro
 	return nil
 }
 
-func (u *ProductUsecase) UpdateProduct(c *gin.Context, productId uuid.UUID, updateProduct domain.ProductUpdate) (domain.ProductProfileResponse, error) {
+func (u *ProductUsecase) UpdateProduct(c *gin.Context, ctx context.Context, productId uuid.UUID, updateProduct domain.ProductUpdate) (domain.ProductProfileResponse, error) {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return domain.ProductProfileResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
 	}
 
-	var product domain.Products
-	err = u.productRepository.GetProduct(&product, domain.ProductParam{Id: productId})
+	product, err := u.getOwnedProduct(ctx, user, productId)
 	if err != nil {
-		return domain.ProductProfileResponse{}, response.NewError(http.StatusNotFound, "an error occured when get product", err)
+		return domain.ProductProfileResponse{}, err
 	}
 
 	var category domain.Categories
-	err = u.categoryRepository.GetCategory(&category, domain.Categories{Id: updateProduct.Category})
+	err = u.categoryRepository.GetCategory(ctx, &category, domain.Categories{Id: updateProduct.Category})
 	if err != nil {
 		return domain.ProductProfileResponse{}, response.NewError(http.StatusNotFound, "category not found", err)
 	}
 
-	if category.Id > 6 {
+	if category.Kind != domain.CategoryKindProduct {
 		return domain.ProductProfileResponse{}, response.NewError(http.StatusBadRequest, "can no use this category for product", errors.New("can not use information category"))
 	}
 
-	var merchant domain.Merchants
-	err = u.merchantRepository.GetMerchant(&merchant, domain.MerchantParam{UserId: user.Id})
+	err = u.productRepository.UpdateProduct(ctx, &updateProduct, product.Id)
 	if err != nil {
-		return domain.ProductProfileResponse{}, response.NewError(http.StatusNotFound, "merchant not found", err)
+		return domain.ProductProfileResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when update product", err)
 	}
 
-	if merchant.Id != product.MerchantId {
-		return domain.ProductProfileResponse{}, response.NewError(http.StatusUnauthorized, "access denied", errors.New("can not edit other people merchant"))
+	var updatedProduct domain.Products
+	err = u.productRepository.GetProduct(ctx, &updatedProduct, domain.ProductParam{Id: product.Id})
+	if err != nil {
+		return domain.ProductProfileResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when get updated product", err)
+	}
+
+	updatedProductResponse := domain.ProductProfileResponse{
+		Id:                updatedProduct.Id,
+		Name:              updatedProduct.Name,
+		Description:       updatedProduct.Description,
+		Price:             updatedProduct.Price,
+		ProductPhoto:      updatedProduct.ProductPhoto,
+		ProductPhotoSizes: updatedProduct.ProductPhotoSizes.Or(updatedProduct.ProductPhoto),
+		Photos:            productPhotoResponses(updatedProduct.Photos),
+		Category:          updatedProduct.Category.Category,
 	}
 
-	err = u.productRepository.UpdateProduct(&updateProduct, product.Id)
+	return updatedProductResponse, nil
+}
+
+func (u *ProductUsecase) UploadProductPhoto(c *gin.Context, ctx context.Context, productId uuid.UUID, productPhoto *multipart.FileHeader) (domain.ProductProfileResponse, error) {
+	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
-		return domain.ProductProfileResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when update product", err)
+		return domain.ProductProfileResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	product, err := u.getOwnedProduct(ctx, user, productId)
+	if err != nil {
+		return domain.ProductProfileResponse{}, err
+	}
+
+	primary, ok := primaryPhoto(product.Photos)
+	if ok {
+		err = u.replaceProductPhoto(ctx, primary, productPhoto)
+	} else {
+		err = u.addProductPhoto(ctx, product.Id, productPhoto)
+	}
+	if err != nil {
+		return domain.ProductProfileResponse{}, err
 	}
 
 	var updatedProduct domain.Products
-	err = u.productRepository.GetProduct(&updatedProduct, domain.ProductParam{Id: product.Id})
+	err = u.productRepository.GetProduct(ctx, &updatedProduct, domain.ProductParam{Id: product.Id})
 	if err != nil {
 		return domain.ProductProfileResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when get updated product", err)
 	}
 
 	updatedProductResponse := domain.ProductProfileResponse{
-		Id:           updatedProduct.Id,
-		Name:         updatedProduct.Name,
-		Description:  updatedProduct.Description,
-		Price:        updatedProduct.Price,
-		ProductPhoto: updatedProduct.ProductPhoto,
-		Category:     updatedProduct.Category.Category,
+		Id:                updatedProduct.Id,
+		Name:              updatedProduct.Name,
+		Description:       updatedProduct.Description,
+		Price:             updatedProduct.Price,
+		ProductPhoto:      updatedProduct.ProductPhoto,
+		ProductPhotoSizes: updatedProduct.ProductPhotoSizes.Or(updatedProduct.ProductPhoto),
+		Photos:            productPhotoResponses(updatedProduct.Photos),
+		Category:          updatedProduct.Category.Category,
 	}
 
 	return updatedProductResponse, nil
 }
 
-func (u *ProductUsecase) UploadProductPhoto(c *gin.Context, productId uuid.UUID, productPhoto *multipart.FileHeader) (domain.ProductProfileResponse, error) {
+func (u *ProductUsecase) DeleteProduct(c *gin.Context, ctx context.Context, productId uuid.UUID) error {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
-		return domain.ProductProfileResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+		return response.NewError(http.StatusNotFound, "an error occured when get login user", err)
 	}
 
 	var product domain.Products
-	err = u.productRepository.GetProduct(&product, domain.ProductParam{Id: productId})
+	err = u.productRepository.GetProduct(ctx, &product, domain.ProductParam{Id: productId})
 	if err != nil {
-		return domain.ProductProfileResponse{}, response.NewError(http.StatusNotFound, "an error occured when get product", err)
+		return response.NewError(http.StatusNotFound, "an error occured when get product", err)
 	}
 
-	var merchant domain.Merchants
-	err = u.merchantRepository.GetMerchant(&merchant, domain.MerchantParam{UserId: user.Id})
+	err = authorizeOwnerOrAdmin(user, product.Merchant.UserId)
 	if err != nil {
-		return domain.ProductProfileResponse{}, response.NewError(http.StatusNotFound, "merchant not found", err)
-	}
-
-	if merchant.Id != product.MerchantId {
-		return domain.ProductProfileResponse{}, response.NewError(http.StatusUnauthorized, "access denied", errors.New("can not edit other people merchant"))
+		return err
 	}
 
-	if product.ProductPhoto != "" {
-		err = u.supabase.Delete(product.ProductPhoto)
-		if err != nil {
-			return domain.ProductProfileResponse{}, response.NewError(http.StatusInternalServerError, "error occured when deleting old product photo", err)
-		}
+	err = u.productRepository.DeleteProduct(ctx, product.Id)
+	if err != nil {
+		return response.NewError(http.StatusInternalServerError, "an error occured when delete product", err)
 	}
 
-	productPhoto.Filename = fmt.Sprintf("%v-%v", time.Now().String(), productPhoto.Filename)
-	productPhoto.Filename = strings.Replace(productPhoto.Filename, " ", "-", -1)
+	return nil
+}
 
-	newProductPhoto, err := u.supabase.Upload(productPhoto)
+func (u *ProductUsecase) getOwnedProduct(ctx context.Context, user domain.Users, productId uuid.UUID) (domain.Products, error) {
+	var product domain.Products
+	err := u.productRepository.GetProduct(ctx, &product, domain.ProductParam{Id: productId})
 	if err != nil {
-		return domain.ProductProfileResponse{}, response.NewError(http.StatusInternalServerError, "failed to upload photo", err)
+		return domain.Products{}, response.NewError(http.StatusNotFound, "an error occured when get product", err)
 	}
 
-	err = u.productRepository.UpdateProduct(&domain.ProductUpdate{ProductPhoto: newProductPhoto}, product.Id)
+	err = authorizeOwner(user, product.Merchant.UserId)
 	if err != nil {
-		return domain.ProductProfileResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when update product", err)
+		return domain.Products{}, err
 	}
 
-	var updatedProduct domain.Products
-	err = u.productRepository.GetProduct(&updatedProduct, domain.ProductParam{Id: product.Id})
-	if err != nil {
-		return domain.ProductProfileResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when get updated product", err)
+	return product, nil
+}
+
+// normalizeNearby validates the location filter and snaps its center to a
+// geohash cell, so that nearby users share the same cached result page.
+func normalizeNearby(productParam *domain.ProductParam) error {
+	if productParam.Latitude == nil && productParam.Longitude == nil {
+		if productParam.Sort == domain.ProductSortDistance {
+			return response.NewError(http.StatusBadRequest, "lat and lng are required to sort by distance", errors.New("missing location"))
+		}
+
+		productParam.Radius = 0
+		return nil
 	}
 
-	updatedProductResponse := domain.ProductProfileResponse{
-		Id:           updatedProduct.Id,
-		Name:         updatedProduct.Name,
-		Description:  updatedProduct.Description,
-		Price:        updatedProduct.Price,
-		ProductPhoto: updatedProduct.ProductPhoto,
-		Category:     updatedProduct.Category.Category,
+	if productParam.Latitude == nil || productParam.Longitude == nil {
+		return response.NewError(http.StatusBadRequest, "lat and lng must be sent together", errors.New("incomplete location"))
 	}
 
-	return updatedProductResponse, nil
+	latitude, longitude := *productParam.Latitude, *productParam.Longitude
+	if latitude < -90 || latitude > 90 || longitude < -180 || longitude > 180 {
+		return response.NewError(http.StatusBadRequest, "invalid location", errors.New("coordinates out of range"))
+	}
+
+	if productParam.Sort != "" && productParam.Sort != domain.ProductSortDistance {
+		return response.NewError(http.StatusBadRequest, "invalid sort", fmt.Errorf("unknown sort %q", productParam.Sort))
+	}
+
+	switch {
+	case productParam.Radius == 0:
+		productParam.Radius = productDefaultRadius
+	case productParam.Radius < 0 || productParam.Radius > productMaxRadius:
+		return response.NewError(http.StatusBadRequest, fmt.Sprintf("radius must be between 0 and %v km", productMaxRadius), errors.New("radius out of range"))
+	}
+
+	productParam.Geohash = geo.Encode(latitude, longitude, productGeohashPrecision)
+	latitude, longitude = geo.Decode(productParam.Geohash)
+	productParam.Latitude, productParam.Longitude = &latitude, &longitude
+
+	return nil
 }
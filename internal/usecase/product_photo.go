@@ -0,0 +1,223 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/response"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const productMaxPhotos = 8
+
+func (u *ProductUsecase) AddProductPhoto(c *gin.Context, ctx context.Context, productId uuid.UUID, productPhoto *multipart.FileHeader) ([]domain.ProductPhotoResponse, error) {
+	product, err := u.getLoginUserProduct(c, ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(product.Photos) >= productMaxPhotos {
+		return nil, photoLimitError(repository.ErrPhotoLimitReached)
+	}
+
+	err = u.addProductPhoto(ctx, product.Id, productPhoto)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.getProductPhotos(ctx, product.Id)
+}
+
+func (u *ProductUsecase) ReplaceProductPhoto(c *gin.Context, ctx context.Context, productId uuid.UUID, photoId uuid.UUID, productPhoto *multipart.FileHeader) ([]domain.ProductPhotoResponse, error) {
+	product, err := u.getLoginUserProduct(c, ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	var photo domain.ProductPhotos
+	err = u.productPhotoRepository.GetPhoto(ctx, &photo, product.Id, photoId)
+	if err != nil {
+		return nil, response.NewError(http.StatusNotFound, "product photo not found", err)
+	}
+
+	err = u.replaceProductPhoto(ctx, photo, productPhoto)
+	if err != nil {
+		return nil, err
+	}
+
+	return u.getProductPhotos(ctx, product.Id)
+}
+
+func (u *ProductUsecase) ReorderProductPhotos(c *gin.Context, ctx context.Context, productId uuid.UUID, photoOrder domain.ProductPhotoOrder) ([]domain.ProductPhotoResponse, error) {
+	product, err := u.getLoginUserProduct(c, ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[uuid.UUID]bool, len(product.Photos))
+	for _, photo := range product.Photos {
+		current[photo.Id] = true
+	}
+
+	seen := make(map[uuid.UUID]bool, len(photoOrder.PhotoIds))
+	for _, photoId := range photoOrder.PhotoIds {
+		if !current[photoId] || seen[photoId] {
+			return nil, response.NewError(http.StatusBadRequest, "photo_ids must list every photo of the product once", fmt.Errorf("unexpected photo id %v", photoId))
+		}
+		seen[photoId] = true
+	}
+
+	if len(seen) != len(current) {
+		return nil, response.NewError(http.StatusBadRequest, "photo_ids must list every photo of the product once", errors.New("missing photo ids"))
+	}
+
+	err = u.productPhotoRepository.ReorderPhotos(ctx, product.Id, photoOrder.PhotoIds)
+	if err != nil {
+		return nil, response.NewError(http.StatusInternalServerError, "an error occured when reorder product photos", err)
+	}
+
+	return u.getProductPhotos(ctx, product.Id)
+}
+
+func (u *ProductUsecase) SetPrimaryProductPhoto(c *gin.Context, ctx context.Context, productId uuid.UUID, photoId uuid.UUID) ([]domain.ProductPhotoResponse, error) {
+	product, err := u.getLoginUserProduct(c, ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	var photo domain.ProductPhotos
+	err = u.productPhotoRepository.GetPhoto(ctx, &photo, product.Id, photoId)
+	if err != nil {
+		return nil, response.NewError(http.StatusNotFound, "product photo not found", err)
+	}
+
+	err = u.productPhotoRepository.SetPrimaryPhoto(ctx, product.Id, photo.Id)
+	if err != nil {
+		return nil, response.NewError(http.StatusInternalServerError, "an error occured when set primary product photo", err)
+	}
+
+	return u.getProductPhotos(ctx, product.Id)
+}
+
+func (u *ProductUsecase) DeleteProductPhoto(c *gin.Context, ctx context.Context, productId uuid.UUID, photoId uuid.UUID) ([]domain.ProductPhotoResponse, error) {
+	product, err := u.getLoginUserProduct(c, ctx, productId)
+	if err != nil {
+		return nil, err
+	}
+
+	var photo domain.ProductPhotos
+	err = u.productPhotoRepository.GetPhoto(ctx, &photo, product.Id, photoId)
+	if err != nil {
+		return nil, response.NewError(http.StatusNotFound, "product photo not found", err)
+	}
+
+	err = u.productPhotoRepository.DeletePhoto(ctx, &photo)
+	if err != nil {
+		return nil, response.NewError(http.StatusInternalServerError, "an error occured when delete product photo", err)
+	}
+
+	removeImage(ctx, u.storage, u.imageRepository, storedProductPhoto(photo))
+
+	return u.getProductPhotos(ctx, product.Id)
+}
+
+func (u *ProductUsecase) getLoginUserProduct(c *gin.Context, ctx context.Context, productId uuid.UUID) (domain.Products, error) {
+	user, err := u.jwt.GetLoginUser(c)
+	if err != nil {
+		return domain.Products{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	return u.getOwnedProduct(ctx, user, productId)
+}
+
+func (u *ProductUsecase) getProductPhotos(ctx context.Context, productId uuid.UUID) ([]domain.ProductPhotoResponse, error) {
+	var photos []domain.ProductPhotos
+	err := u.productPhotoRepository.GetPhotos(ctx, &photos, productId)
+	if err != nil {
+		return nil, response.NewError(http.StatusInternalServerError, "an error occured when get product photos", err)
+	}
+
+	return productPhotoResponses(photos), nil
+}
+
+func (u *ProductUsecase) addProductPhoto(ctx context.Context, productId uuid.UUID, productPhoto *multipart.FileHeader) error {
+	image, err := uploadImage(ctx, u.storage, u.imageRepository, productPhoto)
+	if err != nil {
+		return err
+	}
+
+	err = u.productPhotoRepository.CreatePhoto(ctx, &domain.ProductPhotos{
+		Id:        uuid.New(),
+		ProductId: productId,
+		Url:       image.Original,
+		Sizes:     image.Sizes,
+	}, productMaxPhotos)
+	if errors.Is(err, repository.ErrPhotoLimitReached) {
+		removeImage(ctx, u.storage, u.imageRepository, image)
+		return photoLimitError(err)
+	}
+	if err != nil {
+		removeImage(ctx, u.storage, u.imageRepository, image)
+		return response.NewError(http.StatusInternalServerError, "an error occured when save product photo", err)
+	}
+
+	return nil
+}
+
+func photoLimitError(err error) error {
+	return response.NewError(http.StatusBadRequest, fmt.Sprintf("a product can have at most %d photos", productMaxPhotos), err)
+}
+
+// replaceProductPhoto uploads the new file before touching the record, so a
+// failed upload keeps the old photo and a failed update removes the new file.
+func (u *ProductUsecase) replaceProductPhoto(ctx context.Context, photo domain.ProductPhotos, productPhoto *multipart.FileHeader) error {
+	image, err := uploadImage(ctx, u.storage, u.imageRepository, productPhoto)
+	if err != nil {
+		return err
+	}
+
+	err = u.productPhotoRepository.ReplacePhoto(ctx, &photo, image)
+	if err != nil {
+		removeImage(ctx, u.storage, u.imageRepository, image)
+		return response.NewError(http.StatusInternalServerError, "an error occured when save product photo", err)
+	}
+
+	removeImage(ctx, u.storage, u.imageRepository, storedProductPhoto(photo))
+
+	return nil
+}
+
+func storedProductPhoto(photo domain.ProductPhotos) domain.UploadedImage {
+	return domain.UploadedImage{Original: photo.Url, Sizes: photo.Sizes}
+}
+
+func primaryPhoto(photos []domain.ProductPhotos) (domain.ProductPhotos, bool) {
+	for _, photo := range photos {
+		if photo.IsPrimary {
+			return photo, true
+		}
+	}
+
+	return domain.ProductPhotos{}, false
+}
+
+func productPhotoResponses(photos []domain.ProductPhotos) []domain.ProductPhotoResponse {
+	photoResponses := []domain.ProductPhotoResponse{}
+	for _, photo := range photos {
+		photoResponses = append(photoResponses, domain.ProductPhotoResponse{
+			Id:        photo.Id,
+			Url:       photo.Url,
+			Sizes:     photo.Sizes.Or(photo.Url),
+			Position:  photo.Position,
+			IsPrimary: photo.IsPrimary,
+		})
+	}
+
+	return photoResponses
+}
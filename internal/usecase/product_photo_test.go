@@ -0,0 +1,143 @@
+package usecase
+
+import (
+	"context"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"net/http"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// fakeProductPhotoRepository keeps the photos of one product and enforces the
+// limit the way the locking CreatePhoto does.
+type fakeProductPhotoRepository struct {
+	repository.IProductPhotoRepository
+	photos []domain.ProductPhotos
+}
+
+func (r *fakeProductPhotoRepository) CreatePhoto(ctx context.Context, photo *domain.ProductPhotos, maxPhotos int64) error {
+	if int64(len(r.photos)) >= maxPhotos {
+		return repository.ErrPhotoLimitReached
+	}
+
+	photo.Position = len(r.photos)
+	r.photos = append(r.photos, *photo)
+	return nil
+}
+
+func (r *fakeProductPhotoRepository) GetPhotos(ctx context.Context, photos *[]domain.ProductPhotos, productId uuid.UUID) error {
+	*photos = append(*photos, r.photos...)
+	return nil
+}
+
+func newPhotos(total int) []domain.ProductPhotos {
+	var photos []domain.ProductPhotos
+	for i := 0; i < total; i++ {
+		photos = append(photos, domain.ProductPhotos{Id: uuid.New(), Url: "http://storage/existing.jpg", Position: i})
+	}
+
+	return photos
+}
+
+// newPhotoFixture returns a product of the login user that has loaded photos,
+// while the repository already holds stored photos.
+func newPhotoFixture(loaded int, stored int) (ownershipFixture, *fakeProductPhotoRepository, *fakeImageRepository, IProductUsecase) {
+	f := newOwnershipFixture()
+	f.jwt.user = f.owner
+
+	product := f.product
+	product.Photos = newPhotos(loaded)
+	f.productRepository.products[product.Id] = product
+
+	store, images, events := newImageFixture()
+	f.storage, f.events = store, events
+	photos := &fakeProductPhotoRepository{photos: newPhotos(stored)}
+	categories := &fakeCategoryRepository{categories: map[int]domain.Categories{}}
+
+	return f, photos, images, NewProductUsecase(f.productRepository, photos, f.jwt, f.merchantRepository, categories, images, store)
+}
+
+func TestAddProductPhotoAllowsUpToTheLimit(t *testing.T) {
+	ctx := context.Background()
+	f, photos, _, productUsecase := newPhotoFixture(productMaxPhotos-1, productMaxPhotos-1)
+
+	file, err := fileHeader("photo.png", "image/png", testImage(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	photoResponses, err := productUsecase.AddProductPhoto(nil, ctx, f.product.Id, file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(photoResponses) != productMaxPhotos || len(photos.photos) != productMaxPhotos {
+		t.Fatalf("expected %v photos, got %v", productMaxPhotos, len(photoResponses))
+	}
+}
+
+func TestAddProductPhotoOverTheLimitUploadsNothing(t *testing.T) {
+	ctx := context.Background()
+	f, photos, _, productUsecase := newPhotoFixture(productMaxPhotos, productMaxPhotos)
+
+	file, err := fileHeader("photo.png", "image/png", testImage(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = productUsecase.AddProductPhoto(nil, ctx, f.product.Id, file)
+	assertErrorCode(t, err, http.StatusBadRequest)
+
+	if len(*f.events) > 0 || len(photos.photos) != productMaxPhotos {
+		t.Fatalf("expected nothing to be uploaded or saved, got %v", *f.events)
+	}
+}
+
+func TestAddProductPhotoLosingTheRaceLeavesNoFiles(t *testing.T) {
+	ctx := context.Background()
+
+	// The product was loaded with room for one more photo, but a concurrent
+	// upload filled the last slot before this one was saved.
+	f, photos, images, productUsecase := newPhotoFixture(productMaxPhotos-1, productMaxPhotos)
+
+	file, err := fileHeader("photo.png", "image/png", testImage(t))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = productUsecase.AddProductPhoto(nil, ctx, f.product.Id, file)
+	assertErrorCode(t, err, http.StatusBadRequest)
+	if len(photos.photos) != productMaxPhotos {
+		t.Fatalf("expected no photo to be saved, got %v", len(photos.photos))
+	}
+
+	var uploaded []string
+	for _, event := range *f.events {
+		if link, ok := strings.CutPrefix(event, "upload "); ok {
+			uploaded = append(uploaded, link)
+		}
+	}
+	if len(uploaded) != 3 {
+		t.Fatalf("expected the photo and its renditions to be uploaded, got %v", *f.events)
+	}
+
+	// The upload stays reserved for the grace period, then the sweep
+	// deletes it because no photo uses it.
+	for link := range images.reserved {
+		images.reserved[link] = time.Now().Add(-2 * imageUploadGrace)
+	}
+	err = sweepImages(ctx, f.storage, images)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, link := range uploaded {
+		if !slices.Contains(*f.events, "delete "+link) {
+			t.Fatalf("expected %v to be deleted, got %v", link, *f.events)
+		}
+	}
+}
@@ -1,14 +1,21 @@
 package usecase
 
 import (
+	"context"
+	"errors"
 	"intern-bcc/domain"
 	"intern-bcc/internal/repository"
 	"intern-bcc/pkg/response"
 	"net/http"
+
+	"gorm.io/gorm"
 )
 
 type IProvinceUsecase interface {
-	CreateProvince(provinceRequest domain.Province) error
+	GetProvinces(ctx context.Context) ([]domain.ProvinceResponse, error)
+	CreateProvince(ctx context.Context, provinceRequest domain.Province) error
+	UpdateProvince(ctx context.Context, provinceId int, provinceRequest domain.ProvinceRequest) (domain.ProvinceResponse, error)
+	DeleteProvince(ctx context.Context, provinceId int) error
 }
 
 type ProvinceUsecase struct {
@@ -19,15 +26,73 @@ func NewProvinceUsecase(provinceRepository repository.IProvinceRepository) IProv
 	return &ProvinceUsecase{provinceRepository}
 }
 
-func (u *ProvinceUsecase) CreateProvince(provinceRequest domain.Province) error {
+func (u *ProvinceUsecase) GetProvinces(ctx context.Context) ([]domain.ProvinceResponse, error) {
+	var provinces []domain.Province
+	err := u.provinceRepository.GetProvinces(ctx, &provinces)
+	if err != nil {
+		return nil, response.NewError(http.StatusInternalServerError, "an error occured when get provinces", err)
+	}
+
+	provinceResponses := []domain.ProvinceResponse{}
+	for _, province := range provinces {
+		provinceResponses = append(provinceResponses, domain.ProvinceResponse{
+			Id:       province.Id,
+			Code:     province.Code,
+			Province: province.Province,
+		})
+	}
+
+	return provinceResponses, nil
+}
+
+func (u *ProvinceUsecase) CreateProvince(ctx context.Context, provinceRequest domain.Province) error {
 	province := domain.Province{
+		Code:     provinceRequest.Code,
 		Province: provinceRequest.Province,
 	}
 
-	err := u.provinceRepository.CreateProvince(&province)
+	err := u.provinceRepository.CreateProvince(ctx, &province)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when create province", err)
 	}
 
 	return nil
 }
+
+func (u *ProvinceUsecase) UpdateProvince(ctx context.Context, provinceId int, provinceRequest domain.ProvinceRequest) (domain.ProvinceResponse, error) {
+	var province domain.Province
+	err := u.provinceRepository.GetProvince(ctx, &province, domain.Province{Id: provinceId})
+	if err != nil {
+		return domain.ProvinceResponse{}, response.NewError(http.StatusNotFound, "province not found", err)
+	}
+
+	province.Province = provinceRequest.Province
+	err = u.provinceRepository.UpdateProvince(ctx, &province)
+	if err != nil {
+		return domain.ProvinceResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when update province", err)
+	}
+
+	return domain.ProvinceResponse{
+		Id:       province.Id,
+		Code:     province.Code,
+		Province: province.Province,
+	}, nil
+}
+
+func (u *ProvinceUsecase) DeleteProvince(ctx context.Context, provinceId int) error {
+	err := u.provinceRepository.DeleteProvince(ctx, provinceId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return response.NewError(http.StatusNotFound, "province not found", err)
+	}
+	if errors.Is(err, repository.ErrProvinceHasMerchants) {
+		return response.NewError(http.StatusConflict, "province is still used by merchants", err)
+	}
+	if errors.Is(err, repository.ErrProvinceHasRegencies) {
+		return response.NewError(http.StatusConflict, "province still has regencies", err)
+	}
+	if err != nil {
+		return response.NewError(http.StatusInternalServerError, "an error occured when delete province", err)
+	}
+
+	return nil
+}
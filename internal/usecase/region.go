@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"context"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/response"
+	"net/http"
+)
+
+type IRegionUsecase interface {
+	GetRegencies(ctx context.Context, provinceId int) ([]domain.RegionResponse, error)
+	GetDistricts(ctx context.Context, regencyId int) ([]domain.RegionResponse, error)
+}
+
+type RegionUsecase struct {
+	regionRepository repository.IRegionRepository
+}
+
+func NewRegionUsecase(regionRepository repository.IRegionRepository) IRegionUsecase {
+	return &RegionUsecase{regionRepository}
+}
+
+func (u *RegionUsecase) GetRegencies(ctx context.Context, provinceId int) ([]domain.RegionResponse, error) {
+	var regencies []domain.Regencies
+	err := u.regionRepository.GetRegencies(ctx, &regencies, provinceId)
+	if err != nil {
+		return nil, response.NewError(http.StatusInternalServerError, "an error occured when get regencies", err)
+	}
+
+	regionResponses := []domain.RegionResponse{}
+	for _, regency := range regencies {
+		regionResponses = append(regionResponses, domain.RegionResponse{
+			Id:   regency.Id,
+			Code: regency.Code,
+			Name: regency.Name,
+		})
+	}
+
+	return regionResponses, nil
+}
+
+func (u *RegionUsecase) GetDistricts(ctx context.Context, regencyId int) ([]domain.RegionResponse, error) {
+	var districts []domain.Districts
+	err := u.regionRepository.GetDistricts(ctx, &districts, regencyId)
+	if err != nil {
+		return nil, response.NewError(http.StatusInternalServerError, "an error occured when get districts", err)
+	}
+
+	regionResponses := []domain.RegionResponse{}
+	for _, district := range districts {
+		regionResponses = append(regionResponses, domain.RegionResponse{
+			Id:   district.Id,
+			Code: district.Code,
+			Name: district.Name,
+		})
+	}
+
+	return regionResponses, nil
+}
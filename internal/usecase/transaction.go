@@ -1,10 +1,12 @@
 package usecase
 
 import (
+	"context"
 	"errors"
 	"intern-bcc/domain"
 	"intern-bcc/internal/repository"
 	"intern-bcc/pkg/jwt"
+	"intern-bcc/pkg/logging"
 	"intern-bcc/pkg/midtrans"
 	"intern-bcc/pkg/response"
 	"net/http"
@@ -16,8 +18,8 @@ import (
 )
 
 type ITransactionUsecase interface {
-	CreateTransaction(c *gin.Context, mentorId uuid.UUID, transactionRequest domain.TransactionRequest) (domain.TransactionResponse, error)
-	VerifyTransaction(payload map[string]interface{}) error
+	CreateTransaction(c *gin.Context, ctx context.Context, mentorId uuid.UUID, transactionRequest domain.TransactionRequest) (domain.TransactionResponse, error)
+	VerifyTransaction(ctx context.Context, payload map[string]interface{}) error
 }
 
 type TransactionUsecase struct {
@@ -38,14 +40,14 @@ func NewTransactionUsecase(transactionRepository repository.ITransactionReposito
 	}
 }
 
-func (u *TransactionUsecase) CreateTransaction(c *gin.Context, mentorId uuid.UUID, transactionRequest domain.TransactionRequest) (domain.TransactionResponse, error) {
+func (u *TransactionUsecase) CreateTransaction(c *gin.Context, ctx context.Context, mentorId uuid.UUID, transactionRequest domain.TransactionRequest) (domain.TransactionResponse, error) {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return domain.TransactionResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
 	}
 
 	var mentor domain.Mentors
-	err = u.mentorRepository.GetMentor(&mentor, domain.MentorParam{Id: mentorId})
+	err = u.mentorRepository.GetMentor(ctx, &mentor, domain.MentorParam{Id: mentorId})
 	if err != nil {
 		return domain.TransactionResponse{}, response.NewError(http.StatusNotFound, "an error occured when create transaction", err)
 	}
@@ -64,12 +66,12 @@ func (u *TransactionUsecase) CreateTransaction(c *gin.Context, mentorId uuid.UUI
 		PayedAt:     nullTime,
 	}
 
-	coreApiRes, err := u.midTrans.ChargeTransaction(newTransaction)
+	coreApiRes, err := u.midTrans.ChargeTransaction(c.Request.Context(), newTransaction)
 	if err != nil {
 		return domain.TransactionResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when charge transaction", err)
 	}
 
-	err = u.transactionRepository.CreateTransaction(&newTransaction)
+	err = u.transactionRepository.CreateTransaction(ctx, &newTransaction)
 	if err != nil {
 		return domain.TransactionResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when create transaction", err)
 	}
@@ -97,14 +99,15 @@ func generateTransactionResponse(transactionRequest domain.TransactionRequest, c
 	return transactionResponse
 }
 
-func (u *TransactionUsecase) VerifyTransaction(payload map[string]interface{}) error {
+func (u *TransactionUsecase) VerifyTransaction(ctx context.Context, payload map[string]interface{}) error {
 	transactionIdString, exist := payload["order_id"].(string)
 	if !exist {
 		return response.NewError(http.StatusNotFound, "transaction not found", errors.New("can't find the transaction"))
 	}
 
-	success, err := u.midTrans.VerifyPayment(transactionIdString)
+	success, err := u.midTrans.VerifyPayment(ctx, transactionIdString)
 	if !success {
+		logging.FromContext(ctx).WithError(err).WithField("transaction_id", transactionIdString).Warn("payment notification for an unpaid transaction")
 		return response.NewError(http.StatusBadRequest, "transaction failed", err)
 	}
 
@@ -115,7 +118,7 @@ func (u *TransactionUsecase) VerifyTransaction(payload map[string]interface{}) e
 
 	var transaction domain.Transactions
 	transaction.Id = transactionId
-	err = u.transactionRepository.GetTransaction(&transaction)
+	err = u.transactionRepository.GetTransaction(ctx, &transaction)
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "transaction not found", err)
 	}
@@ -128,7 +131,7 @@ func (u *TransactionUsecase) VerifyTransaction(payload map[string]interface{}) e
 	transaction.IsPayed = true
 	transaction.PayedAt = paymentTime
 
-	err = u.transactionRepository.UpdateTransaction(&transaction)
+	err = u.transactionRepository.UpdateTransaction(ctx, &transaction)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when update transaction", err)
 	}
@@ -138,10 +141,12 @@ func (u *TransactionUsecase) VerifyTransaction(payload map[string]interface{}) e
 		MentorId: transaction.MentorId,
 	}
 
-	err = u.userRepository.CreateHasMentor(&mentor)
+	err = u.userRepository.CreateHasMentor(ctx, &mentor)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when create mentor and student relation", err)
 	}
 
+	logging.FromContext(ctx).WithField("transaction_id", transaction.Id).Info("transaction paid")
+
 	return nil
 }
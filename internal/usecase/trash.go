@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/logging"
+	"intern-bcc/pkg/response"
+	"net/http"
+	"strconv"
+
+	"github.com/google/uuid"
+)
+
+type ITrashUsecase interface {
+	GetTrash(ctx context.Context) (domain.TrashResponse, error)
+	Restore(ctx context.Context, entity string, id string) error
+}
+
+type TrashUsecase struct {
+	productRepository     repository.IProductRepository
+	merchantRepository    repository.IMerchantRepository
+	mentorRepository      repository.IMentorRepository
+	informationRepository repository.IInformationRepository
+}
+
+func NewTrashUsecase(productRepository repository.IProductRepository, merchantRepository repository.IMerchantRepository,
+	mentorRepository repository.IMentorRepository, informationRepository repository.IInformationRepository) ITrashUsecase {
+	return &TrashUsecase{
+		productRepository:     productRepository,
+		merchantRepository:    merchantRepository,
+		mentorRepository:      mentorRepository,
+		informationRepository: informationRepository,
+	}
+}
+
+func (u *TrashUsecase) GetTrash(ctx context.Context) (domain.TrashResponse, error) {
+	var trash domain.TrashResponse
+
+	var products []domain.Products
+	err := u.productRepository.GetDeletedProducts(ctx, &products)
+	if err != nil {
+		return domain.TrashResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when get deleted products", err)
+	}
+	for _, p := range products {
+		trash.Products = append(trash.Products, domain.TrashItem{Id: p.Id.String(), Name: p.Name, DeletedAt: p.DeletedAt.Time})
+	}
+
+	var merchants []domain.Merchants
+	err = u.merchantRepository.GetDeletedMerchants(ctx, &merchants)
+	if err != nil {
+		return domain.TrashResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when get deleted merchants", err)
+	}
+	for _, m := range merchants {
+		trash.Merchants = append(trash.Merchants, domain.TrashItem{Id: m.Id.String(), Name: m.MerchantName, DeletedAt: m.DeletedAt.Time})
+	}
+
+	var mentors []domain.Mentors
+	err = u.mentorRepository.GetDeletedMentors(ctx, &mentors)
+	if err != nil {
+		return domain.TrashResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when get deleted mentors", err)
+	}
+	for _, m := range mentors {
+		trash.Mentors = append(trash.Mentors, domain.TrashItem{Id: m.Id.String(), Name: m.Name, DeletedAt: m.DeletedAt.Time})
+	}
+
+	var information []domain.Information
+	err = u.informationRepository.GetDeletedInformation(ctx, &information)
+	if err != nil {
+		return domain.TrashResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when get deleted information", err)
+	}
+	for _, i := range information {
+		trash.Information = append(trash.Information, domain.TrashItem{Id: strconv.Itoa(i.Id), Name: i.Title, DeletedAt: i.DeletedAt.Time})
+	}
+
+	return trash, nil
+}
+
+func (u *TrashUsecase) Restore(ctx context.Context, entity string, id string) error {
+	var err error
+
+	switch entity {
+	case "product", "merchant", "mentor":
+		uuidId, parseErr := uuid.Parse(id)
+		if parseErr != nil {
+			return response.NewError(http.StatusBadRequest, "failed to parsing id", parseErr)
+		}
+
+		switch entity {
+		case "product":
+			err = u.productRepository.RestoreProduct(ctx, uuidId)
+		case "merchant":
+			err = u.merchantRepository.RestoreMerchant(ctx, uuidId)
+		case "mentor":
+			err = u.mentorRepository.RestoreMentor(ctx, uuidId)
+		}
+	case "information":
+		intId, parseErr := strconv.Atoi(id)
+		if parseErr != nil {
+			return response.NewError(http.StatusBadRequest, "failed to parsing id", parseErr)
+		}
+
+		err = u.informationRepository.RestoreInformation(ctx, intId)
+	default:
+		return response.NewError(http.StatusBadRequest, "can not restore this entity", errors.New("unknown entity "+entity))
+	}
+
+	if err != nil {
+		return response.NewError(http.StatusNotFound, "an error occured when restore "+entity, err)
+	}
+
+	logging.FromContext(ctx).WithFields(map[string]interface{}{"entity": entity, "id": id}).Info("restored from trash")
+
+	return nil
+}
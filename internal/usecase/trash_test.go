@@ -0,0 +1,133 @@
+package usecase
+
+import (
+	"context"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type fakeMentorRepository struct {
+	repository.IMentorRepository
+}
+
+func (r *fakeMentorRepository) GetDeletedMentors(ctx context.Context, mentors *[]domain.Mentors) error {
+	return nil
+}
+
+func (r *fakeMentorRepository) RestoreMentor(ctx context.Context, mentorId uuid.UUID) error {
+	return gorm.ErrRecordNotFound
+}
+
+type fakeInformationRepository struct {
+	repository.IInformationRepository
+}
+
+func (r *fakeInformationRepository) GetDeletedInformation(ctx context.Context, information *[]domain.Information) error {
+	return nil
+}
+
+func (r *fakeInformationRepository) RestoreInformation(ctx context.Context, informationId int) error {
+	return gorm.ErrRecordNotFound
+}
+
+func TestDeletedProductGoesToTrashAndComesBack(t *testing.T) {
+	ctx := context.Background()
+	f := newOwnershipFixture()
+	trashUsecase := NewTrashUsecase(f.productRepository, f.merchantRepository, &fakeMentorRepository{}, &fakeInformationRepository{})
+
+	f.jwt.user = f.owner
+	err := f.productUsecase.DeleteProduct(nil, ctx, f.product.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.productUsecase.GetOwnProduct(nil, ctx, domain.ProductParam{Id: f.product.Id})
+	assertErrorCode(t, err, http.StatusNotFound)
+
+	trash, err := trashUsecase.GetTrash(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash.Products) != 1 || trash.Products[0].Id != f.product.Id.String() || trash.Products[0].DeletedAt.IsZero() {
+		t.Fatalf("expected the deleted product in the trash, got %+v", trash.Products)
+	}
+
+	err = trashUsecase.Restore(ctx, "product", f.product.Id.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = f.productUsecase.GetOwnProduct(nil, ctx, domain.ProductParam{Id: f.product.Id})
+	if err != nil {
+		t.Fatalf("expected the restored product to be back, got %v", err)
+	}
+
+	trash, err = trashUsecase.GetTrash(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash.Products) != 0 {
+		t.Fatalf("expected an empty trash, got %+v", trash.Products)
+	}
+
+	// A product that is not in the trash can not be restored twice.
+	err = trashUsecase.Restore(ctx, "product", f.product.Id.String())
+	assertErrorCode(t, err, http.StatusNotFound)
+}
+
+func TestDeletedMerchantGoesToTrashAndComesBack(t *testing.T) {
+	ctx := context.Background()
+	f := newOwnershipFixture()
+	trashUsecase := NewTrashUsecase(f.productRepository, f.merchantRepository, &fakeMentorRepository{}, &fakeInformationRepository{})
+
+	f.jwt.user = f.owner
+	err := f.merchantUsecase.DeleteMerchant(nil, ctx, f.merchant.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	trash, err := trashUsecase.GetTrash(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(trash.Merchants) != 1 || trash.Merchants[0].Name != f.merchant.MerchantName {
+		t.Fatalf("expected the deleted merchant in the trash, got %+v", trash.Merchants)
+	}
+
+	err = trashUsecase.Restore(ctx, "merchant", f.merchant.Id.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.merchantRepository.merchants[f.merchant.Id].DeletedAt.Valid {
+		t.Fatal("expected the merchant to be restored")
+	}
+}
+
+func TestRestoreRejectsUnknownEntitiesAndIds(t *testing.T) {
+	ctx := context.Background()
+	f := newOwnershipFixture()
+	trashUsecase := NewTrashUsecase(f.productRepository, f.merchantRepository, &fakeMentorRepository{}, &fakeInformationRepository{})
+
+	for _, test := range []struct {
+		entity string
+		id     string
+		code   int
+	}{
+		{"user", uuid.NewString(), http.StatusBadRequest},
+		{"product", "1", http.StatusBadRequest},
+		{"information", uuid.NewString(), http.StatusBadRequest},
+		{"product", f.product.Id.String(), http.StatusNotFound},
+		{"mentor", uuid.NewString(), http.StatusNotFound},
+		{"information", "1", http.StatusNotFound},
+	} {
+		err := trashUsecase.Restore(ctx, test.entity, test.id)
+		assertErrorCode(t, err, test.code)
+	}
+
+	if len(f.productRepository.writes) > 0 || len(f.merchantRepository.writes) > 0 {
+		t.Fatalf("expected nothing to be restored, got %v and %v", f.productRepository.writes, f.merchantRepository.writes)
+	}
+}
@@ -0,0 +1,340 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/jwt"
+	"intern-bcc/pkg/logging"
+	"intern-bcc/pkg/response"
+	"intern-bcc/pkg/totp"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+)
+
+const (
+	totalRecoveryCodes  = 10
+	maxTwoFactorAttempt = 5
+	twoFactorLockout    = 15 * time.Minute
+)
+
+type ITwoFactorUsecase interface {
+	Enroll(c *gin.Context, ctx context.Context) (domain.TwoFactorEnrollResponse, error)
+	Activate(c *gin.Context, ctx context.Context, twoFactorCode domain.TwoFactorCode) (domain.RecoveryCodesResponse, error)
+	Disable(c *gin.Context, ctx context.Context, twoFactorCode domain.TwoFactorCode) error
+	RegenerateRecoveryCodes(c *gin.Context, ctx context.Context, twoFactorCode domain.TwoFactorCode) (domain.RecoveryCodesResponse, error)
+	Login(ctx context.Context, twoFactorLogin domain.TwoFactorLogin) (domain.LoginResponse, error)
+}
+
+type TwoFactorUsecase struct {
+	twoFactorRepository repository.ITwoFactorRepository
+	userRepository      repository.IUserRepository
+	jwt                 jwt.IJwt
+	totp                totp.ITotp
+}
+
+func NewTwoFactorUsecase(twoFactorRepository repository.ITwoFactorRepository, userRepository repository.IUserRepository,
+	jwt jwt.IJwt, totp totp.ITotp) ITwoFactorUsecase {
+	return &TwoFactorUsecase{
+		twoFactorRepository: twoFactorRepository,
+		userRepository:      userRepository,
+		jwt:                 jwt,
+		totp:                totp,
+	}
+}
+
+func (u *TwoFactorUsecase) Enroll(c *gin.Context, ctx context.Context) (domain.TwoFactorEnrollResponse, error) {
+	user, err := u.jwt.GetLoginUser(c)
+	if err != nil {
+		return domain.TwoFactorEnrollResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	if user.TwoFactor {
+		return domain.TwoFactorEnrollResponse{}, response.NewError(http.StatusBadRequest, "failed to enroll two factor", errors.New("two factor already enabled"))
+	}
+
+	secret, provisioningUri, err := u.totp.GenerateSecret(user.Email)
+	if err != nil {
+		return domain.TwoFactorEnrollResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when generate two factor secret", err)
+	}
+
+	err = u.twoFactorRepository.CreateEnrollment(ctx, user.Id, secret)
+	if err != nil {
+		return domain.TwoFactorEnrollResponse{}, cacheError(err, http.StatusInternalServerError, "an error occured when save two factor enrollment")
+	}
+
+	enrollResponse := domain.TwoFactorEnrollResponse{
+		Secret:          secret,
+		ProvisioningUri: provisioningUri,
+	}
+
+	return enrollResponse, nil
+}
+
+func (u *TwoFactorUsecase) Activate(c *gin.Context, ctx context.Context, twoFactorCode domain.TwoFactorCode) (domain.RecoveryCodesResponse, error) {
+	user, err := u.jwt.GetLoginUser(c)
+	if err != nil {
+		return domain.RecoveryCodesResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	if user.TwoFactor {
+		return domain.RecoveryCodesResponse{}, response.NewError(http.StatusBadRequest, "failed to activate two factor", errors.New("two factor already enabled"))
+	}
+
+	secret, err := u.twoFactorRepository.GetEnrollment(ctx, user.Id)
+	if err != nil {
+		return domain.RecoveryCodesResponse{}, cacheError(err, http.StatusBadRequest, "two factor enrollment expired, please enroll again")
+	}
+
+	if !u.totp.Validate(twoFactorCode.Code, secret) {
+		return domain.RecoveryCodesResponse{}, response.NewError(http.StatusUnauthorized, "invalid code", errors.New("wrong two factor code"))
+	}
+
+	plainCodes, recoveryCodes, err := generateRecoveryCodes(user.Id)
+	if err != nil {
+		return domain.RecoveryCodesResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when generate recovery codes", err)
+	}
+
+	err = u.twoFactorRepository.EnableTwoFactor(ctx, user.Id, secret, recoveryCodes)
+	if err != nil {
+		return domain.RecoveryCodesResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when enable two factor", err)
+	}
+
+	_, err = u.twoFactorRepository.MarkCodeUsed(ctx, user.Id, twoFactorCode.Code)
+	if err != nil {
+		return domain.RecoveryCodesResponse{}, cacheError(err, http.StatusInternalServerError, "an error occured when save used code")
+	}
+
+	err = u.twoFactorRepository.DeleteEnrollment(ctx, user.Id)
+	if err != nil {
+		return domain.RecoveryCodesResponse{}, cacheError(err, http.StatusInternalServerError, "an error occured when delete two factor enrollment")
+	}
+
+	logging.FromContext(ctx).WithField("user_id", user.Id).Info("two factor enabled")
+
+	return domain.RecoveryCodesResponse{RecoveryCodes: plainCodes}, nil
+}
+
+func (u *TwoFactorUsecase) Disable(c *gin.Context, ctx context.Context, twoFactorCode domain.TwoFactorCode) error {
+	user, err := u.jwt.GetLoginUser(c)
+	if err != nil {
+		return response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	if user.IsAdmin {
+		return response.NewError(http.StatusForbidden, "failed to disable two factor", errors.New("admin account must use two factor"))
+	}
+
+	if !user.TwoFactor {
+		return response.NewError(http.StatusBadRequest, "failed to disable two factor", errors.New("two factor is not enabled"))
+	}
+
+	err = u.verifySecondFactor(ctx, user, twoFactorCode.Code)
+	if err != nil {
+		return err
+	}
+
+	err = u.twoFactorRepository.DisableTwoFactor(ctx, user.Id)
+	if err != nil {
+		return response.NewError(http.StatusInternalServerError, "an error occured when disable two factor", err)
+	}
+
+	logging.FromContext(ctx).WithField("user_id", user.Id).Info("two factor disabled")
+
+	return nil
+}
+
+func (u *TwoFactorUsecase) RegenerateRecoveryCodes(c *gin.Context, ctx context.Context, twoFactorCode domain.TwoFactorCode) (domain.RecoveryCodesResponse, error) {
+	user, err := u.jwt.GetLoginUser(c)
+	if err != nil {
+		return domain.RecoveryCodesResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	if !user.TwoFactor {
+		return domain.RecoveryCodesResponse{}, response.NewError(http.StatusBadRequest, "failed to regenerate recovery codes", errors.New("two factor is not enabled"))
+	}
+
+	err = u.verifySecondFactor(ctx, user, twoFactorCode.Code)
+	if err != nil {
+		return domain.RecoveryCodesResponse{}, err
+	}
+
+	plainCodes, recoveryCodes, err := generateRecoveryCodes(user.Id)
+	if err != nil {
+		return domain.RecoveryCodesResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when generate recovery codes", err)
+	}
+
+	err = u.twoFactorRepository.ReplaceRecoveryCodes(ctx, user.Id, recoveryCodes)
+	if err != nil {
+		return domain.RecoveryCodesResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when save recovery codes", err)
+	}
+
+	return domain.RecoveryCodesResponse{RecoveryCodes: plainCodes}, nil
+}
+
+func (u *TwoFactorUsecase) Login(ctx context.Context, twoFactorLogin domain.TwoFactorLogin) (domain.LoginResponse, error) {
+	userIdString, err := u.twoFactorRepository.GetChallenge(ctx, twoFactorLogin.Challenge)
+	if err != nil {
+		return domain.LoginResponse{}, cacheError(err, http.StatusUnauthorized, "login session expired, please login again")
+	}
+
+	userId, err := uuid.Parse(userIdString)
+	if err != nil {
+		return domain.LoginResponse{}, response.NewError(http.StatusInternalServerError, "failed to parsing user id", err)
+	}
+
+	var user domain.Users
+	err = u.userRepository.GetUser(ctx, &user, domain.UserParam{Id: userId})
+	if err != nil {
+		return domain.LoginResponse{}, response.NewError(http.StatusNotFound, "account not found", err)
+	}
+
+	err = u.verifySecondFactor(ctx, user, twoFactorLogin.Code)
+	var errorObject *response.ErrorObject
+	if errors.As(err, &errorObject) && errorObject.Code == http.StatusTooManyRequests {
+		deleteErr := u.twoFactorRepository.DeleteChallenge(ctx, twoFactorLogin.Challenge)
+		if deleteErr != nil {
+			return domain.LoginResponse{}, cacheError(deleteErr, http.StatusInternalServerError, "an error occured when delete login session")
+		}
+	}
+	if err != nil {
+		return domain.LoginResponse{}, err
+	}
+
+	err = u.twoFactorRepository.DeleteChallenge(ctx, twoFactorLogin.Challenge)
+	if err != nil {
+		return domain.LoginResponse{}, cacheError(err, http.StatusInternalServerError, "an error occured when delete login session")
+	}
+
+	tokenString, err := u.jwt.GenerateToken(user.Id)
+	if err != nil {
+		return domain.LoginResponse{}, response.NewError(http.StatusInternalServerError, "failed to generate jwt token", err)
+	}
+
+	return domain.LoginResponse{JWT: tokenString}, nil
+}
+
+// verifySecondFactor counts wrong codes per account, so spreading guesses over
+// several login challenges or addresses does not get around the lockout.
+func (u *TwoFactorUsecase) verifySecondFactor(ctx context.Context, user domain.Users, code string) error {
+	attempt, err := u.twoFactorRepository.GetAttempt(ctx, user.Id)
+	if err != nil {
+		return cacheError(err, http.StatusInternalServerError, "an error occured when get two factor attempt")
+	}
+
+	if attempt >= maxTwoFactorAttempt {
+		return response.NewError(http.StatusTooManyRequests, "too many wrong two factor codes, please try again later", errors.New("two factor verification locked"))
+	}
+
+	valid, err := u.matchSecondFactor(ctx, user, strings.TrimSpace(code))
+	if err != nil {
+		return err
+	}
+
+	if !valid {
+		attempt, err = u.twoFactorRepository.IncrAttempt(ctx, user.Id, twoFactorLockout)
+		if err != nil {
+			return cacheError(err, http.StatusInternalServerError, "an error occured when count two factor attempt")
+		}
+
+		if attempt >= maxTwoFactorAttempt {
+			logging.FromContext(ctx).WithField("user_id", user.Id).Warn("two factor verification locked after repeated wrong codes")
+			return response.NewError(http.StatusTooManyRequests, "too many wrong two factor codes, please try again later", errors.New("two factor verification locked"))
+		}
+
+		return response.NewError(http.StatusUnauthorized, "invalid code", errors.New("wrong two factor code"))
+	}
+
+	err = u.twoFactorRepository.DeleteAttempt(ctx, user.Id)
+	if err != nil {
+		return cacheError(err, http.StatusInternalServerError, "an error occured when reset two factor attempt")
+	}
+
+	return nil
+}
+
+// matchSecondFactor consumes the code when it is a fresh TOTP code or an unused
+// recovery code.
+func (u *TwoFactorUsecase) matchSecondFactor(ctx context.Context, user domain.Users, code string) (bool, error) {
+	if u.totp.Validate(code, user.TwoFactorKey) {
+		marked, err := u.twoFactorRepository.MarkCodeUsed(ctx, user.Id, code)
+		if err != nil {
+			return false, cacheError(err, http.StatusInternalServerError, "an error occured when save used code")
+		}
+
+		return marked, nil
+	}
+
+	var recoveryCodes []domain.RecoveryCodes
+	err := u.twoFactorRepository.GetRecoveryCodes(ctx, &recoveryCodes, user.Id)
+	if err != nil {
+		return false, response.NewError(http.StatusInternalServerError, "an error occured when get recovery codes", err)
+	}
+
+	for _, rc := range recoveryCodes {
+		if bcrypt.CompareHashAndPassword([]byte(rc.Code), []byte(strings.ToLower(code))) != nil {
+			continue
+		}
+
+		err = u.twoFactorRepository.DeleteRecoveryCode(ctx, rc.Id)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, response.NewError(http.StatusInternalServerError, "an error occured when use recovery code", err)
+		}
+
+		logging.FromContext(ctx).WithField("user_id", user.Id).Info("recovery code used")
+
+		return true, nil
+	}
+
+	return false, nil
+}
+
+func generateRecoveryCodes(userId uuid.UUID) ([]string, []domain.RecoveryCodes, error) {
+	var plainCodes []string
+	var recoveryCodes []domain.RecoveryCodes
+	for i := 0; i < totalRecoveryCodes; i++ {
+		code, err := generateRandomString(10, "abcdefghijkmnpqrstuvwxyz23456789")
+		if err != nil {
+			return nil, nil, err
+		}
+		code = code[:5] + "-" + code[5:]
+
+		hashCode, err := bcrypt.GenerateFromPassword([]byte(code), 10)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		plainCodes = append(plainCodes, code)
+		recoveryCodes = append(recoveryCodes, domain.RecoveryCodes{
+			UserId: userId,
+			Code:   string(hashCode),
+		})
+	}
+
+	return plainCodes, recoveryCodes, nil
+}
+
+func generateRandomString(length int, alphabet string) (string, error) {
+	result := make([]byte, length)
+	for i := range result {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(alphabet))))
+		if err != nil {
+			return "", err
+		}
+		result[i] = alphabet[n.Int64()]
+	}
+
+	return string(result), nil
+}
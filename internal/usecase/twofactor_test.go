@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/cache"
+	"intern-bcc/pkg/config"
+	"intern-bcc/pkg/jwt"
+	"intern-bcc/pkg/response"
+	"intern-bcc/pkg/totp"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	otptotp "github.com/pquerna/otp/totp"
+	"gorm.io/gorm"
+)
+
+// fakeTwoFactorRepository keeps the cache backed state of the real repository
+// and replaces the recovery code table. GetRecoveryCodes always returns every
+// code ever issued, like a read taken before a concurrent request deleted one,
+// so only DeleteRecoveryCode decides whether a code is still unused.
+type fakeTwoFactorRepository struct {
+	repository.ITwoFactorRepository
+	mu            sync.Mutex
+	recoveryCodes []domain.RecoveryCodes
+	deleted       map[int]bool
+}
+
+func (r *fakeTwoFactorRepository) GetRecoveryCodes(ctx context.Context, recoveryCodes *[]domain.RecoveryCodes, userId uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	*recoveryCodes = append([]domain.RecoveryCodes(nil), r.recoveryCodes...)
+	return nil
+}
+
+func (r *fakeTwoFactorRepository) DeleteRecoveryCode(ctx context.Context, recoveryCodeId int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.deleted[recoveryCodeId] {
+		return gorm.ErrRecordNotFound
+	}
+	r.deleted[recoveryCodeId] = true
+
+	return nil
+}
+
+type fakeUserRepository struct {
+	repository.IUserRepository
+	users  map[uuid.UUID]domain.Users
+	events *[]string
+}
+
+func (r *fakeUserRepository) GetUser(ctx context.Context, user *domain.Users, param domain.UserParam) error {
+	found, ok := r.users[param.Id]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	*user = found
+	return nil
+}
+
+func assertErrorCode(t *testing.T, err error, code int) {
+	t.Helper()
+
+	var errorObject *response.ErrorObject
+	if !errors.As(err, &errorObject) {
+		t.Fatalf("expected an error with status %v, got %v", code, err)
+	}
+	if errorObject.Code != code {
+		t.Fatalf("expected status %v, got %v", code, errorObject)
+	}
+}
+
+type twoFactorFixture struct {
+	usecase       ITwoFactorUsecase
+	repository    *fakeTwoFactorRepository
+	user          domain.Users
+	recoveryCodes []string
+}
+
+func newTwoFactorFixture(t *testing.T, recoveryCodes int) *twoFactorFixture {
+	t.Helper()
+
+	secret, _, err := totp.TotpInit(config.TotpConfig{Issuer: "test"}).GenerateSecret("user@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := domain.Users{Id: uuid.New(), TwoFactor: true, TwoFactorKey: secret}
+
+	plainCodes, hashedCodes, err := generateRecoveryCodes(user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range hashedCodes {
+		hashedCodes[i].Id = i + 1
+	}
+
+	twoFactorRepository := &fakeTwoFactorRepository{
+		ITwoFactorRepository: repository.NewTwoFactorRepository(nil, cache.MemoryInit(100)),
+		recoveryCodes:        hashedCodes[:recoveryCodes],
+		deleted:              make(map[int]bool),
+	}
+	userRepository := &fakeUserRepository{users: map[uuid.UUID]domain.Users{user.Id: user}}
+
+	return &twoFactorFixture{
+		usecase: NewTwoFactorUsecase(twoFactorRepository, userRepository,
+			jwt.JwtInit(config.JwtConfig{SecretKey: "secret", ExpiredTime: 1}), totp.TotpInit(config.TotpConfig{Issuer: "test"})),
+		repository:    twoFactorRepository,
+		user:          user,
+		recoveryCodes: plainCodes[:recoveryCodes],
+	}
+}
+
+// login starts a fresh login challenge, as the password step would, and
+// answers it with code.
+func (f *twoFactorFixture) login(t *testing.T, code string) error {
+	t.Helper()
+
+	ctx := context.Background()
+	challenge := uuid.NewString()
+	err := f.repository.CreateChallenge(ctx, challenge, f.user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = f.usecase.Login(ctx, domain.TwoFactorLogin{Challenge: challenge, Code: code})
+	return err
+}
+
+func TestTwoFactorLoginRejectsReplayedCode(t *testing.T) {
+	f := newTwoFactorFixture(t, 0)
+
+	code, err := otptotp.GenerateCode(f.user.TwoFactorKey, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = f.login(t, code)
+	if err != nil {
+		t.Fatalf("expected the first login to pass, got %v", err)
+	}
+
+	err = f.login(t, code)
+	assertErrorCode(t, err, http.StatusUnauthorized)
+}
+
+func TestTwoFactorLoginAcceptsCodeOnlyOnceUnderConcurrency(t *testing.T) {
+	f := newTwoFactorFixture(t, 0)
+
+	code, err := otptotp.GenerateCode(f.user.TwoFactorKey, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const requests = 10
+	errs := make(chan error, requests)
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- f.login(t, code)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	passed := 0
+	for err := range errs {
+		if err == nil {
+			passed++
+		}
+	}
+	if passed != 1 {
+		t.Fatalf("expected exactly one login to pass, got %v", passed)
+	}
+}
+
+func TestTwoFactorLoginConsumesRecoveryCode(t *testing.T) {
+	f := newTwoFactorFixture(t, 2)
+
+	err := f.login(t, f.recoveryCodes[0])
+	if err != nil {
+		t.Fatalf("expected the recovery code to be accepted, got %v", err)
+	}
+
+	err = f.login(t, f.recoveryCodes[0])
+	assertErrorCode(t, err, http.StatusUnauthorized)
+
+	err = f.login(t, f.recoveryCodes[1])
+	if err != nil {
+		t.Fatalf("expected the other recovery code to still be accepted, got %v", err)
+	}
+}
+
+func TestTwoFactorLoginLocksAfterWrongCodes(t *testing.T) {
+	f := newTwoFactorFixture(t, 0)
+
+	for i := 1; i < maxTwoFactorAttempt; i++ {
+		err := f.login(t, "000000")
+		assertErrorCode(t, err, http.StatusUnauthorized)
+	}
+
+	err := f.login(t, "000000")
+	assertErrorCode(t, err, http.StatusTooManyRequests)
+
+	// Once locked, even the right code is refused until the lockout expires.
+	code, err := otptotp.GenerateCode(f.user.TwoFactorKey, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = f.login(t, code)
+	assertErrorCode(t, err, http.StatusTooManyRequests)
+}
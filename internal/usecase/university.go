@@ -1,14 +1,35 @@
 package usecase
 
 import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
 	"intern-bcc/domain"
 	"intern-bcc/internal/repository"
 	"intern-bcc/pkg/response"
+	"io"
+	"mime/multipart"
 	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	universitySearchLimit   = 20
+	universityImportMaxSize = 2 << 20
+	universityImportMaxRows = 10000
+	universityNameMaxLength = 191
 )
 
 type IUniversityUsecase interface {
-	CreateUniversity(universityRequest domain.Universities) error
+	GetUniversities(ctx context.Context, universityParam domain.UniversityParam) ([]domain.UniversityResponse, error)
+	CreateUniversity(ctx context.Context, universityRequest domain.Universities) error
+	ImportUniversities(ctx context.Context, file *multipart.FileHeader) (domain.UniversityImportResponse, error)
+	UpdateUniversity(ctx context.Context, universityId int, universityRequest domain.UniversityRequest) (domain.UniversityResponse, error)
+	DeleteUniversity(ctx context.Context, universityId int) error
 }
 
 type UniversityUsecase struct {
@@ -19,15 +40,157 @@ func NewUniversityUsecase(universityRepository repository.IUniversityRepository)
 	return &UniversityUsecase{universityRepository}
 }
 
-func (u *UniversityUsecase) CreateUniversity(universityRequest domain.Universities) error {
+func (u *UniversityUsecase) GetUniversities(ctx context.Context, universityParam domain.UniversityParam) ([]domain.UniversityResponse, error) {
+	universityParam.Search = strings.TrimSpace(universityParam.Search)
+	if universityParam.Search != "" && (universityParam.Limit <= 0 || universityParam.Limit > universitySearchLimit) {
+		universityParam.Limit = universitySearchLimit
+	}
+
+	var universities []domain.Universities
+	err := u.universityRepository.GetUniversities(ctx, &universities, universityParam)
+	if err != nil {
+		return nil, response.NewError(http.StatusInternalServerError, "an error occured when get universities", err)
+	}
+
+	universityResponses := []domain.UniversityResponse{}
+	for _, university := range universities {
+		universityResponses = append(universityResponses, domain.UniversityResponse{
+			Id:         university.Id,
+			University: university.University,
+			Latitude:   university.Latitude,
+			Longitude:  university.Longitude,
+		})
+	}
+
+	return universityResponses, nil
+}
+
+func (u *UniversityUsecase) CreateUniversity(ctx context.Context, universityRequest domain.Universities) error {
 	newUniversity := domain.Universities{
 		University: universityRequest.University,
+		Latitude:   universityRequest.Latitude,
+		Longitude:  universityRequest.Longitude,
 	}
 
-	err := u.universityRepository.CreateUniversity(&newUniversity)
+	err := u.universityRepository.CreateUniversity(ctx, &newUniversity)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when creating university", err)
 	}
 
 	return nil
 }
+
+func (u *UniversityUsecase) ImportUniversities(ctx context.Context, file *multipart.FileHeader) (domain.UniversityImportResponse, error) {
+	if file.Size > universityImportMaxSize {
+		return domain.UniversityImportResponse{}, response.NewError(http.StatusRequestEntityTooLarge, "file is too large", fmt.Errorf("file size %d exceeds %d bytes", file.Size, universityImportMaxSize))
+	}
+
+	source, err := file.Open()
+	if err != nil {
+		return domain.UniversityImportResponse{}, response.NewError(http.StatusBadRequest, "failed to open file", err)
+	}
+	defer source.Close()
+
+	reader := csv.NewReader(source)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	importResponse := domain.UniversityImportResponse{Errors: []string{}}
+	seen := make(map[string]bool)
+	var names []string
+
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return domain.UniversityImportResponse{}, response.NewError(http.StatusBadRequest, "failed to parse csv", err)
+		}
+
+		name := strings.TrimSpace(strings.TrimPrefix(record[0], "\ufeff"))
+		if line == 1 && isUniversityHeader(name) {
+			continue
+		}
+
+		switch {
+		case name == "":
+			importResponse.Skipped++
+		case !utf8.ValidString(name) || utf8.RuneCountInString(name) > universityNameMaxLength:
+			importResponse.Errors = append(importResponse.Errors, fmt.Sprintf("line %d: university name must be valid text of at most %d characters", line, universityNameMaxLength))
+		case seen[strings.ToLower(name)]:
+			importResponse.Skipped++
+		default:
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+
+		if len(names) > universityImportMaxRows {
+			return domain.UniversityImportResponse{}, response.NewError(http.StatusBadRequest, "too many rows", fmt.Errorf("csv has more than %d universities", universityImportMaxRows))
+		}
+	}
+
+	if len(names) == 0 {
+		return importResponse, nil
+	}
+
+	created, err := u.universityRepository.ImportUniversities(ctx, names)
+	if err != nil {
+		return domain.UniversityImportResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when import universities", err)
+	}
+
+	importResponse.Created = created
+	importResponse.Skipped += len(names) - created
+
+	return importResponse, nil
+}
+
+func (u *UniversityUsecase) UpdateUniversity(ctx context.Context, universityId int, universityRequest domain.UniversityRequest) (domain.UniversityResponse, error) {
+	var university domain.Universities
+	err := u.universityRepository.GetUniversity(ctx, &university, domain.Universities{Id: universityId})
+	if err != nil {
+		return domain.UniversityResponse{}, response.NewError(http.StatusNotFound, "university not found", err)
+	}
+
+	university.University = universityRequest.University
+	if universityRequest.Latitude != nil {
+		university.Latitude = universityRequest.Latitude
+		university.Longitude = universityRequest.Longitude
+	}
+
+	err = u.universityRepository.UpdateUniversity(ctx, &university)
+	if err != nil {
+		return domain.UniversityResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when update university", err)
+	}
+
+	return domain.UniversityResponse{
+		Id:         university.Id,
+		University: university.University,
+		Latitude:   university.Latitude,
+		Longitude:  university.Longitude,
+	}, nil
+}
+
+func (u *UniversityUsecase) DeleteUniversity(ctx context.Context, universityId int) error {
+	err := u.universityRepository.DeleteUniversity(ctx, universityId)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return response.NewError(http.StatusNotFound, "university not found", err)
+	}
+	if errors.Is(err, repository.ErrUniversityHasMerchants) {
+		return response.NewError(http.StatusConflict, "university is still used by merchants", err)
+	}
+	if err != nil {
+		return response.NewError(http.StatusInternalServerError, "an error occured when delete university", err)
+	}
+
+	return nil
+}
+
+func isUniversityHeader(value string) bool {
+	switch strings.ToLower(value) {
+	case "university", "universitas", "nama", "name":
+		return true
+	}
+
+	return false
+}
@@ -0,0 +1,262 @@
+package usecase
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/cache"
+	"intern-bcc/pkg/imaging"
+	"intern-bcc/pkg/jwt"
+	"intern-bcc/pkg/logging"
+	"intern-bcc/pkg/response"
+	"intern-bcc/pkg/storage"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	uploadUrlExpiry  = 15 * time.Minute
+	pendingUploadTtl = time.Hour
+)
+
+type IUploadUsecase interface {
+	RequestUpload(c *gin.Context, ctx context.Context, target domain.UploadTarget, uploadRequest domain.UploadRequest) (domain.UploadResponse, error)
+	OpenUpload(c *gin.Context, ctx context.Context, target domain.UploadTarget, uploadId uuid.UUID) (*multipart.FileHeader, error)
+	FinishUpload(ctx context.Context, uploadId uuid.UUID)
+	SweepUploads(ctx context.Context) error
+	SweepImages(ctx context.Context) error
+}
+
+type UploadUsecase struct {
+	uploadRepository repository.IUploadRepository
+	imageRepository  repository.IImageRepository
+	jwt              jwt.IJwt
+	storage          storage.IStorage
+}
+
+func NewUploadUsecase(uploadRepository repository.IUploadRepository, imageRepository repository.IImageRepository, jwt jwt.IJwt, storage storage.IStorage) IUploadUsecase {
+	return &UploadUsecase{
+		uploadRepository: uploadRepository,
+		imageRepository:  imageRepository,
+		jwt:              jwt,
+		storage:          storage,
+	}
+}
+
+func (u *UploadUsecase) RequestUpload(c *gin.Context, ctx context.Context, target domain.UploadTarget, uploadRequest domain.UploadRequest) (domain.UploadResponse, error) {
+	user, err := u.jwt.GetLoginUser(c)
+	if err != nil {
+		return domain.UploadResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	if !imaging.IsAllowedType(uploadRequest.ContentType) {
+		return domain.UploadResponse{}, imageError(fmt.Errorf("%w: %v", imaging.ErrUnsupportedType, uploadRequest.ContentType))
+	}
+
+	if uploadRequest.Size > imaging.MaxUploadSize {
+		return domain.UploadResponse{}, imageError(fmt.Errorf("%w: %d bytes exceeds %d bytes", imaging.ErrTooLarge, uploadRequest.Size, imaging.MaxUploadSize))
+	}
+
+	uploadId := uuid.New()
+	key := storage.PendingDir + "/" + uploadId.String()
+	presigned, err := u.storage.PresignUpload(ctx, key, uploadRequest.ContentType, uploadRequest.Size, uploadUrlExpiry)
+	if err != nil {
+		return domain.UploadResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when create upload url", err)
+	}
+
+	pendingUpload := domain.PendingUpload{
+		Id:          uploadId,
+		UserId:      user.Id,
+		Target:      target,
+		Key:         key,
+		Link:        presigned.Link,
+		ContentType: uploadRequest.ContentType,
+		Size:        uploadRequest.Size,
+	}
+
+	err = u.uploadRepository.CreatePendingUpload(ctx, pendingUpload, pendingUploadTtl)
+	if err != nil {
+		return domain.UploadResponse{}, cacheError(err, http.StatusInternalServerError, "an error occured when save upload")
+	}
+
+	uploadResponse := domain.UploadResponse{
+		UploadId:  uploadId,
+		Target:    target,
+		Url:       presigned.Url,
+		Method:    presigned.Method,
+		Headers:   presigned.Headers,
+		ExpiresAt: time.Now().Add(uploadUrlExpiry),
+	}
+
+	return uploadResponse, nil
+}
+
+// OpenUpload verifies that a direct upload exists and has the requested size
+// and type, and returns it as an uploaded file, so it is validated and attached
+// exactly like a form upload. An upload that fails verification is discarded.
+func (u *UploadUsecase) OpenUpload(c *gin.Context, ctx context.Context, target domain.UploadTarget, uploadId uuid.UUID) (*multipart.FileHeader, error) {
+	user, err := u.jwt.GetLoginUser(c)
+	if err != nil {
+		return nil, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	var pendingUpload domain.PendingUpload
+	err = u.uploadRepository.GetPendingUpload(ctx, &pendingUpload, uploadId)
+	if errors.Is(err, cache.ErrMiss) {
+		return nil, response.NewError(http.StatusNotFound, "upload not found or expired", err)
+	}
+	if err != nil {
+		return nil, cacheError(err, http.StatusInternalServerError, "an error occured when get upload")
+	}
+
+	err = authorizeOwner(user, pendingUpload.UserId)
+	if err != nil {
+		return nil, err
+	}
+
+	if pendingUpload.Target != target {
+		return nil, response.NewError(http.StatusBadRequest, "upload was requested for another field", errors.New("upload target does not match"))
+	}
+
+	size, err := u.storage.Size(ctx, pendingUpload.Key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return nil, response.NewError(http.StatusBadRequest, "file has not been uploaded yet", err)
+	}
+	if err != nil {
+		return nil, response.NewError(http.StatusInternalServerError, "an error occured when get uploaded file", err)
+	}
+
+	if size != pendingUpload.Size {
+		u.discardUpload(ctx, pendingUpload)
+		return nil, response.NewError(http.StatusBadRequest, "uploaded file size does not match the requested size", fmt.Errorf("uploaded %d bytes, requested %d bytes", size, pendingUpload.Size))
+	}
+
+	data, err := u.storage.Download(ctx, pendingUpload.Key, imaging.MaxUploadSize)
+	if errors.Is(err, storage.ErrTooLarge) {
+		u.discardUpload(ctx, pendingUpload)
+		return nil, imageError(fmt.Errorf("%w: %v", imaging.ErrTooLarge, err))
+	}
+	if err != nil {
+		return nil, response.NewError(http.StatusInternalServerError, "an error occured when get uploaded file", err)
+	}
+
+	contentType, err := imaging.DetectType(data)
+	if err != nil {
+		u.discardUpload(ctx, pendingUpload)
+		return nil, imageError(err)
+	}
+
+	if contentType != pendingUpload.ContentType {
+		u.discardUpload(ctx, pendingUpload)
+		return nil, response.NewError(http.StatusUnsupportedMediaType, "uploaded file type does not match the requested type", fmt.Errorf("uploaded %v, requested %v", contentType, pendingUpload.ContentType))
+	}
+
+	file, err := fileHeader(pendingUpload.Target.Field, contentType, data)
+	if err != nil {
+		return nil, response.NewError(http.StatusInternalServerError, "an error occured when read uploaded file", err)
+	}
+
+	return file, nil
+}
+
+// FinishUpload removes a direct upload once it has been attached, or failed to
+// be, since only its stored renditions are kept.
+func (u *UploadUsecase) FinishUpload(ctx context.Context, uploadId uuid.UUID) {
+	var pendingUpload domain.PendingUpload
+	err := u.uploadRepository.GetPendingUpload(ctx, &pendingUpload, uploadId)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("upload", uploadId).Error("failed to get finished upload")
+		return
+	}
+
+	u.discardUpload(ctx, pendingUpload)
+}
+
+// SweepUploads deletes direct uploads that were never confirmed. An object
+// older than uploadUrlExpiry + pendingUploadTtl has outlived its pending upload,
+// so nothing can attach it anymore.
+func (u *UploadUsecase) SweepUploads(ctx context.Context) error {
+	objects, err := u.storage.List(ctx, storage.PendingDir)
+	if err != nil {
+		return err
+	}
+
+	cutoff := time.Now().Add(-(uploadUrlExpiry + pendingUploadTtl))
+	var deleted int
+	for _, object := range objects {
+		if object.ModifiedAt.After(cutoff) {
+			continue
+		}
+
+		err = u.storage.Delete(ctx, object.Link)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).WithField("key", object.Key).Error("failed to delete abandoned upload")
+			continue
+		}
+		deleted++
+	}
+
+	if deleted > 0 {
+		logging.FromContext(ctx).WithField("count", deleted).Info("deleted abandoned uploads")
+	}
+
+	return nil
+}
+
+// SweepImages deletes stored images that no record ended up using.
+func (u *UploadUsecase) SweepImages(ctx context.Context) error {
+	return sweepImages(ctx, u.storage, u.imageRepository)
+}
+
+func (u *UploadUsecase) discardUpload(ctx context.Context, pendingUpload domain.PendingUpload) {
+	err := u.storage.Delete(ctx, pendingUpload.Link)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("upload", pendingUpload.Id).Error("failed to delete direct upload")
+	}
+
+	err = u.uploadRepository.DeletePendingUpload(ctx, pendingUpload.Id)
+	if err != nil {
+		logging.FromContext(ctx).WithError(err).WithField("upload", pendingUpload.Id).Error("failed to delete pending upload")
+	}
+}
+
+// fileHeader wraps data in a multipart file header, which is what the upload
+// usecases accept.
+func fileHeader(name string, contentType string, data []byte) (*multipart.FileHeader, error) {
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+
+	header := make(textproto.MIMEHeader)
+	header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="file"; filename="%v"`, name))
+	header.Set("Content-Type", contentType)
+
+	part, err := writer.CreatePart(header)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = part.Write(data)
+	if err != nil {
+		return nil, err
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	form, err := multipart.NewReader(&body, writer.Boundary()).ReadForm(int64(len(data)) + 1<<20)
+	if err != nil {
+		return nil, err
+	}
+
+	return form.File["file"][0], nil
+}
@@ -2,11 +2,12 @@ package usecase
 
 import (
 	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/cache"
 	"intern-bcc/pkg/gomail"
 	"intern-bcc/pkg/jwt"
 	"intern-bcc/pkg/midtrans"
-	"intern-bcc/pkg/redis"
-	"intern-bcc/pkg/supabase"
+	"intern-bcc/pkg/storage"
+	"intern-bcc/pkg/totp"
 )
 
 type Usecase struct {
@@ -20,28 +21,38 @@ type Usecase struct {
 	InformationUsecase IInformationUsecase
 	UniversityUsecase  IUniversityUsecase
 	ProvinceUsecase    IProvinceUsecase
+	RegionUsecase      IRegionUsecase
+	TwoFactorUsecase   ITwoFactorUsecase
+	TrashUsecase       ITrashUsecase
+	UploadUsecase      IUploadUsecase
 }
 
 type UsecaseParam struct {
 	Repository *repository.Repository
 	Jwt        jwt.IJwt
-	Supabase   supabase.ISupabase
+	Storage    storage.IStorage
 	Midtrans   midtrans.IMidTrans
 	GoMail     gomail.IGoMail
-	Redis      redis.IRedis
+	Cache      cache.ICache
+	Totp       totp.ITotp
+	BaseUrl    string
 }
 
 func NewUsecase(usecaseParam UsecaseParam) *Usecase {
-	userUsecase := NewUserUsecase(usecaseParam.Repository.UserRepository, usecaseParam.Repository.ProductRepository, usecaseParam.Jwt, usecaseParam.Supabase, usecaseParam.GoMail)
+	userUsecase := NewUserUsecase(usecaseParam.Repository.UserRepository, usecaseParam.Repository.ProductRepository, usecaseParam.Repository.TwoFactorRepository, usecaseParam.Jwt, usecaseParam.Repository.ImageRepository, usecaseParam.Storage, usecaseParam.GoMail, usecaseParam.BaseUrl)
 	transactionUsecase := NewTransactionUsecase(usecaseParam.Repository.TransactionRepository, usecaseParam.Repository.UserRepository, usecaseParam.Repository.MentorRepository, usecaseParam.Jwt, usecaseParam.Midtrans)
-	productUsecase := NewProductUsecase(usecaseParam.Repository.ProductRepository, usecaseParam.Jwt, usecaseParam.Repository.MerchantSQLRepository, usecaseParam.Repository.CategoryRepository, usecaseParam.Supabase)
-	mentorUsecase := NewMentorUsecase(usecaseParam.Repository.MentorRepository, usecaseParam.Jwt, usecaseParam.Supabase)
-	merchantUsecase := NewMerchantUsecase(usecaseParam.Repository.MerchantSQLRepository, usecaseParam.Jwt, usecaseParam.GoMail, usecaseParam.Supabase, usecaseParam.Repository.UniversityRepository, usecaseParam.Repository.ProvinceRepository)
+	productUsecase := NewProductUsecase(usecaseParam.Repository.ProductRepository, usecaseParam.Repository.ProductPhotoRepository, usecaseParam.Jwt, usecaseParam.Repository.MerchantSQLRepository, usecaseParam.Repository.CategoryRepository, usecaseParam.Repository.ImageRepository, usecaseParam.Storage)
+	mentorUsecase := NewMentorUsecase(usecaseParam.Repository.MentorRepository, usecaseParam.Jwt, usecaseParam.Repository.ImageRepository, usecaseParam.Storage)
+	merchantUsecase := NewMerchantUsecase(usecaseParam.Repository.MerchantSQLRepository, usecaseParam.Jwt, usecaseParam.GoMail, usecaseParam.Repository.ImageRepository, usecaseParam.Storage, usecaseParam.Repository.UniversityRepository, usecaseParam.Repository.ProvinceRepository, usecaseParam.Repository.RegionRepository)
 	experienceUsecase := NewExperienceRepository(usecaseParam.Repository.ExperienceRepository)
 	categoryUsecase := NewCategoryUsecase(usecaseParam.Repository.CategoryRepository)
-	informationUsecase := NewInformatinUsecase(usecaseParam.Repository.InformationRepository, usecaseParam.Repository.CategoryRepository, usecaseParam.Supabase)
+	informationUsecase := NewInformatinUsecase(usecaseParam.Repository.InformationRepository, usecaseParam.Repository.CategoryRepository, usecaseParam.Repository.ImageRepository, usecaseParam.Storage)
 	universtiyUsecase := NewUniversityUsecase(usecaseParam.Repository.UniversityRepository)
 	provinceUsecase := NewProvinceUsecase(usecaseParam.Repository.ProvinceRepository)
+	regionUsecase := NewRegionUsecase(usecaseParam.Repository.RegionRepository)
+	trashUsecase := NewTrashUsecase(usecaseParam.Repository.ProductRepository, usecaseParam.Repository.MerchantSQLRepository, usecaseParam.Repository.MentorRepository, usecaseParam.Repository.InformationRepository)
+	twoFactorUsecase := NewTwoFactorUsecase(usecaseParam.Repository.TwoFactorRepository, usecaseParam.Repository.UserRepository, usecaseParam.Jwt, usecaseParam.Totp)
+	uploadUsecase := NewUploadUsecase(usecaseParam.Repository.UploadRepository, usecaseParam.Repository.ImageRepository, usecaseParam.Jwt, usecaseParam.Storage)
 
 	return &Usecase{
 		UserUsecase:        userUsecase,
@@ -54,5 +65,9 @@ func NewUsecase(usecaseParam UsecaseParam) *Usecase {
 		InformationUsecase: informationUsecase,
 		UniversityUsecase:  universtiyUsecase,
 		ProvinceUsecase:    provinceUsecase,
+		RegionUsecase:      regionUsecase,
+		TwoFactorUsecase:   twoFactorUsecase,
+		TrashUsecase:       trashUsecase,
+		UploadUsecase:      uploadUsecase,
 	}
 }
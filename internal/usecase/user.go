@@ -2,19 +2,17 @@ package usecase
 
 import (
 	"errors"
-	"fmt"
 	"intern-bcc/domain"
 	"intern-bcc/internal/repository"
 	"intern-bcc/pkg/gomail"
 	"intern-bcc/pkg/jwt"
+	"intern-bcc/pkg/logging"
 	"intern-bcc/pkg/response"
-	"intern-bcc/pkg/supabase"
+	"intern-bcc/pkg/storage"
 	"math/rand"
 	"mime/multipart"
 	"net/http"
-	"os"
 	"strings"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -23,42 +21,52 @@ import (
 )
 
 type IUserUsecase interface {
-	GetUser(param domain.UserParam) (domain.Users, error)
-	GetLikeProducts(c *gin.Context) ([]domain.ProductResponses, error)
-	GetOwnProducts(c *gin.Context) ([]domain.ProductResponses, error)
-	GetOwnMentors(c *gin.Context) ([]domain.OwnMentorResponses, error)
-	Register(userRequest domain.UserRequest) error
-	Login(userLogin domain.UserLogin) (domain.LoginResponse, error)
-	UpdateUser(c *gin.Context, userId uuid.UUID, userUpdate domain.UserUpdate) (domain.UserResponse, error)
-	UploadUserPhoto(c *gin.Context, userId uuid.UUID, userPhoto *multipart.FileHeader) (domain.UserResponse, error)
+	GetUser(ctx context.Context, param domain.UserParam) (domain.Users, error)
+	GetProfile(c *gin.Context) (domain.UserResponse, error)
+	GetPublicProfile(ctx context.Context, param domain.UserParam) (domain.UserPublicResponse, error)
+	GetLikeProducts(c *gin.Context, ctx context.Context) ([]domain.ProductResponses, error)
+	GetOwnProducts(c *gin.Context, ctx context.Context) ([]domain.ProductResponses, error)
+	GetOwnMentors(c *gin.Context, ctx context.Context) ([]domain.OwnMentorResponses, error)
+	Register(ctx context.Context, userRequest domain.UserRequest) error
+	Login(ctx context.Context, userLogin domain.UserLogin) (domain.LoginResponse, error)
+	UpdateUser(c *gin.Context, ctx context.Context, userUpdate domain.UserUpdate) (domain.UserResponse, error)
+	UploadUserPhoto(c *gin.Context, userPhoto *multipart.FileHeader) (domain.UserResponse, error)
 	PasswordRecovery(userParam domain.UserParam, ctx context.Context) error
 	ChangePassword(ctx context.Context, name string, verPass string, passwordRequest domain.PasswordUpdate) error
-	LikeProduct(c *gin.Context, productId uuid.UUID) error
-	DeleteLikeProduct(c *gin.Context, productId uuid.UUID) error
+	LikeProduct(c *gin.Context, ctx context.Context, productId uuid.UUID) error
+	DeleteLikeProduct(c *gin.Context, ctx context.Context, productId uuid.UUID) error
+	ExportUserData(c *gin.Context, ctx context.Context) (domain.UserDataExport, error)
+	DeleteAccount(c *gin.Context, ctx context.Context, deleteRequest domain.DeleteAccountRequest) error
 }
 
 type UserUsecase struct {
-	userRepository    repository.IUserRepository
-	productRepository repository.IProductRepository
-	jwt               jwt.IJwt
-	supabase          supabase.ISupabase
-	goMail            gomail.IGoMail
+	userRepository      repository.IUserRepository
+	productRepository   repository.IProductRepository
+	twoFactorRepository repository.ITwoFactorRepository
+	jwt                 jwt.IJwt
+	imageRepository     repository.IImageRepository
+	storage             storage.IStorage
+	goMail              gomail.IGoMail
+	baseUrl             string
 }
 
-func NewUserUsecase(userRepository repository.IUserRepository, productRepository repository.IProductRepository, jwt jwt.IJwt,
-	supabase supabase.ISupabase, goMail gomail.IGoMail) IUserUsecase {
+func NewUserUsecase(userRepository repository.IUserRepository, productRepository repository.IProductRepository,
+	twoFactorRepository repository.ITwoFactorRepository, jwt jwt.IJwt, imageRepository repository.IImageRepository, storage storage.IStorage, goMail gomail.IGoMail, baseUrl string) IUserUsecase {
 	return &UserUsecase{
-		userRepository:    userRepository,
-		productRepository: productRepository,
-		jwt:               jwt,
-		supabase:          supabase,
-		goMail:            goMail,
+		userRepository:      userRepository,
+		productRepository:   productRepository,
+		twoFactorRepository: twoFactorRepository,
+		jwt:                 jwt,
+		imageRepository:     imageRepository,
+		storage:             storage,
+		goMail:              goMail,
+		baseUrl:             baseUrl,
 	}
 }
 
-func (u *UserUsecase) GetUser(param domain.UserParam) (domain.Users, error) {
+func (u *UserUsecase) GetUser(ctx context.Context, param domain.UserParam) (domain.Users, error) {
 	var user domain.Users
-	err := u.userRepository.GetUser(&user, param)
+	err := u.userRepository.GetUser(ctx, &user, param)
 	if err != nil {
 		return domain.Users{}, response.NewError(http.StatusNotFound, "an error occured when get user", err)
 	}
@@ -66,13 +74,50 @@ func (u *UserUsecase) GetUser(param domain.UserParam) (domain.Users, error) {
 	return user, nil
 }
 
-func (u *UserUsecase) GetLikeProducts(c *gin.Context) ([]domain.ProductResponses, error) {
+func (u *UserUsecase) GetProfile(c *gin.Context) (domain.UserResponse, error) {
+	user, err := u.jwt.GetLoginUser(c)
+	if err != nil {
+		return domain.UserResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	userResponse := domain.UserResponse{
+		Id:                  user.Id,
+		Name:                user.Name,
+		Email:               user.Email,
+		Gender:              user.Gender,
+		PlaceBirth:          user.PlaceBirth,
+		DateBirth:           user.DateBirth,
+		ProfilePicture:      user.ProfilePicture,
+		ProfilePictureSizes: user.ProfilePictureSizes.Or(user.ProfilePicture),
+	}
+
+	return userResponse, nil
+}
+
+func (u *UserUsecase) GetPublicProfile(ctx context.Context, param domain.UserParam) (domain.UserPublicResponse, error) {
+	var user domain.Users
+	err := u.userRepository.GetUser(ctx, &user, param)
+	if err != nil {
+		return domain.UserPublicResponse{}, response.NewError(http.StatusNotFound, "an error occured when get user", err)
+	}
+
+	userResponse := domain.UserPublicResponse{
+		Id:                  user.Id,
+		Name:                user.Name,
+		ProfilePicture:      user.ProfilePicture,
+		ProfilePictureSizes: user.ProfilePictureSizes.Or(user.ProfilePicture),
+	}
+
+	return userResponse, nil
+}
+
+func (u *UserUsecase) GetLikeProducts(c *gin.Context, ctx context.Context) ([]domain.ProductResponses, error) {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return []domain.ProductResponses{}, response.NewError(http.StatusInternalServerError, "an error occured when get login user", err)
 	}
 
-	err = u.userRepository.GetLikeProducts(&user, user.Id)
+	err = u.userRepository.GetLikeProducts(ctx, &user, user.Id)
 	if err != nil {
 		return []domain.ProductResponses{}, response.NewError(http.StatusInternalServerError, "an error occured when get liked product", err)
 	}
@@ -85,7 +130,7 @@ func (u *UserUsecase) GetLikeProducts(c *gin.Context) ([]domain.ProductResponses
 			MerchantName: lk.Merchant.MerchantName,
 			University:   lk.Merchant.University.University,
 			Price:        lk.Price,
-			ProductPhoto: lk.ProductPhoto,
+			ProductPhoto: lk.ProductPhotoSizes.Or(lk.ProductPhoto).Thumbnail,
 		}
 
 		productResponses = append(productResponses, productResponse)
@@ -94,13 +139,13 @@ func (u *UserUsecase) GetLikeProducts(c *gin.Context) ([]domain.ProductResponses
 	return productResponses, nil
 }
 
-func (u *UserUsecase) GetOwnProducts(c *gin.Context) ([]domain.ProductResponses, error) {
+func (u *UserUsecase) GetOwnProducts(c *gin.Context, ctx context.Context) ([]domain.ProductResponses, error) {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return []domain.ProductResponses{}, response.NewError(http.StatusInternalServerError, "failed to get login user", err)
 	}
 
-	err = u.userRepository.GetOwnProducts(&user, user.Id)
+	err = u.userRepository.GetOwnProducts(ctx, &user, user.Id)
 	if err != nil {
 		return []domain.ProductResponses{}, response.NewError(http.StatusInternalServerError, "an error occured when get own product", err)
 	}
@@ -113,7 +158,7 @@ func (u *UserUsecase) GetOwnProducts(c *gin.Context) ([]domain.ProductResponses,
 			MerchantName: user.Merchant.MerchantName,
 			University:   user.Merchant.University.University,
 			Price:        p.Price,
-			ProductPhoto: p.ProductPhoto,
+			ProductPhoto: p.ProductPhotoSizes.Or(p.ProductPhoto).Thumbnail,
 		}
 
 		productResponses = append(productResponses, productResponse)
@@ -122,13 +167,13 @@ func (u *UserUsecase) GetOwnProducts(c *gin.Context) ([]domain.ProductResponses,
 	return productResponses, nil
 }
 
-func (u *UserUsecase) GetOwnMentors(c *gin.Context) ([]domain.OwnMentorResponses, error) {
+func (u *UserUsecase) GetOwnMentors(c *gin.Context, ctx context.Context) ([]domain.OwnMentorResponses, error) {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return []domain.OwnMentorResponses{}, response.NewError(http.StatusInternalServerError, "failed to get login user", err)
 	}
 
-	err = u.userRepository.GetOwnMentors(&user, user.Id)
+	err = u.userRepository.GetOwnMentors(ctx, &user, user.Id)
 	if err != nil {
 		return []domain.OwnMentorResponses{}, response.NewError(http.StatusInternalServerError, "an error occured when get own mentors", err)
 	}
@@ -139,7 +184,7 @@ func (u *UserUsecase) GetOwnMentors(c *gin.Context) ([]domain.OwnMentorResponses
 			Id:            m.Id,
 			Name:          m.Name,
 			CurrentJob:    m.CurrentJob,
-			MentorPicture: m.MentorPicture,
+			MentorPicture: m.MentorPictureSizes.Or(m.MentorPicture).Thumbnail,
 		}
 
 		ownMentorResponses = append(ownMentorResponses, ownMentorResponse)
@@ -148,7 +193,7 @@ func (u *UserUsecase) GetOwnMentors(c *gin.Context) ([]domain.OwnMentorResponses
 	return ownMentorResponses, nil
 }
 
-func (u *UserUsecase) Register(userRequest domain.UserRequest) error {
+func (u *UserUsecase) Register(ctx context.Context, userRequest domain.UserRequest) error {
 	hashPassword, err := bcrypt.GenerateFromPassword([]byte(userRequest.Password), 10)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "error when hashing password", err)
@@ -161,7 +206,7 @@ func (u *UserUsecase) Register(userRequest domain.UserRequest) error {
 		Password: string(hashPassword),
 	}
 
-	err = u.userRepository.Register(&newUser)
+	err = u.userRepository.Register(ctx, &newUser)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "error occured when creating user", err)
 	}
@@ -169,9 +214,9 @@ func (u *UserUsecase) Register(userRequest domain.UserRequest) error {
 	return nil
 }
 
-func (u *UserUsecase) Login(userLogin domain.UserLogin) (domain.LoginResponse, error) {
+func (u *UserUsecase) Login(ctx context.Context, userLogin domain.UserLogin) (domain.LoginResponse, error) {
 	var user domain.Users
-	err := u.userRepository.GetUser(&user, domain.UserParam{
+	err := u.userRepository.GetUser(ctx, &user, domain.UserParam{
 		Email: userLogin.Email,
 	})
 	if err != nil {
@@ -180,9 +225,29 @@ func (u *UserUsecase) Login(userLogin domain.UserLogin) (domain.LoginResponse, e
 
 	err = bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(userLogin.Password))
 	if err != nil {
+		logging.FromContext(ctx).WithField("user_id", user.Id).Warn("login with wrong password")
 		return domain.LoginResponse{}, response.NewError(http.StatusNotFound, "email or password invalid", err)
 	}
 
+	if user.TwoFactor {
+		challenge, err := generateRandomString(32, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ1234567890")
+		if err != nil {
+			return domain.LoginResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when create login session", err)
+		}
+
+		err = u.twoFactorRepository.CreateChallenge(ctx, challenge, user.Id)
+		if err != nil {
+			return domain.LoginResponse{}, cacheError(err, http.StatusInternalServerError, "an error occured when create login session")
+		}
+
+		loginUser := domain.LoginResponse{
+			TwoFactorRequired: true,
+			Challenge:         challenge,
+		}
+
+		return loginUser, nil
+	}
+
 	tokenString, err := u.jwt.GenerateToken(user.Id)
 	if err != nil {
 		return domain.LoginResponse{}, response.NewError(http.StatusInternalServerError, "failed to generate jwt token", err)
@@ -195,84 +260,75 @@ func (u *UserUsecase) Login(userLogin domain.UserLogin) (domain.LoginResponse, e
 	return loginUser, nil
 }
 
-func (u *UserUsecase) UpdateUser(c *gin.Context, userId uuid.UUID, userUpdate domain.UserUpdate) (domain.UserResponse, error) {
+func (u *UserUsecase) UpdateUser(c *gin.Context, ctx context.Context, userUpdate domain.UserUpdate) (domain.UserResponse, error) {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return domain.UserResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
 	}
 
-	if userId != user.Id {
-		return domain.UserResponse{}, response.NewError(http.StatusUnauthorized, "access denied", errors.New("can not change other people profile"))
-	}
-
-	err = u.userRepository.UpdateUser(&userUpdate, userId)
+	err = u.userRepository.UpdateUser(ctx, &userUpdate, user.Id)
 	if err != nil {
 		return domain.UserResponse{}, response.NewError(http.StatusInternalServerError, "error occured when update user", err)
 	}
 
 	var updatedUser domain.Users
-	err = u.userRepository.GetUser(&updatedUser, domain.UserParam{Id: userId})
+	err = u.userRepository.GetUser(ctx, &updatedUser, domain.UserParam{Id: user.Id})
 	if err != nil {
 		return domain.UserResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when get updated user", err)
 	}
 
 	updatedUserResponse := domain.UserResponse{
-		Id:         updatedUser.Id,
-		Name:       updatedUser.Name,
-		Email:      updatedUser.Email,
-		Gender:     updatedUser.Gender,
-		PlaceBirth: updatedUser.PlaceBirth,
-		DateBirth:  updatedUser.DateBirth,
-		ProfilePicture: updatedUser.ProfilePicture,
+		Id:                  updatedUser.Id,
+		Name:                updatedUser.Name,
+		Email:               updatedUser.Email,
+		Gender:              updatedUser.Gender,
+		PlaceBirth:          updatedUser.PlaceBirth,
+		DateBirth:           updatedUser.DateBirth,
+		ProfilePicture:      updatedUser.ProfilePicture,
+		ProfilePictureSizes: updatedUser.ProfilePictureSizes.Or(updatedUser.ProfilePicture),
 	}
 
 	return updatedUserResponse, nil
 }
 
-func (u *UserUsecase) UploadUserPhoto(c *gin.Context, userId uuid.UUID, userPhoto *multipart.FileHeader) (domain.UserResponse, error) {
+func (u *UserUsecase) UploadUserPhoto(c *gin.Context, userPhoto *multipart.FileHeader) (domain.UserResponse, error) {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return domain.UserResponse{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
 	}
 
-	if user.Id != userId {
-		return domain.UserResponse{}, response.NewError(http.StatusUnauthorized, "access denied", errors.New("can not change other people profile"))
-	}
-
-	if user.ProfilePicture != "" {
-		err = u.supabase.Delete(user.ProfilePicture)
-		if err != nil {
-			return domain.UserResponse{}, response.NewError(http.StatusInternalServerError, "error occured when deleting old profile picture", err)
-		}
-	}
-
-	userPhoto.Filename = fmt.Sprintf("%v-%v", time.Now().String(), userPhoto.Filename)
-	userPhoto.Filename = strings.Replace(userPhoto.Filename, " ", "-", -1)
-
-	newProfilePicture, err := u.supabase.Upload(userPhoto)
+	ctx := c.Request.Context()
+	newProfilePicture, err := uploadImage(ctx, u.storage, u.imageRepository, userPhoto)
 	if err != nil {
-		return domain.UserResponse{}, response.NewError(http.StatusInternalServerError, "failed to upload photo", err)
+		return domain.UserResponse{}, err
 	}
 
-	err = u.userRepository.UpdateUser(&domain.UserUpdate{ProfilePicture: newProfilePicture}, user.Id)
+	err = u.userRepository.UpdateUser(ctx, &domain.UserUpdate{
+		ProfilePicture:      newProfilePicture.Original,
+		ProfilePictureSizes: newProfilePicture.Sizes,
+	}, user.Id)
 	if err != nil {
+		removeImage(ctx, u.storage, u.imageRepository, newProfilePicture)
 		return domain.UserResponse{}, response.NewError(http.StatusInternalServerError, "error occured when update user", err)
 	}
 
+	removeImage(ctx, u.storage, u.imageRepository, domain.UploadedImage{Original: user.ProfilePicture, Sizes: user.ProfilePictureSizes})
+
 	var updatedUser domain.Users
-	err = u.userRepository.GetUser(&updatedUser, domain.UserParam{Id: user.Id})
+	err = u.userRepository.GetUser(ctx, &updatedUser, domain.UserParam{Id: user.Id})
 	if err != nil {
 		return domain.UserResponse{}, response.NewError(http.StatusInternalServerError, "an error occured when get updated user", err)
 	}
 
 	updatedUserResponse := domain.UserResponse{
-		Id:         updatedUser.Id,
-		Name:       updatedUser.Name,
-		Email:      updatedUser.Email,
-		Gender:     updatedUser.Gender,
-		PlaceBirth: updatedUser.PlaceBirth,
-		DateBirth:  updatedUser.DateBirth,
-		ProfilePicture: updatedUser.ProfilePicture,
+		Id:                  updatedUser.Id,
+		Name:                updatedUser.Name,
+		Email:               updatedUser.Email,
+		Gender:              updatedUser.Gender,
+		PlaceBirth:          updatedUser.PlaceBirth,
+		DateBirth:           updatedUser.DateBirth,
+		ProfilePicture:      updatedUser.ProfilePicture,
+		ProfilePictureSizes: updatedUser.ProfilePictureSizes.Or(updatedUser.ProfilePicture),
 	}
 
 	return updatedUserResponse, nil
@@ -280,7 +336,7 @@ func (u *UserUsecase) UploadUserPhoto(c *gin.Context, userId uuid.UUID, userPhot
 
 func (u *UserUsecase) PasswordRecovery(userParam domain.UserParam, ctx context.Context) error {
 	var user domain.Users
-	err := u.userRepository.GetUser(&user, domain.UserParam{Email: userParam.Email})
+	err := u.userRepository.GetUser(ctx, &user, domain.UserParam{Email: userParam.Email})
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "account not found", err)
 	}
@@ -298,20 +354,16 @@ func (u *UserUsecase) PasswordRecovery(userParam domain.UserParam, ctx context.C
 
 	err = u.userRepository.CreatePasswordVerification(ctx, emailVerHash, userName)
 	if err != nil {
-		return response.NewError(http.StatusInternalServerError, "error occured when send email", err)
+		return cacheError(err, http.StatusInternalServerError, "error occured when send email")
 	}
 
-	address := os.Getenv("ADDRESS")
-	port := os.Getenv("PORT")
-	domainName := fmt.Sprintf("%v:%v", address, port)
-
 	subject := "Account Recovery"
 	htmlBody := `<html>
 	<h1>Click Link to Change Password</h1>
-	<h2><a href="http://` + domainName + `/api/v1/recoveryaccount/` + userName + `/` + emailVerPassword + `">click here</a></h2>
+	<h2><a href="` + u.baseUrl + `/api/v1/recoveryaccount/` + userName + `/` + emailVerPassword + `">click here</a></h2>
 	</html>`
 
-	err = u.goMail.SendGoMail(subject, htmlBody, user.Email)
+	err = u.goMail.SendGoMail(ctx, subject, htmlBody, user.Email)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when send email", err)
 	}
@@ -322,7 +374,7 @@ func (u *UserUsecase) PasswordRecovery(userParam domain.UserParam, ctx context.C
 func (u *UserUsecase) ChangePassword(ctx context.Context, name string, verPass string, passwordRequest domain.PasswordUpdate) error {
 	verPassHash, err := u.userRepository.GetPasswordVerification(ctx, name)
 	if err != nil {
-		return response.NewError(http.StatusInternalServerError, "an error occured when get ver password from database", err)
+		return cacheError(err, http.StatusInternalServerError, "an error occured when get ver password from database")
 	}
 
 	err = bcrypt.CompareHashAndPassword([]byte(verPassHash), []byte(verPass))
@@ -345,16 +397,23 @@ func (u *UserUsecase) ChangePassword(ctx context.Context, name string, verPass s
 	}
 
 	var user domain.Users
-	err = u.userRepository.GetUser(&user, domain.UserParam{Name: userName})
+	err = u.userRepository.GetUser(ctx, &user, domain.UserParam{Name: userName})
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "can not to find user", err)
 	}
 
-	err = u.userRepository.UpdateUser(&domain.UserUpdate{Password: string(hashedPass)}, user.Id)
+	err = u.userRepository.UpdateUser(ctx, &domain.UserUpdate{Password: string(hashedPass)}, user.Id)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when update password", err)
 	}
 
+	err = u.userRepository.DeletePasswordVerification(ctx, name)
+	if err != nil {
+		return cacheError(err, http.StatusInternalServerError, "an error occured when delete verification code")
+	}
+
+	logging.FromContext(ctx).WithField("user_id", user.Id).Info("password changed through account recovery")
+
 	return nil
 }
 
@@ -376,14 +435,14 @@ func newEmailVerification() (string, string, error) {
 	return emailVerPassword, emailVerHash, nil
 }
 
-func (u *UserUsecase) LikeProduct(c *gin.Context, productId uuid.UUID) error {
+func (u *UserUsecase) LikeProduct(c *gin.Context, ctx context.Context, productId uuid.UUID) error {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "an error occured when get login user", err)
 	}
 
 	var product domain.Products
-	err = u.productRepository.GetProduct(&product, domain.ProductParam{Id: productId})
+	err = u.productRepository.GetProduct(ctx, &product, domain.ProductParam{Id: productId})
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "failed to find product", err)
 	}
@@ -393,7 +452,7 @@ func (u *UserUsecase) LikeProduct(c *gin.Context, productId uuid.UUID) error {
 		ProductId: product.Id,
 	}
 
-	err = u.userRepository.LikeProduct(&likeProduct)
+	err = u.userRepository.LikeProduct(ctx, &likeProduct)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "an error occured when like product", err)
 	}
@@ -401,22 +460,168 @@ func (u *UserUsecase) LikeProduct(c *gin.Context, productId uuid.UUID) error {
 	return nil
 }
 
-func (u *UserUsecase) DeleteLikeProduct(c *gin.Context, productId uuid.UUID) error {
+func (u *UserUsecase) DeleteLikeProduct(c *gin.Context, ctx context.Context, productId uuid.UUID) error {
 	user, err := u.jwt.GetLoginUser(c)
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "an error occured when get login user", err)
 	}
 
 	var likedProduct domain.LikeProduct
-	err = u.userRepository.GetLikeProduct(&likedProduct, domain.LikeProduct{UserId: user.Id, ProductId: productId})
+	err = u.userRepository.GetLikeProduct(ctx, &likedProduct, domain.LikeProduct{UserId: user.Id, ProductId: productId})
 	if err != nil {
 		return response.NewError(http.StatusNotFound, "an error occured when get liked product", err)
 	}
 
-	err = u.userRepository.DeleteLikeProduct(&likedProduct)
+	err = u.userRepository.DeleteLikeProduct(ctx, &likedProduct)
 	if err != nil {
 		return response.NewError(http.StatusInternalServerError, "failed to deleted liked product", err)
 	}
 
 	return nil
 }
+
+func (u *UserUsecase) ExportUserData(c *gin.Context, ctx context.Context) (domain.UserDataExport, error) {
+	loginUser, err := u.jwt.GetLoginUser(c)
+	if err != nil {
+		return domain.UserDataExport{}, response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	var user domain.Users
+	err = u.userRepository.GetUserData(ctx, &user, loginUser.Id)
+	if err != nil {
+		return domain.UserDataExport{}, response.NewError(http.StatusInternalServerError, "an error occured when get user data", err)
+	}
+
+	userData := domain.UserDataExport{
+		Profile: domain.UserResponse{
+			Id:             user.Id,
+			Name:           user.Name,
+			Email:          user.Email,
+			Gender:         user.Gender,
+			PlaceBirth:     user.PlaceBirth,
+			DateBirth:      user.DateBirth,
+			ProfilePicture: user.ProfilePicture,
+		},
+	}
+
+	for _, lk := range user.LikeProduct {
+		userData.LikedProducts = append(userData.LikedProducts, domain.ProductResponses{
+			Id:           lk.Id,
+			Name:         lk.Name,
+			MerchantName: lk.Merchant.MerchantName,
+			University:   lk.Merchant.University.University,
+			Price:        lk.Price,
+			ProductPhoto: lk.ProductPhoto,
+		})
+	}
+
+	if user.Merchant.Id != uuid.Nil {
+		userData.Merchant = &domain.MerchantProfileResponse{
+			Id:            user.Merchant.Id,
+			MerchantName:  user.Merchant.MerchantName,
+			Province:      user.Merchant.Province.Province,
+			City:          user.Merchant.City,
+			University:    user.Merchant.University.University,
+			Faculty:       user.Merchant.Faculty,
+			PhoneNumber:   user.Merchant.PhoneNumber,
+			Instagram:     user.Merchant.Instagram,
+			MerchantPhoto: user.Merchant.MerchantPhoto,
+		}
+
+		for _, p := range user.Merchant.Products {
+			userData.Products = append(userData.Products, domain.ProductProfileResponse{
+				Id:           p.Id,
+				Name:         p.Name,
+				Description:  p.Description,
+				Category:     p.Category.Category,
+				Price:        p.Price,
+				ProductPhoto: p.ProductPhoto,
+			})
+		}
+	}
+
+	for _, t := range user.Transactions {
+		userData.Transactions = append(userData.Transactions, domain.TransactionExport{
+			Id:          t.Id,
+			MentorName:  t.Mentor.Name,
+			Price:       t.Price,
+			PaymentType: t.PaymentType,
+			IsPayed:     t.IsPayed,
+			CreatedAt:   t.CreatedAt,
+			PayedAt:     t.PayedAt,
+		})
+	}
+
+	for _, m := range user.HasMentors {
+		userData.Mentors = append(userData.Mentors, domain.OwnMentorResponses{
+			Id:            m.Id,
+			Name:          m.Name,
+			CurrentJob:    m.CurrentJob,
+			MentorPicture: m.MentorPicture,
+		})
+	}
+
+	return userData, nil
+}
+
+func (u *UserUsecase) DeleteAccount(c *gin.Context, ctx context.Context, deleteRequest domain.DeleteAccountRequest) error {
+	loginUser, err := u.jwt.GetLoginUser(c)
+	if err != nil {
+		return response.NewError(http.StatusNotFound, "an error occured when get login user", err)
+	}
+
+	if loginUser.IsAdmin {
+		return response.NewError(http.StatusForbidden, "failed to delete account", errors.New("admin account can not be deleted"))
+	}
+
+	err = bcrypt.CompareHashAndPassword([]byte(loginUser.Password), []byte(deleteRequest.Password))
+	if err != nil {
+		return response.NewError(http.StatusUnauthorized, "password invalid", err)
+	}
+
+	var user domain.Users
+	err = u.userRepository.GetUserData(ctx, &user, loginUser.Id)
+	if err != nil {
+		return response.NewError(http.StatusInternalServerError, "an error occured when get user data", err)
+	}
+
+	images := []domain.UploadedImage{
+		{Original: user.ProfilePicture, Sizes: user.ProfilePictureSizes},
+		{Original: user.Merchant.MerchantPhoto, Sizes: user.Merchant.MerchantPhotoSizes},
+	}
+	for _, p := range user.Merchant.Products {
+		if len(p.Photos) == 0 {
+			images = append(images, domain.UploadedImage{Original: p.ProductPhoto, Sizes: p.ProductPhotoSizes})
+		}
+		for _, photo := range p.Photos {
+			images = append(images, domain.UploadedImage{Original: photo.Url, Sizes: photo.Sizes})
+		}
+	}
+
+	var photos []string
+	for _, image := range images {
+		photos = append(photos, image.Links()...)
+	}
+
+	err = u.userRepository.DeleteUser(ctx, user)
+	if err != nil {
+		return response.NewError(http.StatusInternalServerError, "an error occured when delete account", err)
+	}
+
+	logging.FromContext(ctx).WithField("user_id", user.Id).Info("account deleted")
+
+	var photoErrors []error
+	for _, photo := range photos {
+		err = deleteUnusedFile(ctx, u.storage, u.imageRepository, photo)
+		if err != nil {
+			logging.FromContext(ctx).WithError(err).WithField("photo", photo).Error("failed to delete photo of deleted account")
+			photoErrors = append(photoErrors, err)
+		}
+	}
+
+	if len(photoErrors) > 0 {
+		return response.NewError(http.StatusInternalServerError, "account deleted, but failed to delete some photos", errors.Join(photoErrors...))
+	}
+
+	return nil
+}
@@ -0,0 +1,31 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	ErrMiss        = errors.New("cache miss")
+	ErrUnavailable = errors.New("cache unavailable")
+)
+
+type ICache interface {
+	Set(ctx context.Context, key string, data string, ttl time.Duration) error
+	// SetNX stores data only when key does not exist yet and reports whether
+	// it did.
+	SetNX(ctx context.Context, key string, data string, ttl time.Duration) (bool, error)
+	Get(ctx context.Context, key string) (string, error)
+	Delete(ctx context.Context, key string) error
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// TagGeneration returns a value that grows with every tag invalidation.
+	TagGeneration(ctx context.Context) (int64, error)
+	// SetWithTags stores data under key and tags unless one of the tags was
+	// invalidated after generation was read, and reports whether it did, so
+	// data loaded before an invalidation is never cached after it.
+	SetWithTags(ctx context.Context, key string, data string, ttl time.Duration, generation int64, tags ...string) (bool, error)
+	InvalidateTags(ctx context.Context, tags ...string) error
+	Ping(ctx context.Context) error
+	Close() error
+}
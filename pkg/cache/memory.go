@@ -0,0 +1,272 @@
+package cache
+
+import (
+	"container/list"
+	"context"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	memorySweepInterval = time.Minute
+	// memoryGenerationTTL is how long the generation of an invalidated tag is
+	// kept. It only has to outlive the loads that started before the
+	// invalidation, which take seconds.
+	memoryGenerationTTL = time.Hour
+)
+
+type memoryEntry struct {
+	key       string
+	data      string
+	expiresAt time.Time
+	tags      []string
+}
+
+type memoryTagGeneration struct {
+	generation    int64
+	invalidatedAt time.Time
+}
+
+// Memory holds at most capacity entries, each in one of two LRU lists. Tagged
+// entries are listing pages that can always be rebuilt, while untagged entries
+// hold OTPs, attempt counters, rate limits and other state, so a full cache
+// evicts tagged entries first and only evicts untagged ones when nothing else
+// is left. A flood of listing requests therefore never pushes out an OTP.
+type Memory struct {
+	mu        sync.Mutex
+	capacity  int
+	entries   map[string]*list.Element
+	order     *list.List
+	untagged  *list.List
+	tags      map[string]map[string]bool
+	nextSweep time.Time
+
+	generation     int64
+	tagGenerations map[string]memoryTagGeneration
+}
+
+func MemoryInit(capacity int) ICache {
+	return &Memory{
+		capacity: capacity,
+		entries:  make(map[string]*list.Element),
+		order:    list.New(),
+		untagged: list.New(),
+		tags:     make(map[string]map[string]bool),
+
+		tagGenerations: make(map[string]memoryTagGeneration),
+	}
+}
+
+func (m *Memory) Set(ctx context.Context, key string, data string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.set(key, data, ttl, nil)
+	return nil
+}
+
+func (m *Memory) SetNX(ctx context.Context, key string, data string, ttl time.Duration) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.get(key) != nil {
+		return false, nil
+	}
+
+	m.set(key, data, ttl, nil)
+	return true, nil
+}
+
+func (m *Memory) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := m.get(key)
+	if entry == nil {
+		return "", ErrMiss
+	}
+
+	return entry.data, nil
+}
+
+func (m *Memory) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.delete(key)
+	return nil
+}
+
+func (m *Memory) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry := m.get(key)
+	if entry == nil {
+		m.set(key, "1", ttl, nil)
+		return 1, nil
+	}
+
+	count, err := strconv.ParseInt(entry.data, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	count++
+	entry.data = strconv.FormatInt(count, 10)
+
+	return count, nil
+}
+
+func (m *Memory) TagGeneration(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.generation, nil
+}
+
+func (m *Memory) SetWithTags(ctx context.Context, key string, data string, ttl time.Duration, generation int64, tags ...string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, tag := range tags {
+		if m.tagGenerations[tag].generation > generation {
+			return false, nil
+		}
+	}
+
+	m.set(key, data, ttl, tags)
+	return true, nil
+}
+
+func (m *Memory) InvalidateTags(ctx context.Context, tags ...string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.generation++
+	now := time.Now()
+	for _, tag := range tags {
+		m.tagGenerations[tag] = memoryTagGeneration{generation: m.generation, invalidatedAt: now}
+
+		for key := range m.tags[tag] {
+			if element, ok := m.entries[key]; ok {
+				m.remove(element)
+			}
+		}
+		delete(m.tags, tag)
+	}
+	m.sweep()
+
+	return nil
+}
+
+func (m *Memory) Ping(ctx context.Context) error {
+	return nil
+}
+
+func (m *Memory) Close() error {
+	return nil
+}
+
+func (m *Memory) get(key string) *memoryEntry {
+	element, ok := m.entries[key]
+	if !ok {
+		return nil
+	}
+
+	entry := element.Value.(*memoryEntry)
+	if time.Now().After(entry.expiresAt) {
+		m.remove(element)
+		return nil
+	}
+
+	m.list(entry).MoveToFront(element)
+	return entry
+}
+
+func (m *Memory) set(key string, data string, ttl time.Duration, tags []string) {
+	m.delete(key)
+
+	entry := &memoryEntry{
+		key:       key,
+		data:      data,
+		expiresAt: time.Now().Add(ttl),
+		tags:      tags,
+	}
+
+	m.entries[key] = m.list(entry).PushFront(entry)
+
+	for _, tag := range tags {
+		if m.tags[tag] == nil {
+			m.tags[tag] = make(map[string]bool)
+		}
+		m.tags[tag][key] = true
+	}
+
+	m.sweep()
+	m.evict()
+}
+
+// evict removes the least recently used entries until the cache is back
+// within capacity, taking tagged entries before untagged ones.
+func (m *Memory) evict() {
+	for m.capacity > 0 && len(m.entries) > m.capacity {
+		if m.order.Len() > 0 {
+			m.remove(m.order.Back())
+		} else {
+			m.remove(m.untagged.Back())
+		}
+	}
+}
+
+func (m *Memory) delete(key string) {
+	if element, ok := m.entries[key]; ok {
+		m.remove(element)
+	}
+}
+
+// sweep drops expired untagged entries that were never read again, such as
+// rate limit counters of clients that went away, and old tag generations.
+func (m *Memory) sweep() {
+	now := time.Now()
+	if now.Before(m.nextSweep) {
+		return
+	}
+	m.nextSweep = now.Add(memorySweepInterval)
+
+	for element := m.untagged.Front(); element != nil; {
+		next := element.Next()
+		if now.After(element.Value.(*memoryEntry).expiresAt) {
+			m.remove(element)
+		}
+		element = next
+	}
+
+	for tag, tagGeneration := range m.tagGenerations {
+		if now.Sub(tagGeneration.invalidatedAt) > memoryGenerationTTL {
+			delete(m.tagGenerations, tag)
+		}
+	}
+}
+
+func (m *Memory) remove(element *list.Element) {
+	entry := element.Value.(*memoryEntry)
+	for _, tag := range entry.tags {
+		delete(m.tags[tag], entry.key)
+		if len(m.tags[tag]) == 0 {
+			delete(m.tags, tag)
+		}
+	}
+
+	delete(m.entries, entry.key)
+	m.list(entry).Remove(element)
+}
+
+func (m *Memory) list(entry *memoryEntry) *list.List {
+	if len(entry.tags) == 0 {
+		return m.untagged
+	}
+
+	return m.order
+}
@@ -0,0 +1,173 @@
+package cache
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestMemoryEvictsTaggedEntriesFirst(t *testing.T) {
+	ctx := context.Background()
+	m := MemoryInit(4)
+
+	_, err := m.Incr(ctx, "ratelimit:login", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = m.Set(ctx, "otp", "123456", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		_, err = m.SetWithTags(ctx, "listing:"+strconv.Itoa(i), "page", time.Minute, 0, "product")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count, err := m.Incr(ctx, "ratelimit:login", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 2 {
+		t.Fatalf("expected the rate limit counter to survive eviction, got %v", count)
+	}
+
+	otp, err := m.Get(ctx, "otp")
+	if err != nil || otp != "123456" {
+		t.Fatalf("expected the otp to survive eviction, got %q, %v", otp, err)
+	}
+
+	_, err = m.Get(ctx, "listing:7")
+	if !errors.Is(err, ErrMiss) {
+		t.Fatalf("expected older listings to be evicted, got %v", err)
+	}
+	for _, key := range []string{"listing:8", "listing:9"} {
+		_, err = m.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("expected %v to be kept, got %v", key, err)
+		}
+	}
+}
+
+func TestMemoryBoundsUntaggedEntries(t *testing.T) {
+	ctx := context.Background()
+	m := MemoryInit(3)
+
+	err := m.Set(ctx, "otp", "123456", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		_, err = m.Incr(ctx, "ratelimit:"+strconv.Itoa(i), time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Reading the otp keeps it the most recently used entry.
+		_, err = m.Get(ctx, "otp")
+		if err != nil {
+			t.Fatalf("expected the recently read otp to be kept, got %v", err)
+		}
+	}
+
+	if size := len(m.(*Memory).entries); size != 3 {
+		t.Fatalf("expected the cache to hold at most 3 entries, got %v", size)
+	}
+
+	_, err = m.Get(ctx, "ratelimit:0")
+	if !errors.Is(err, ErrMiss) {
+		t.Fatalf("expected the least recently used counter to be evicted, got %v", err)
+	}
+	for _, key := range []string{"ratelimit:8", "ratelimit:9"} {
+		_, err = m.Get(ctx, key)
+		if err != nil {
+			t.Fatalf("expected %v to be kept, got %v", key, err)
+		}
+	}
+}
+
+func TestMemoryExpiresUntaggedEntries(t *testing.T) {
+	ctx := context.Background()
+	m := MemoryInit(2)
+
+	err := m.Set(ctx, "otp", "123456", time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	_, err = m.Get(ctx, "otp")
+	if !errors.Is(err, ErrMiss) {
+		t.Fatalf("expected the otp to expire, got %v", err)
+	}
+}
+
+func TestMemorySetNXStoresOnlyOnce(t *testing.T) {
+	ctx := context.Background()
+	m := MemoryInit(2)
+
+	ok, err := m.SetNX(ctx, "2fa:used", "1", time.Millisecond)
+	if err != nil || !ok {
+		t.Fatalf("expected the first SetNX to store the key, got %v, %v", ok, err)
+	}
+
+	ok, err = m.SetNX(ctx, "2fa:used", "2", time.Millisecond)
+	if err != nil || ok {
+		t.Fatalf("expected the second SetNX to find the key already set, got %v, %v", ok, err)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+
+	ok, err = m.SetNX(ctx, "2fa:used", "3", time.Minute)
+	if err != nil || !ok {
+		t.Fatalf("expected SetNX to store the key again once it expired, got %v, %v", ok, err)
+	}
+}
+
+func TestMemorySkipsEntriesLoadedBeforeInvalidation(t *testing.T) {
+	ctx := context.Background()
+	m := MemoryInit(10)
+
+	generation, err := m.TagGeneration(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = m.InvalidateTags(ctx, "merchant:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := m.SetWithTags(ctx, "listing", "old", time.Minute, generation, "product", "merchant:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored {
+		t.Fatal("expected a page loaded before its tag was invalidated not to be stored")
+	}
+	_, err = m.Get(ctx, "listing")
+	if !errors.Is(err, ErrMiss) {
+		t.Fatalf("expected no cached page, got %v", err)
+	}
+
+	// Tags that were not invalidated since do not hold the write back.
+	stored, err = m.SetWithTags(ctx, "mentors", "page", time.Minute, generation, "mentor")
+	if err != nil || !stored {
+		t.Fatalf("expected the page to be stored, got %v, %v", stored, err)
+	}
+
+	generation, err = m.TagGeneration(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err = m.SetWithTags(ctx, "listing", "new", time.Minute, generation, "product", "merchant:1")
+	if err != nil || !stored {
+		t.Fatalf("expected a page loaded after the invalidation to be stored, got %v, %v", stored, err)
+	}
+}
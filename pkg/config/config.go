@@ -0,0 +1,394 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+type Config struct {
+	Env      string         `yaml:"env" env:"ENV"`
+	App      AppConfig      `yaml:"app"`
+	Database DatabaseConfig `yaml:"database"`
+	Cache    CacheConfig    `yaml:"cache"`
+	Jwt      JwtConfig      `yaml:"jwt"`
+	Totp     TotpConfig     `yaml:"totp"`
+	Mail     MailConfig     `yaml:"mail"`
+	Midtrans MidtransConfig `yaml:"midtrans"`
+	Storage  StorageConfig  `yaml:"storage"`
+	Supabase SupabaseConfig `yaml:"supabase"`
+	S3       S3Config       `yaml:"s3"`
+	Health   HealthConfig   `yaml:"health"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+	Seed     SeedConfig     `yaml:"seed"`
+}
+
+type AppConfig struct {
+	Address         string        `yaml:"address" env:"ADDRESS" required:"true"`
+	Port            string        `yaml:"port" env:"PORT" required:"true"`
+	ReadTimeout     time.Duration `yaml:"read_timeout" env:"HTTP_READ_TIMEOUT" default:"15s"`
+	WriteTimeout    time.Duration `yaml:"write_timeout" env:"HTTP_WRITE_TIMEOUT" default:"30s"`
+	IdleTimeout     time.Duration `yaml:"idle_timeout" env:"HTTP_IDLE_TIMEOUT" default:"60s"`
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout" env:"SHUTDOWN_TIMEOUT" default:"20s"`
+	TrustedProxies  []string      `yaml:"trusted_proxies" env:"TRUSTED_PROXIES"`
+}
+
+type DatabaseConfig struct {
+	User     string `yaml:"user" env:"DB_USER" required:"true"`
+	Password string `yaml:"password" env:"DB_PASS" secret:"true"`
+	Host     string `yaml:"host" env:"DB_HOST" required:"true"`
+	Port     string `yaml:"port" env:"DB_PORT" required:"true"`
+	Name     string `yaml:"name" env:"DB_NAME" required:"true"`
+
+	MigrateOnStart bool `yaml:"migrate_on_start" env:"DB_MIGRATE_ON_START" default:"false"`
+}
+
+type CacheConfig struct {
+	Driver     string `yaml:"driver" env:"CACHE_DRIVER" default:"redis"`
+	MemorySize int    `yaml:"memory_size" env:"CACHE_MEMORY_SIZE" default:"10000"`
+	RedisUrl   string `yaml:"redis_url" env:"REDIS_URL" secret:"true"`
+}
+
+type JwtConfig struct {
+	SecretKey   string `yaml:"secret_key" env:"SECRET_KEY" required:"true" secret:"true"`
+	ExpiredTime int    `yaml:"expired_time" env:"JWT_EXP_TIME" required:"true"`
+}
+
+type TotpConfig struct {
+	Issuer string `yaml:"issuer" env:"TOTP_ISSUER" default:"BizConnect"`
+}
+
+type MailConfig struct {
+	Host     string `yaml:"host" env:"GOMAIL_HOST" required:"true"`
+	Port     int    `yaml:"port" env:"GOMAIL_PORT" required:"true"`
+	Username string `yaml:"username" env:"GOMAIL_USERNAME" required:"true"`
+	Password string `yaml:"password" env:"GOMAIL_PASSWORD" required:"true" secret:"true"`
+}
+
+type MidtransConfig struct {
+	ServerKey string `yaml:"server_key" env:"MIDTRANS_SERVER_KEY" required:"true" secret:"true"`
+}
+
+type StorageConfig struct {
+	Driver    string `yaml:"driver" env:"STORAGE_DRIVER" default:"supabase"`
+	PublicUrl string `yaml:"public_url" env:"STORAGE_PUBLIC_URL"`
+	LocalDir  string `yaml:"local_dir" env:"STORAGE_LOCAL_DIR" default:"storage"`
+}
+
+type SupabaseConfig struct {
+	Url    string `yaml:"url" env:"SUPABASE_URL"`
+	Token  string `yaml:"token" env:"SUPABASE_TOKEN" secret:"true"`
+	Bucket string `yaml:"bucket" env:"SUPABASE_BUCKET"`
+}
+
+type S3Config struct {
+	Endpoint  string `yaml:"endpoint" env:"S3_ENDPOINT"`
+	Region    string `yaml:"region" env:"S3_REGION" default:"us-east-1"`
+	Bucket    string `yaml:"bucket" env:"S3_BUCKET"`
+	AccessKey string `yaml:"access_key" env:"S3_ACCESS_KEY"`
+	SecretKey string `yaml:"secret_key" env:"S3_SECRET_KEY" secret:"true"`
+	PathStyle bool   `yaml:"path_style" env:"S3_PATH_STYLE" default:"true"`
+}
+
+type HealthConfig struct {
+	Timeout      time.Duration `yaml:"timeout" env:"READINESS_TIMEOUT" default:"3s"`
+	CheckSmtp    bool          `yaml:"check_smtp" env:"READINESS_CHECK_SMTP" default:"false"`
+	CheckStorage bool          `yaml:"check_storage" env:"READINESS_CHECK_STORAGE" default:"false"`
+}
+
+type TracingConfig struct {
+	Exporter    string  `yaml:"exporter" env:"TRACING_EXPORTER" default:"none"`
+	Endpoint    string  `yaml:"endpoint" env:"TRACING_ENDPOINT"`
+	ServiceName string  `yaml:"service_name" env:"TRACING_SERVICE_NAME" default:"bizconnect"`
+	SampleRatio float64 `yaml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" default:"1"`
+}
+
+type SeedConfig struct {
+	Profile       string `yaml:"profile" env:"SEED_PROFILE" default:"production"`
+	AdminName     string `yaml:"admin_name" env:"SEED_ADMIN_NAME" default:"Admin"`
+	AdminEmail    string `yaml:"admin_email" env:"SEED_ADMIN_EMAIL"`
+	AdminPassword string `yaml:"admin_password" env:"SEED_ADMIN_PASSWORD" secret:"true"`
+	RegionsFile   string `yaml:"regions_file" env:"SEED_REGIONS_FILE"`
+}
+
+// Command selects which part of the configuration Load requires, so migrate and
+// seed can run with only the database configured.
+type Command string
+
+const (
+	CommandServe   Command = "serve"
+	CommandMigrate Command = "migrate"
+	CommandSeed    Command = "seed"
+)
+
+func (c AppConfig) BaseUrl() string {
+	return fmt.Sprintf("http://%v:%v", c.Address, c.Port)
+}
+
+func Load(command Command) (Config, error) {
+	err := godotenv.Load()
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return Config{}, fmt.Errorf("failed to load .env file: %w", err)
+	}
+
+	var cfg Config
+	err = applyDefaults(reflect.ValueOf(&cfg).Elem())
+	if err != nil {
+		return Config{}, err
+	}
+
+	configFile := os.Getenv("CONFIG_FILE")
+	if configFile == "" {
+		configFile = "config.yaml"
+	}
+
+	byteConfig, err := os.ReadFile(configFile)
+	if err == nil {
+		err = yaml.Unmarshal(byteConfig, &cfg)
+		if err != nil {
+			return Config{}, fmt.Errorf("failed to parse %v: %w", configFile, err)
+		}
+	} else if !errors.Is(err, os.ErrNotExist) || os.Getenv("CONFIG_FILE") != "" {
+		return Config{}, fmt.Errorf("failed to read %v: %w", configFile, err)
+	}
+
+	var problems []string
+	applyEnv(reflect.ValueOf(&cfg).Elem(), &problems)
+
+	switch command {
+	case CommandServe:
+		validate(reflect.ValueOf(cfg), &problems)
+		problems = append(problems, cfg.check()...)
+	case CommandMigrate:
+		validate(reflect.ValueOf(cfg.Database), &problems)
+	case CommandSeed:
+		validate(reflect.ValueOf(cfg.Database), &problems)
+		problems = append(problems, cfg.Seed.check()...)
+	default:
+		return Config{}, fmt.Errorf("unknown command %q", command)
+	}
+
+	if len(problems) > 0 {
+		return Config{}, fmt.Errorf("invalid configuration:\n  - %v", strings.Join(problems, "\n  - "))
+	}
+
+	return cfg, nil
+}
+
+func (c Config) check() []string {
+	var problems []string
+
+	if c.Cache.Driver != "redis" && c.Cache.Driver != "memory" {
+		problems = append(problems, fmt.Sprintf("CACHE_DRIVER must be redis or memory, got %q", c.Cache.Driver))
+	}
+	if c.Cache.Driver == "redis" && c.Cache.RedisUrl == "" {
+		problems = append(problems, "REDIS_URL is required when CACHE_DRIVER is redis")
+	}
+	if c.Cache.MemorySize <= 0 {
+		problems = append(problems, "CACHE_MEMORY_SIZE must be greater than 0")
+	}
+	switch c.Storage.Driver {
+	case "supabase":
+		if c.Supabase.Url == "" || c.Supabase.Token == "" || c.Supabase.Bucket == "" {
+			problems = append(problems, "SUPABASE_URL, SUPABASE_TOKEN and SUPABASE_BUCKET are required when STORAGE_DRIVER is supabase")
+		}
+	case "local":
+		if c.Storage.LocalDir == "" {
+			problems = append(problems, "STORAGE_LOCAL_DIR is required when STORAGE_DRIVER is local")
+		}
+	case "s3":
+		if c.S3.Endpoint == "" || c.S3.Bucket == "" || c.S3.AccessKey == "" || c.S3.SecretKey == "" {
+			problems = append(problems, "S3_ENDPOINT, S3_BUCKET, S3_ACCESS_KEY and S3_SECRET_KEY are required when STORAGE_DRIVER is s3")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("STORAGE_DRIVER must be supabase, local or s3, got %q", c.Storage.Driver))
+	}
+
+	for _, proxy := range c.App.TrustedProxies {
+		if net.ParseIP(proxy) == nil {
+			_, _, err := net.ParseCIDR(proxy)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("TRUSTED_PROXIES has invalid value: %q is not an IP or CIDR", proxy))
+			}
+		}
+	}
+
+	if c.Jwt.ExpiredTime < 0 {
+		problems = append(problems, "JWT_EXP_TIME must not be negative")
+	}
+
+	if c.Tracing.Exporter != "none" && c.Tracing.Exporter != "stdout" && c.Tracing.Exporter != "otlp" {
+		problems = append(problems, fmt.Sprintf("TRACING_EXPORTER must be none, stdout or otlp, got %q", c.Tracing.Exporter))
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		problems = append(problems, "TRACING_SAMPLE_RATIO must be between 0 and 1")
+	}
+
+	return problems
+}
+
+func (c SeedConfig) check() []string {
+	var problems []string
+
+	if c.Profile != "production" && c.Profile != "development" {
+		problems = append(problems, fmt.Sprintf("SEED_PROFILE must be production or development, got %q", c.Profile))
+	}
+	if (c.AdminEmail == "") != (c.AdminPassword == "") {
+		problems = append(problems, "SEED_ADMIN_EMAIL and SEED_ADMIN_PASSWORD must be set together")
+	}
+
+	return problems
+}
+
+func (c Config) String() string {
+	var lines []string
+	describe(reflect.ValueOf(c), "", &lines)
+
+	return strings.Join(lines, "\n")
+}
+
+func applyDefaults(v reflect.Value) error {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			err := applyDefaults(v.Field(i))
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		value, ok := field.Tag.Lookup("default")
+		if !ok {
+			continue
+		}
+
+		err := setValue(v.Field(i), value)
+		if err != nil {
+			return fmt.Errorf("invalid default for %v: %w", field.Tag.Get("env"), err)
+		}
+	}
+
+	return nil
+}
+
+func applyEnv(v reflect.Value, problems *[]string) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			applyEnv(v.Field(i), problems)
+			continue
+		}
+
+		name := field.Tag.Get("env")
+		value, ok := os.LookupEnv(name)
+		if name == "" || !ok || value == "" {
+			continue
+		}
+
+		err := setValue(v.Field(i), value)
+		if err != nil {
+			*problems = append(*problems, fmt.Sprintf("%v has invalid value: %v", name, err))
+		}
+	}
+}
+
+func validate(v reflect.Value, problems *[]string) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		if field.Type.Kind() == reflect.Struct {
+			validate(v.Field(i), problems)
+			continue
+		}
+
+		name := field.Tag.Get("env")
+		if field.Tag.Get("required") == "true" && v.Field(i).IsZero() && !reported(*problems, name) {
+			*problems = append(*problems, fmt.Sprintf("%v is required", name))
+		}
+	}
+}
+
+func reported(problems []string, name string) bool {
+	for _, problem := range problems {
+		if strings.HasPrefix(problem, name+" ") {
+			return true
+		}
+	}
+
+	return false
+}
+
+func describe(v reflect.Value, prefix string, lines *[]string) {
+	for i := 0; i < v.NumField(); i++ {
+		field := v.Type().Field(i)
+		name := prefix + field.Tag.Get("yaml")
+		if field.Type.Kind() == reflect.Struct {
+			describe(v.Field(i), name+".", lines)
+			continue
+		}
+
+		value := fmt.Sprint(v.Field(i).Interface())
+		if field.Tag.Get("secret") == "true" && value != "" {
+			value = "[REDACTED]"
+		}
+		*lines = append(*lines, fmt.Sprintf("%v=%v", name, value))
+	}
+}
+
+func setValue(field reflect.Value, value string) error {
+	if field.Type() == reflect.TypeOf(time.Duration(0)) {
+		duration, err := time.ParseDuration(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a duration", value)
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		boolean, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+		field.SetBool(boolean)
+	case reflect.Float64:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		field.SetFloat(number)
+	case reflect.Int:
+		number, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		field.SetInt(int64(number))
+	case reflect.Slice:
+		if field.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("unsupported config type %v", field.Type())
+		}
+
+		var values []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				values = append(values, item)
+			}
+		}
+		field.Set(reflect.ValueOf(values))
+	default:
+		return fmt.Errorf("unsupported config type %v", field.Kind())
+	}
+
+	return nil
+}
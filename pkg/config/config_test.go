@@ -0,0 +1,64 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadRequiresOnlyWhatTheCommandUses(t *testing.T) {
+	// Load reads .env and config.yaml from the working directory.
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.Chdir(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	t.Setenv("CONFIG_FILE", "")
+	for _, name := range []string{"ADDRESS", "PORT", "SECRET_KEY", "JWT_EXP_TIME", "GOMAIL_HOST", "GOMAIL_PORT", "GOMAIL_USERNAME", "GOMAIL_PASSWORD", "MIDTRANS_SERVER_KEY", "REDIS_URL", "SUPABASE_URL", "SUPABASE_TOKEN", "SUPABASE_BUCKET", "SEED_PROFILE", "SEED_ADMIN_EMAIL", "SEED_ADMIN_PASSWORD"} {
+		t.Setenv(name, "")
+	}
+	t.Setenv("DB_USER", "root")
+	t.Setenv("DB_HOST", "localhost")
+	t.Setenv("DB_PORT", "3306")
+	t.Setenv("DB_NAME", "bizconnect")
+
+	for _, command := range []Command{CommandMigrate, CommandSeed} {
+		_, err = Load(command)
+		if err != nil {
+			t.Fatalf("expected %v to need only the database, got %v", command, err)
+		}
+	}
+
+	_, err = Load(CommandServe)
+	if err == nil || !strings.Contains(err.Error(), "SECRET_KEY is required") {
+		t.Fatalf("expected serve to require the app secrets, got %v", err)
+	}
+
+	t.Setenv("SEED_ADMIN_EMAIL", "admin@example.com")
+	_, err = Load(CommandSeed)
+	if err == nil || !strings.Contains(err.Error(), "SEED_ADMIN_PASSWORD") {
+		t.Fatalf("expected seed to check its own settings, got %v", err)
+	}
+	_, err = Load(CommandMigrate)
+	if err != nil {
+		t.Fatalf("expected migrate to ignore the seed settings, got %v", err)
+	}
+
+	t.Setenv("DB_HOST", "")
+	_, err = Load(CommandMigrate)
+	if err == nil || !strings.Contains(err.Error(), "DB_HOST is required") {
+		t.Fatalf("expected migrate to require the database, got %v", err)
+	}
+
+	t.Setenv("CONFIG_FILE", filepath.Join(t.TempDir(), "missing.yaml"))
+	_, err = Load(CommandMigrate)
+	if err == nil {
+		t.Fatal("expected an explicit config file that does not exist to be reported")
+	}
+}
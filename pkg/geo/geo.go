@@ -0,0 +1,113 @@
+package geo
+
+import (
+	"math"
+	"strings"
+)
+
+const (
+	earthRadiusKm = 6371.0
+	base32        = "0123456789bcdefghjkmnpqrstuvwxyz"
+)
+
+type BoundingBox struct {
+	MinLatitude  float64
+	MaxLatitude  float64
+	MinLongitude float64
+	MaxLongitude float64
+}
+
+// Encode returns the geohash of a point with the given number of characters.
+func Encode(latitude float64, longitude float64, precision int) string {
+	minLat, maxLat := -90.0, 90.0
+	minLng, maxLng := -180.0, 180.0
+
+	var hash strings.Builder
+	bit, index, even := 0, 0, true
+	for hash.Len() < precision {
+		if even {
+			mid := (minLng + maxLng) / 2
+			if longitude >= mid {
+				index = index<<1 | 1
+				minLng = mid
+			} else {
+				index <<= 1
+				maxLng = mid
+			}
+		} else {
+			mid := (minLat + maxLat) / 2
+			if latitude >= mid {
+				index = index<<1 | 1
+				minLat = mid
+			} else {
+				index <<= 1
+				maxLat = mid
+			}
+		}
+		even = !even
+
+		bit++
+		if bit == 5 {
+			hash.WriteByte(base32[index])
+			bit, index = 0, 0
+		}
+	}
+
+	return hash.String()
+}
+
+// Decode returns the center of a geohash cell.
+func Decode(hash string) (float64, float64) {
+	minLat, maxLat := -90.0, 90.0
+	minLng, maxLng := -180.0, 180.0
+
+	even := true
+	for _, c := range hash {
+		index := strings.IndexRune(base32, c)
+		for bit := 4; bit >= 0; bit-- {
+			set := index>>bit&1 == 1
+			if even {
+				mid := (minLng + maxLng) / 2
+				if set {
+					minLng = mid
+				} else {
+					maxLng = mid
+				}
+			} else {
+				mid := (minLat + maxLat) / 2
+				if set {
+					minLat = mid
+				} else {
+					maxLat = mid
+				}
+			}
+			even = !even
+		}
+	}
+
+	return (minLat + maxLat) / 2, (minLng + maxLng) / 2
+}
+
+// Around returns a box that contains every point within radiusKm of the center.
+// Longitudes stay within ±180, so a box that would cross the antimeridian
+// spans every longitude instead.
+func Around(latitude float64, longitude float64, radiusKm float64) BoundingBox {
+	deltaLat := radiusKm / earthRadiusKm * 180 / math.Pi
+	box := BoundingBox{
+		MinLatitude:  math.Max(latitude-deltaLat, -90),
+		MaxLatitude:  math.Min(latitude+deltaLat, 90),
+		MinLongitude: -180,
+		MaxLongitude: 180,
+	}
+
+	cos := math.Cos(latitude * math.Pi / 180)
+	if box.MinLatitude > -90 && box.MaxLatitude < 90 && cos > 0 {
+		deltaLng := deltaLat / cos
+		if longitude-deltaLng >= -180 && longitude+deltaLng <= 180 {
+			box.MinLongitude = longitude - deltaLng
+			box.MaxLongitude = longitude + deltaLng
+		}
+	}
+
+	return box
+}
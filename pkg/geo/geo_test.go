@@ -0,0 +1,165 @@
+package geo
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEncode(t *testing.T) {
+	for _, test := range []struct {
+		latitude  float64
+		longitude float64
+		precision int
+		hash      string
+	}{
+		{57.64911, 10.40744, 11, "u4pruydqqvj"},
+		{42.6, -5.6, 5, "ezs42"},
+		{-25.382708, -49.265506, 8, "6gkzwgjz"},
+		{0, 0, 4, "s000"},
+		{90, 180, 6, "zzzzzz"},
+		{-90, -180, 6, "000000"},
+		{89.99, -179.99, 3, "bpb"},
+		{-89.99, 179.99, 3, "pbp"},
+	} {
+		if hash := Encode(test.latitude, test.longitude, test.precision); hash != test.hash {
+			t.Fatalf("expected %v, %v to encode to %v, got %v", test.latitude, test.longitude, test.hash, hash)
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	for _, test := range []struct {
+		hash      string
+		latitude  float64
+		longitude float64
+		tolerance float64
+	}{
+		{"u4pruydqqvj", 57.64911, 10.40744, 1e-5},
+		{"ezs42", 42.605, -5.603, 1e-3},
+		{"zzzzzz", 90, 180, 0.01},
+		{"000000", -90, -180, 0.01},
+	} {
+		latitude, longitude := Decode(test.hash)
+		if math.Abs(latitude-test.latitude) > test.tolerance || math.Abs(longitude-test.longitude) > test.tolerance {
+			t.Fatalf("expected %v to decode to %v, %v, got %v, %v", test.hash, test.latitude, test.longitude, latitude, longitude)
+		}
+	}
+}
+
+func TestDecodeReturnsTheCellOfTheEncodedPoint(t *testing.T) {
+	for _, point := range [][2]float64{{-7.9666, 112.6326}, {89.999, 179.999}, {-89.999, -179.999}, {0, -180}, {-0.0001, 179.9999}} {
+		hash := Encode(point[0], point[1], 7)
+		latitude, longitude := Decode(hash)
+		if Encode(latitude, longitude, 7) != hash {
+			t.Fatalf("expected the center of %v to be in the same cell, got %v", hash, Encode(latitude, longitude, 7))
+		}
+		// A 7 character cell is about 0.0014 by 0.0014 degrees.
+		if math.Abs(latitude-point[0]) > 0.001 || math.Abs(longitude-point[1]) > 0.001 {
+			t.Fatalf("expected %v to decode near %v, got %v, %v", hash, point, latitude, longitude)
+		}
+	}
+
+	if hash := Encode(-7.9666, 112.6326, 7); !strings.HasPrefix(hash, Encode(-7.9666, 112.6326, 5)) {
+		t.Fatalf("expected a longer geohash to extend the shorter one, got %v", hash)
+	}
+}
+
+// destination returns the point distanceKm away from the start in the given
+// bearing, in degrees clockwise from north.
+func destination(latitude float64, longitude float64, bearing float64, distanceKm float64) (float64, float64) {
+	lat, lng := latitude*math.Pi/180, longitude*math.Pi/180
+	angle, theta := distanceKm/earthRadiusKm, bearing*math.Pi/180
+
+	lat2 := math.Asin(math.Sin(lat)*math.Cos(angle) + math.Cos(lat)*math.Sin(angle)*math.Cos(theta))
+	lng2 := lng + math.Atan2(math.Sin(theta)*math.Sin(angle)*math.Cos(lat), math.Cos(angle)-math.Sin(lat)*math.Sin(lat2))
+
+	lng2 = math.Mod(lng2*180/math.Pi+540, 360) - 180
+	return lat2 * 180 / math.Pi, lng2
+}
+
+func TestAround(t *testing.T) {
+	for _, test := range []struct {
+		name      string
+		latitude  float64
+		longitude float64
+		radiusKm  float64
+		box       BoundingBox
+	}{
+		{
+			name:      "equator",
+			latitude:  0,
+			longitude: 0,
+			radiusKm:  earthRadiusKm * math.Pi / 180,
+			box:       BoundingBox{MinLatitude: -1, MaxLatitude: 1, MinLongitude: -1, MaxLongitude: 1},
+		},
+		{
+			name:      "north pole",
+			latitude:  89.9,
+			longitude: 20,
+			radiusKm:  50,
+			box:       BoundingBox{MinLatitude: 89.9 - 50/earthRadiusKm*180/math.Pi, MaxLatitude: 90, MinLongitude: -180, MaxLongitude: 180},
+		},
+		{
+			name:      "south pole",
+			latitude:  -90,
+			longitude: 0,
+			radiusKm:  10,
+			box:       BoundingBox{MinLatitude: -90, MaxLatitude: -90 + 10/earthRadiusKm*180/math.Pi, MinLongitude: -180, MaxLongitude: 180},
+		},
+		{
+			name:      "east of the antimeridian",
+			latitude:  -16,
+			longitude: 179.9,
+			radiusKm:  50,
+			box:       BoundingBox{MinLatitude: -16 - 50/earthRadiusKm*180/math.Pi, MaxLatitude: -16 + 50/earthRadiusKm*180/math.Pi, MinLongitude: -180, MaxLongitude: 180},
+		},
+		{
+			name:      "west of the antimeridian",
+			latitude:  52,
+			longitude: -179.95,
+			radiusKm:  20,
+			box:       BoundingBox{MinLatitude: 52 - 20/earthRadiusKm*180/math.Pi, MaxLatitude: 52 + 20/earthRadiusKm*180/math.Pi, MinLongitude: -180, MaxLongitude: 180},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			box := Around(test.latitude, test.longitude, test.radiusKm)
+			for _, bound := range [][2]float64{
+				{box.MinLatitude, test.box.MinLatitude},
+				{box.MaxLatitude, test.box.MaxLatitude},
+				{box.MinLongitude, test.box.MinLongitude},
+				{box.MaxLongitude, test.box.MaxLongitude},
+			} {
+				if math.Abs(bound[0]-bound[1]) > 1e-9 {
+					t.Fatalf("expected %+v, got %+v", test.box, box)
+				}
+			}
+		})
+	}
+}
+
+func TestAroundContainsEveryPointWithinTheRadius(t *testing.T) {
+	for _, center := range [][3]float64{
+		{-7.9666, 112.6326, 25},
+		{60, 10, 500},
+		{89.5, 45, 100},
+		{-89.5, -45, 100},
+		{0, 179.99, 5},
+		{0, -179.99, 5},
+		{-40, 180, 200},
+	} {
+		box := Around(center[0], center[1], center[2])
+		if box.MinLongitude < -180 || box.MaxLongitude > 180 || box.MinLatitude < -90 || box.MaxLatitude > 90 {
+			t.Fatalf("expected the box around %v to stay within ±90 and ±180, got %+v", center, box)
+		}
+
+		for bearing := 0.0; bearing < 360; bearing += 15 {
+			for _, fraction := range []float64{0.5, 0.999} {
+				latitude, longitude := destination(center[0], center[1], bearing, center[2]*fraction)
+				if latitude < box.MinLatitude || latitude > box.MaxLatitude || longitude < box.MinLongitude || longitude > box.MaxLongitude {
+					t.Fatalf("expected %v, %v to be in the box around %v, got %+v", latitude, longitude, center, box)
+				}
+			}
+		}
+	}
+}
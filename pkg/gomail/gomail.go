@@ -1,14 +1,20 @@
 package gomail
 
 import (
-	"os"
-	"strconv"
+	"context"
+	"fmt"
+	"intern-bcc/pkg/config"
+	"intern-bcc/pkg/metrics"
+	"intern-bcc/pkg/tracing"
+	"net"
 
+	"go.opentelemetry.io/otel/attribute"
 	"gopkg.in/gomail.v2"
 )
 
 type IGoMail interface {
-	SendGoMail(subject string, htmlBody string, toEmail string) error
+	SendGoMail(ctx context.Context, subject string, htmlBody string, toEmail string) error
+	Ping(ctx context.Context) error
 }
 
 type Gomail struct {
@@ -18,22 +24,19 @@ type Gomail struct {
 	password string
 }
 
-func GoMailInit() IGoMail {
-	host := os.Getenv("GOMAIL_HOST")
-	stringPort := os.Getenv("GOMAIL_PORT")
-	port, _ := strconv.Atoi(stringPort)
-	username := os.Getenv("GOMAIL_USERNAME")
-	password := os.Getenv("GOMAIL_PASSWORD")
-
+func GoMailInit(cfg config.MailConfig) IGoMail {
 	return &Gomail{
-		host:     host,
-		port:     port,
-		username: username,
-		password: password,
+		host:     cfg.Host,
+		port:     cfg.Port,
+		username: cfg.Username,
+		password: cfg.Password,
 	}
 }
 
-func (g *Gomail) SendGoMail(subject string, htmlBody string, toEmail string) error {
+func (g *Gomail) SendGoMail(ctx context.Context, subject string, htmlBody string, toEmail string) (err error) {
+	_, span := tracing.StartClient(ctx, "smtp.send", attribute.String("smtp.host", g.host))
+	defer func() { tracing.End(span, err) }()
+
 	m := gomail.NewMessage()
 	m.SetHeader("From", g.username)
 	m.SetHeader("To", toEmail)
@@ -43,8 +46,19 @@ func (g *Gomail) SendGoMail(subject string, htmlBody string, toEmail string) err
 	d := gomail.NewDialer(g.host, g.port, g.username, g.password)
 
 	if err := d.DialAndSend(m); err != nil {
+		metrics.EmailSendFailures.Inc()
 		return err
 	}
 
 	return nil
 }
+
+func (g *Gomail) Ping(ctx context.Context) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", fmt.Sprintf("%v:%v", g.host, g.port))
+	if err != nil {
+		return err
+	}
+
+	return conn.Close()
+}
@@ -0,0 +1,65 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type CheckFunc func(ctx context.Context) error
+
+type IHealth interface {
+	Register(name string, check CheckFunc)
+	Ready(ctx context.Context) (map[string]string, bool)
+}
+
+type Health struct {
+	timeout time.Duration
+	names   []string
+	checks  map[string]CheckFunc
+}
+
+func HealthInit(timeout time.Duration) IHealth {
+	return &Health{
+		timeout: timeout,
+		checks:  make(map[string]CheckFunc),
+	}
+}
+
+func (h *Health) Register(name string, check CheckFunc) {
+	h.names = append(h.names, name)
+	h.checks[name] = check
+}
+
+func (h *Health) Ready(ctx context.Context) (map[string]string, bool) {
+	ctx, cancel := context.WithTimeout(ctx, h.timeout)
+	defer cancel()
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	status := make(map[string]string)
+	ready := true
+
+	for _, name := range h.names {
+		wg.Add(1)
+		go func(name string, check CheckFunc) {
+			defer wg.Done()
+
+			result := "ok"
+			err := check(ctx)
+			if err != nil {
+				result = err.Error()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			status[name] = result
+			if err != nil {
+				ready = false
+			}
+		}(name, h.checks[name])
+	}
+
+	wg.Wait()
+	return status, ready
+}
@@ -0,0 +1,188 @@
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+)
+
+const (
+	MaxUploadSize = 5 << 20
+	maxPixels     = 40_000_000
+	jpegQuality   = 85
+)
+
+var (
+	ErrTooLarge        = errors.New("image is too large")
+	ErrUnsupportedType = errors.New("unsupported image type")
+	ErrInvalidImage    = errors.New("invalid image")
+)
+
+var allowedTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
+type Rendition struct {
+	Name    string
+	MaxSide int
+}
+
+var (
+	Original  = Rendition{Name: "original", MaxSide: 2048}
+	Medium    = Rendition{Name: "medium", MaxSide: 1024}
+	Thumbnail = Rendition{Name: "thumbnail", MaxSide: 320}
+)
+
+type Encoded struct {
+	Rendition   Rendition
+	ContentType string
+	Extension   string
+	Data        []byte
+}
+
+type Processed struct {
+	Original  Encoded
+	Medium    Encoded
+	Thumbnail Encoded
+}
+
+// Process validates an uploaded image by its content rather than its name,
+// applies the EXIF orientation and re-encodes it into every rendition, which
+// also drops EXIF and other metadata from the stored files.
+func Process(file *multipart.FileHeader) (Processed, error) {
+	if file.Size > MaxUploadSize {
+		return Processed{}, fmt.Errorf("%w: %d bytes exceeds %d bytes", ErrTooLarge, file.Size, MaxUploadSize)
+	}
+
+	source, err := file.Open()
+	if err != nil {
+		return Processed{}, err
+	}
+	defer source.Close()
+
+	data, err := io.ReadAll(io.LimitReader(source, MaxUploadSize+1))
+	if err != nil {
+		return Processed{}, err
+	}
+
+	return ProcessBytes(data)
+}
+
+func ProcessBytes(data []byte) (Processed, error) {
+	if len(data) > MaxUploadSize {
+		return Processed{}, fmt.Errorf("%w: more than %d bytes", ErrTooLarge, MaxUploadSize)
+	}
+
+	contentType, err := DetectType(data)
+	if err != nil {
+		return Processed{}, err
+	}
+
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Processed{}, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	if config.Width <= 0 || config.Height <= 0 || config.Width*config.Height > maxPixels {
+		return Processed{}, fmt.Errorf("%w: %dx%d pixels", ErrTooLarge, config.Width, config.Height)
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return Processed{}, fmt.Errorf("%w: %v", ErrInvalidImage, err)
+	}
+
+	if contentType == "image/jpeg" {
+		img = orient(img, jpegOrientation(data))
+	}
+
+	// Formats that may carry transparency stay PNG, everything else is JPEG.
+	keepAlpha := contentType == "image/png" || contentType == "image/gif"
+
+	var processed Processed
+	for _, target := range []struct {
+		rendition Rendition
+		encoded   *Encoded
+	}{
+		{Original, &processed.Original},
+		{Medium, &processed.Medium},
+		{Thumbnail, &processed.Thumbnail},
+	} {
+		encoded, err := encode(resize(img, target.rendition.MaxSide), keepAlpha)
+		if err != nil {
+			return Processed{}, err
+		}
+
+		encoded.Rendition = target.rendition
+		*target.encoded = encoded
+	}
+
+	return processed, nil
+}
+
+// DetectType returns the type of an image by its content.
+func DetectType(data []byte) (string, error) {
+	contentType := http.DetectContentType(data)
+	if !IsAllowedType(contentType) {
+		return "", fmt.Errorf("%w: %v", ErrUnsupportedType, contentType)
+	}
+
+	return contentType, nil
+}
+
+func IsAllowedType(contentType string) bool {
+	return allowedTypes[contentType]
+}
+
+func resize(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width <= maxSide && height <= maxSide {
+		return img
+	}
+
+	if width >= height {
+		height = max(1, height*maxSide/width)
+		width = maxSide
+	} else {
+		width = max(1, width*maxSide/height)
+		height = maxSide
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), img, bounds, draw.Src, nil)
+
+	return dst
+}
+
+func encode(img image.Image, keepAlpha bool) (Encoded, error) {
+	var buf bytes.Buffer
+	if keepAlpha {
+		err := png.Encode(&buf, img)
+		if err != nil {
+			return Encoded{}, err
+		}
+
+		return Encoded{ContentType: "image/png", Extension: ".png", Data: buf.Bytes()}, nil
+	}
+
+	err := jpeg.Encode(&buf, img, &jpeg.Options{Quality: jpegQuality})
+	if err != nil {
+		return Encoded{}, err
+	}
+
+	return Encoded{ContentType: "image/jpeg", Extension: ".jpg", Data: buf.Bytes()}, nil
+}
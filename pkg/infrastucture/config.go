@@ -1,15 +0,0 @@
-package infrastucture
-
-import (
-	"log"
-
-	"github.com/joho/godotenv"
-)
-
-func LoadEnv() {
-	err := godotenv.Load()
-	if err != nil {
-		log.Println(err)
-		log.Fatal("Error loading .env file")
-	}
-}
@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestImageReferencesFollowRecords(t *testing.T) {
+	ctx := context.Background()
+	db := newTestMySQL(t)
+
+	migrator, err := MigratorInit(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = migrator.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	images := repository.NewImageRepository(db)
+	deleted := 0
+	remove := func(ctx context.Context) error {
+		deleted++
+		return nil
+	}
+	assertDeleted := func(link string, want bool) {
+		t.Helper()
+
+		before := deleted
+		err := images.DeleteUnreferenced(ctx, link, time.Now(), remove)
+		if want && (err != nil || deleted != before+1) {
+			t.Fatalf("expected %v to be deleted, got %v", link, err)
+		}
+		if !want && !errors.Is(err, repository.ErrImageReferenced) {
+			t.Fatalf("expected %v to be kept, got %v", link, err)
+		}
+	}
+
+	mentor := domain.Mentors{
+		Id:                 uuid.New(),
+		Name:               "Mentor",
+		MentorPicture:      "http://storage/a.jpg",
+		MentorPictureSizes: domain.ImageRenditions{Medium: "http://storage/b.jpg", Thumbnail: "http://storage/b.jpg"},
+	}
+	err = db.Create(&mentor).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDeleted("http://storage/a.jpg", false)
+	assertDeleted("http://storage/b.jpg", false)
+
+	err = db.Model(&mentor).Update("mentor_picture", "http://storage/c.jpg").Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDeleted("http://storage/a.jpg", true)
+	assertDeleted("http://storage/c.jpg", false)
+
+	// A soft deleted record can still be restored, so it keeps its files.
+	err = db.Delete(&mentor).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDeleted("http://storage/c.jpg", false)
+
+	err = db.Unscoped().Delete(&mentor).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertDeleted("http://storage/c.jpg", true)
+
+	// A recent upload keeps its files until its reservation expires.
+	err = images.CreateUploadReferences(ctx, []string{"http://storage/d.jpg"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = images.DeleteUnreferenced(ctx, "http://storage/d.jpg", time.Now().Add(-time.Hour), remove)
+	if !errors.Is(err, repository.ErrImageReferenced) {
+		t.Fatalf("expected a recent upload to be kept, got %v", err)
+	}
+
+	links, err := images.DeleteExpiredUploadReferences(ctx, time.Now().Add(time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(links) != 1 || links[0] != "http://storage/d.jpg" {
+		t.Fatalf("expected the expired upload to be returned, got %v", links)
+	}
+	assertDeleted("http://storage/d.jpg", true)
+}
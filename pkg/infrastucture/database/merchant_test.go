@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/cache"
+	"testing"
+	"time"
+)
+
+func TestRestoreMerchantRestoresOnlyProductsDeletedWithIt(t *testing.T) {
+	ctx := context.Background()
+	db := newTestMySQL(t)
+	records := createMerchantRecords(t, db)
+
+	memory := cache.MemoryInit(100)
+	merchants := repository.NewMerchantRepository(db, memory)
+	products := repository.NewProductRepository(db, memory)
+
+	// The first product was deleted on its own before the merchant was.
+	err := products.DeleteProduct(ctx, records.products[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	err = merchants.DeleteMerchant(ctx, records.merchant.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var deletedMerchants []domain.Merchants
+	err = merchants.GetDeletedMerchants(ctx, &deletedMerchants)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var deletedProducts []domain.Products
+	err = products.GetDeletedProducts(ctx, &deletedProducts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(deletedMerchants) != 1 || len(deletedProducts) != 2 {
+		t.Fatalf("expected the merchant and both products in the trash, got %v merchants and %v products", len(deletedMerchants), len(deletedProducts))
+	}
+
+	err = merchants.RestoreMerchant(ctx, records.merchant.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total := countRows(t, db, "merchants", "id = ? AND deleted_at IS NULL", records.merchant.Id); total != 1 {
+		t.Fatal("expected the merchant to be restored")
+	}
+	if total := countRows(t, db, "products", "id = ? AND deleted_at IS NULL", records.products[1].Id); total != 1 {
+		t.Fatal("expected the product deleted with the merchant to be restored")
+	}
+	if total := countRows(t, db, "products", "id = ? AND deleted_at IS NOT NULL", records.products[0].Id); total != 1 {
+		t.Fatal("expected the product deleted on its own to stay in the trash")
+	}
+
+	// Restoring twice finds nothing to restore.
+	err = merchants.RestoreMerchant(ctx, records.merchant.Id)
+	if err == nil {
+		t.Fatal("expected a merchant that is not in the trash to be reported")
+	}
+
+	err = products.RestoreProduct(ctx, records.products[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total := countRows(t, db, "products", "merchant_id = ? AND deleted_at IS NULL", records.merchant.Id); total != 2 {
+		t.Fatal("expected both products to be back")
+	}
+}
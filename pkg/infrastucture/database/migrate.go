@@ -1,18 +1,564 @@
 package database
 
-import "intern-bcc/domain"
-
-func Migrate() {
-	DB.Migrator().AutoMigrate(
-		&domain.Users{},
-		&domain.Transactions{},
-		&domain.Universities{},
-		&domain.Province{},
-		&domain.Information{},
-		&domain.Merchants{},
-		&domain.Products{},
-		&domain.Mentors{},
-		&domain.Experiences{},
-		&domain.Categories{},
-	)
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"intern-bcc/domain"
+	"io/fs"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+var (
+	ErrPendingMigrations = errors.New("database has pending migrations")
+	ErrSchemaDrift       = errors.New("database schema has drifted")
+)
+
+const migrationLockTimeout = 30
+
+// integerWidth matches the display width MySQL 5.7 reports for integer columns.
+var integerWidth = regexp.MustCompile(`^(tinyint|smallint|mediumint|int|bigint)\(\d+\)`)
+
+const createSchemaMigrations = `CREATE TABLE IF NOT EXISTS schema_migrations (
+  version bigint NOT NULL,
+  name varchar(255) NOT NULL,
+  checksum varchar(64) NOT NULL,
+  applied_at datetime(3) NOT NULL,
+  PRIMARY KEY (version)
+)`
+
+var models = []interface{}{
+	&domain.Users{},
+	&domain.Transactions{},
+	&domain.Universities{},
+	&domain.Province{},
+	&domain.Information{},
+	&domain.Merchants{},
+	&domain.Products{},
+	&domain.Mentors{},
+	&domain.Experiences{},
+	&domain.Categories{},
+	&domain.RecoveryCodes{},
+	&domain.Regencies{},
+	&domain.Districts{},
+	&domain.ProductPhotos{},
+	&domain.ImageReferences{},
+}
+
+var joinTables = map[string][]string{
+	"has_mentors":       {"user_id", "mentor_id"},
+	"user_like_product": {"user_id", "product_id"},
+}
+
+type expectedColumn struct {
+	name     string
+	dataType string
+}
+
+type expectedIndex struct {
+	name    string
+	columns []string
+	unique  bool
+}
+
+type Migration struct {
+	Version  int
+	Name     string
+	Up       string
+	Down     string
+	Checksum string
+}
+
+type MigrationStatus struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+type SchemaMigration struct {
+	Version   int `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+type IMigrator interface {
+	Status(ctx context.Context) ([]MigrationStatus, error)
+	Up(ctx context.Context) ([]Migration, error)
+	Down(ctx context.Context, steps int) ([]Migration, error)
+	Check(ctx context.Context) error
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+}
+
+func MigratorInit(db *gorm.DB) (IMigrator, error) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		migrations: migrations,
+	}, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]MigrationStatus, error) {
+	applied, err := m.applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.verifyHistory(applied)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	for _, migration := range m.migrations {
+		status := MigrationStatus{Migration: migration}
+		if record, ok := applied[migration.Version]; ok {
+			appliedAt := record.AppliedAt
+			status.AppliedAt = &appliedAt
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func (m *Migrator) Up(ctx context.Context) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(db *gorm.DB) error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = m.verifyHistory(applied)
+		if err != nil {
+			return err
+		}
+
+		for _, migration := range m.migrations {
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+
+			err = execStatements(db, migration.Up)
+			if err != nil {
+				return fmt.Errorf("failed to apply migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			err = db.Create(&SchemaMigration{
+				Version:   migration.Version,
+				Name:      migration.Name,
+				Checksum:  migration.Checksum,
+				AppliedAt: time.Now(),
+			}).Error
+			if err != nil {
+				return err
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	if steps < 1 {
+		return nil, fmt.Errorf("steps must be at least 1, got %d", steps)
+	}
+
+	var done []Migration
+	err := m.withLock(ctx, func(db *gorm.DB) error {
+		applied, err := m.applied(ctx)
+		if err != nil {
+			return err
+		}
+
+		err = m.verifyHistory(applied)
+		if err != nil {
+			return err
+		}
+
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+
+			err = execStatements(db, migration.Down)
+			if err != nil {
+				return fmt.Errorf("failed to roll back migration %04d_%s: %w", migration.Version, migration.Name, err)
+			}
+
+			err = db.Delete(&SchemaMigration{}, migration.Version).Error
+			if err != nil {
+				return err
+			}
+
+			done = append(done, migration)
+		}
+
+		return nil
+	})
+
+	return done, err
+}
+
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	var pending []string
+	for _, status := range statuses {
+		if status.AppliedAt == nil {
+			pending = append(pending, fmt.Sprintf("%04d_%s", status.Version, status.Name))
+		}
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %s", ErrPendingMigrations, strings.Join(pending, ", "))
+	}
+
+	return m.verifySchema(ctx)
+}
+
+func (m *Migrator) applied(ctx context.Context) (map[int]SchemaMigration, error) {
+	db := m.db.WithContext(ctx)
+
+	err := db.Exec(createSchemaMigrations).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to prepare schema_migrations: %w", err)
+	}
+
+	var records []SchemaMigration
+	err = db.Order("version").Find(&records).Error
+	if err != nil {
+		return nil, err
+	}
+
+	applied := make(map[int]SchemaMigration, len(records))
+	for _, record := range records {
+		applied[record.Version] = record
+	}
+
+	return applied, nil
+}
+
+func (m *Migrator) verifyHistory(applied map[int]SchemaMigration) error {
+	known := make(map[int]Migration, len(m.migrations))
+	for _, migration := range m.migrations {
+		known[migration.Version] = migration
+	}
+
+	var problems []string
+	for version, record := range applied {
+		migration, ok := known[version]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("migration %04d_%s is applied but missing from this build", version, record.Name))
+			continue
+		}
+		if migration.Checksum != record.Checksum {
+			problems = append(problems, fmt.Sprintf("migration %04d_%s was modified after it was applied", version, migration.Name))
+		}
+	}
+
+	return driftError(problems)
+}
+
+// verifySchema checks that every model's columns exist with the type the model
+// declares, and that the indexes and unique constraints it declares exist on
+// the same columns.
+func (m *Migrator) verifySchema(ctx context.Context) error {
+	db := m.db.WithContext(ctx)
+	migrator := db.Migrator()
+
+	var problems []string
+	checkTable := func(table string, columns []expectedColumn, indexes []expectedIndex) error {
+		if !migrator.HasTable(table) {
+			problems = append(problems, fmt.Sprintf("table %s is missing", table))
+			return nil
+		}
+
+		columnTypes, err := migrator.ColumnTypes(table)
+		if err != nil {
+			return err
+		}
+
+		existing := make(map[string]string, len(columnTypes))
+		for _, columnType := range columnTypes {
+			dataType, _ := columnType.ColumnType()
+			existing[columnType.Name()] = dataType
+		}
+
+		for _, column := range columns {
+			dataType, ok := existing[column.name]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("column %s.%s is missing", table, column.name))
+				continue
+			}
+			if column.dataType != "" && normalizeType(dataType) != normalizeType(column.dataType) {
+				problems = append(problems, fmt.Sprintf("column %s.%s is %s, expected %s", table, column.name, dataType, normalizeType(column.dataType)))
+			}
+		}
+
+		if len(indexes) == 0 {
+			return nil
+		}
+
+		existingIndexes, err := migrator.GetIndexes(table)
+		if err != nil {
+			return err
+		}
+
+		byName := make(map[string]gorm.Index, len(existingIndexes))
+		for _, index := range existingIndexes {
+			byName[index.Name()] = index
+		}
+
+		for _, index := range indexes {
+			found, ok := byName[index.name]
+			if !ok {
+				problems = append(problems, fmt.Sprintf("index %s on %s is missing", index.name, table))
+				continue
+			}
+
+			unique, _ := found.Unique()
+			if unique != index.unique || !slices.Equal(found.Columns(), index.columns) {
+				problems = append(problems, fmt.Sprintf("index %s on %s does not match the model", index.name, table))
+			}
+		}
+
+		return nil
+	}
+
+	cache := &sync.Map{}
+	for _, model := range models {
+		modelSchema, err := schema.Parse(model, cache, db.NamingStrategy)
+		if err != nil {
+			return err
+		}
+
+		var columns []expectedColumn
+		var indexes []expectedIndex
+		for _, field := range modelSchema.Fields {
+			if field.DBName == "" || field.IgnoreMigration {
+				continue
+			}
+
+			columns = append(columns, expectedColumn{field.DBName, db.Dialector.DataTypeOf(field)})
+			if field.Unique && !field.PrimaryKey {
+				indexes = append(indexes, expectedIndex{db.NamingStrategy.UniqueName(modelSchema.Table, field.DBName), []string{field.DBName}, true})
+			}
+		}
+
+		for _, index := range modelSchema.ParseIndexes() {
+			var indexColumns []string
+			for _, option := range index.Fields {
+				indexColumns = append(indexColumns, option.DBName)
+			}
+			indexes = append(indexes, expectedIndex{index.Name, indexColumns, index.Class == "UNIQUE"})
+		}
+
+		err = checkTable(modelSchema.Table, columns, indexes)
+		if err != nil {
+			return err
+		}
+	}
+
+	tables := make([]string, 0, len(joinTables))
+	for table := range joinTables {
+		tables = append(tables, table)
+	}
+	sort.Strings(tables)
+
+	for _, table := range tables {
+		var columns []expectedColumn
+		for _, column := range joinTables[table] {
+			columns = append(columns, expectedColumn{name: column})
+		}
+
+		err := checkTable(table, columns, nil)
+		if err != nil {
+			return err
+		}
+	}
+
+	return driftError(problems)
+}
+
+func (m *Migrator) withLock(ctx context.Context, fn func(db *gorm.DB) error) error {
+	return m.db.WithContext(ctx).Connection(func(db *gorm.DB) error {
+		var locked int
+		err := db.Raw("SELECT GET_LOCK('schema_migrations', ?)", migrationLockTimeout).Scan(&locked).Error
+		if err != nil {
+			return err
+		}
+		if locked != 1 {
+			return errors.New("another migration is already running")
+		}
+		defer db.Exec("SELECT RELEASE_LOCK('schema_migrations')")
+
+		return fn(db)
+	})
+}
+
+func driftError(problems []string) error {
+	if len(problems) == 0 {
+		return nil
+	}
+
+	sort.Strings(problems)
+	return fmt.Errorf("%w:\n  - %s", ErrSchemaDrift, strings.Join(problems, "\n  - "))
+}
+
+// normalizeType reduces a column type to the form MySQL reports it in, so the
+// type a model declares, such as "boolean" or "datetime(3) NULL", can be
+// compared with the type of the column.
+func normalizeType(dataType string) string {
+	dataType = strings.ToLower(strings.TrimSpace(dataType))
+	for _, attribute := range []string{" not null", " null", " default", " auto_increment", " on update", " primary key", " unique"} {
+		if i := strings.Index(dataType, attribute); i >= 0 {
+			dataType = dataType[:i]
+		}
+	}
+	dataType = strings.ReplaceAll(dataType, ", ", ",")
+
+	switch dataType {
+	case "bool", "boolean", "tinyint(1)":
+		return "tinyint(1)"
+	}
+
+	return integerWidth.ReplaceAllString(dataType, "$1")
+}
+
+func execStatements(db *gorm.DB, script string) error {
+	for _, statement := range splitStatements(script) {
+		err := db.Exec(statement).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+
+		current.WriteString(line)
+		current.WriteString("\n")
+
+		if strings.HasSuffix(trimmed, ";") {
+			statements = append(statements, strings.TrimSpace(current.String()))
+			current.Reset()
+		}
+	}
+
+	if rest := strings.TrimSpace(current.String()); rest != "" {
+		statements = append(statements, rest)
+	}
+
+	return statements
+}
+
+func loadMigrations(files fs.FS) ([]Migration, error) {
+	names, err := fs.Glob(files, "migrations/*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int]*Migration)
+	for _, name := range names {
+		base := path.Base(name)
+
+		var direction string
+		switch {
+		case strings.HasSuffix(base, ".up.sql"):
+			direction = "up"
+		case strings.HasSuffix(base, ".down.sql"):
+			direction = "down"
+		default:
+			return nil, fmt.Errorf("migration %s must end in .up.sql or .down.sql", base)
+		}
+
+		stem := strings.TrimSuffix(base, "."+direction+".sql")
+		versionString, migrationName, ok := strings.Cut(stem, "_")
+		if !ok {
+			return nil, fmt.Errorf("migration %s must be named <version>_<name>", base)
+		}
+
+		version, err := strconv.Atoi(versionString)
+		if err != nil {
+			return nil, fmt.Errorf("migration %s has an invalid version: %w", base, err)
+		}
+
+		content, err := fs.ReadFile(files, name)
+		if err != nil {
+			return nil, err
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &Migration{Version: version, Name: migrationName}
+			byVersion[version] = migration
+		}
+		if migration.Name != migrationName {
+			return nil, fmt.Errorf("migration %04d has conflicting names %s and %s", version, migration.Name, migrationName)
+		}
+
+		if direction == "up" {
+			migration.Up = string(content)
+		} else {
+			migration.Down = string(content)
+		}
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.Up == "" || migration.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s needs both an up and a down file", migration.Version, migration.Name)
+		}
+
+		sum := sha256.Sum256([]byte(migration.Up))
+		migration.Checksum = hex.EncodeToString(sum[:])
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
 }
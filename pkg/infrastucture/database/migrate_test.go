@@ -0,0 +1,292 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcmysql "github.com/testcontainers/testcontainers-go/modules/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestMySQL(t *testing.T) *gorm.DB {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+	container, err := tcmysql.RunContainer(ctx,
+		testcontainers.WithImage("mysql:8.0.36"),
+		tcmysql.WithDatabase("intern_bcc"),
+		tcmysql.WithUsername("test"),
+		tcmysql.WithPassword("test"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = container.Terminate(context.Background())
+	})
+
+	dsn, err := container.ConnectionString(ctx, "charset=utf8mb4", "parseTime=True")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestMigrationsRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	db := newTestMySQL(t)
+
+	migrator, err := MigratorInit(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	total := len(migrator.(*Migrator).migrations)
+
+	done, err := migrator.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != total {
+		t.Fatalf("expected %v migrations to be applied, got %v", total, len(done))
+	}
+
+	err = migrator.Check(ctx)
+	if err != nil {
+		t.Fatalf("expected the migrated schema to match the models, got %v", err)
+	}
+
+	// Rolling back the latest migration and applying it again must leave the
+	// schema as it was.
+	done, err = migrator.Down(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 1 {
+		t.Fatalf("expected one migration to be rolled back, got %v", len(done))
+	}
+
+	err = migrator.Check(ctx)
+	if !errors.Is(err, ErrPendingMigrations) {
+		t.Fatalf("expected the rolled back migration to be pending, got %v", err)
+	}
+
+	_, err = migrator.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = migrator.Check(ctx)
+	if err != nil {
+		t.Fatalf("expected the schema to match again after reapplying, got %v", err)
+	}
+
+	// Every down script must undo its up script, so a full rollback leaves no
+	// tables behind and a full reapply starts from a clean database.
+	done, err = migrator.Down(ctx, total)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != total {
+		t.Fatalf("expected %v migrations to be rolled back, got %v", total, len(done))
+	}
+
+	var tables []string
+	err = db.Raw("SELECT table_name FROM information_schema.tables WHERE table_schema = DATABASE() AND table_name <> 'schema_migrations'").Scan(&tables).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tables) > 0 {
+		t.Fatalf("expected a full rollback to drop every table, left %v", tables)
+	}
+
+	done, err = migrator.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != total {
+		t.Fatalf("expected %v migrations to be reapplied, got %v", total, len(done))
+	}
+
+	err = migrator.Check(ctx)
+	if err != nil {
+		t.Fatalf("expected the reapplied schema to match the models, got %v", err)
+	}
+}
+
+func TestCheckReportsChangedTypesAndMissingIndexes(t *testing.T) {
+	ctx := context.Background()
+	db := newTestMySQL(t)
+
+	migrator, err := MigratorInit(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = migrator.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.Exec("ALTER TABLE `categories` MODIFY `kind` varchar(40) NOT NULL DEFAULT 'product', DROP INDEX `idx_categories_kind`").Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = migrator.Check(ctx)
+	if !errors.Is(err, ErrSchemaDrift) {
+		t.Fatalf("expected schema drift, got %v", err)
+	}
+	for _, problem := range []string{"column categories.kind is varchar(40), expected varchar(20)", "index idx_categories_kind on categories is missing"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Fatalf("expected %q to be reported, got %v", problem, err)
+		}
+	}
+}
+
+func TestSplitStatements(t *testing.T) {
+	for _, test := range []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "empty",
+			script: "\n-- nothing to do\n\n",
+		},
+		{
+			name:   "statements spanning lines",
+			script: "CREATE TABLE `a` (\n  `id` bigint\n);\n\nALTER TABLE `a`\n  ADD COLUMN `b` bigint;\n",
+			want:   []string{"CREATE TABLE `a` (\n  `id` bigint\n);", "ALTER TABLE `a`\n  ADD COLUMN `b` bigint;"},
+		},
+		{
+			name:   "comments between and inside statements",
+			script: "-- first\nUPDATE `a` SET `b` = 1;\n  -- second\nUPDATE `a`\n-- still the second\nSET `b` = 2;",
+			want:   []string{"UPDATE `a` SET `b` = 1;", "UPDATE `a`\nSET `b` = 2;"},
+		},
+		{
+			name:   "trailing statement without semicolon",
+			script: "DELETE FROM `a`;\nDELETE FROM `b`",
+			want:   []string{"DELETE FROM `a`;", "DELETE FROM `b`"},
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			got := splitStatements(test.script)
+			if !slices.Equal(got, test.want) {
+				t.Fatalf("expected %q, got %q", test.want, got)
+			}
+		})
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations(fstest.MapFS{
+		"migrations/0010_later.up.sql":   {Data: []byte("UP 10")},
+		"migrations/0010_later.down.sql": {Data: []byte("DOWN 10")},
+		"migrations/0002_first.up.sql":   {Data: []byte("UP 2")},
+		"migrations/0002_first.down.sql": {Data: []byte("DOWN 2")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sum := sha256.Sum256([]byte("UP 2"))
+	if len(migrations) != 2 || migrations[0].Version != 2 || migrations[1].Version != 10 {
+		t.Fatalf("expected the migrations ordered by version, got %+v", migrations)
+	}
+	if migrations[0].Name != "first" || migrations[0].Up != "UP 2" || migrations[0].Down != "DOWN 2" || migrations[0].Checksum != hex.EncodeToString(sum[:]) {
+		t.Fatalf("expected the up and down scripts of 0002_first, got %+v", migrations[0])
+	}
+
+	for _, test := range []struct {
+		name    string
+		files   fstest.MapFS
+		problem string
+	}{
+		{
+			name:    "missing down",
+			files:   fstest.MapFS{"migrations/0001_a.up.sql": {Data: []byte("UP")}},
+			problem: "needs both an up and a down file",
+		},
+		{
+			name:    "unknown direction",
+			files:   fstest.MapFS{"migrations/0001_a.sql": {Data: []byte("UP")}},
+			problem: "must end in .up.sql or .down.sql",
+		},
+		{
+			name:    "missing name",
+			files:   fstest.MapFS{"migrations/0001.up.sql": {Data: []byte("UP")}},
+			problem: "must be named <version>_<name>",
+		},
+		{
+			name:    "invalid version",
+			files:   fstest.MapFS{"migrations/first_a.up.sql": {Data: []byte("UP")}},
+			problem: "has an invalid version",
+		},
+		{
+			name: "conflicting names",
+			files: fstest.MapFS{
+				"migrations/0001_a.up.sql":   {Data: []byte("UP")},
+				"migrations/0001_b.down.sql": {Data: []byte("DOWN")},
+			},
+			problem: "has conflicting names",
+		},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := loadMigrations(test.files)
+			if err == nil || !strings.Contains(err.Error(), test.problem) {
+				t.Fatalf("expected an error containing %q, got %v", test.problem, err)
+			}
+		})
+	}
+}
+
+func TestEmbeddedMigrationsLoad(t *testing.T) {
+	migrations, err := loadMigrations(migrationFiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i, migration := range migrations {
+		if migration.Version != i+1 {
+			t.Fatalf("expected migration versions without gaps, got %04d_%s at position %v", migration.Version, migration.Name, i+1)
+		}
+	}
+}
+
+func TestNormalizeType(t *testing.T) {
+	for _, test := range []struct {
+		declared string
+		reported string
+	}{
+		{"boolean", "tinyint(1)"},
+		{"datetime(3) NULL", "datetime(3)"},
+		{"datetime NULL DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP", "datetime"},
+		{"bigint AUTO_INCREMENT", "bigint(20)"},
+		{"bigint unsigned AUTO_INCREMENT", "bigint(20) unsigned"},
+		{"enum('Laki-laki', 'Perempuan', '') NULL", "enum('Laki-laki','Perempuan','')"},
+		{"varchar(36)", "VARCHAR(36)"},
+	} {
+		if normalizeType(test.declared) != normalizeType(test.reported) {
+			t.Fatalf("expected %q to match %q, got %q and %q", test.declared, test.reported, normalizeType(test.declared), normalizeType(test.reported))
+		}
+	}
+
+	if normalizeType("varchar(191)") == normalizeType("longtext") || normalizeType("tinyint(1)") == normalizeType("tinyint(4)") {
+		t.Fatal("expected different types not to match")
+	}
+}
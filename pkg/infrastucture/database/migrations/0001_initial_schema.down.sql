@@ -0,0 +1,13 @@
+DROP TABLE IF EXISTS `user_like_product`;
+DROP TABLE IF EXISTS `has_mentors`;
+DROP TABLE IF EXISTS `recovery_codes`;
+DROP TABLE IF EXISTS `transactions`;
+DROP TABLE IF EXISTS `experiences`;
+DROP TABLE IF EXISTS `mentors`;
+DROP TABLE IF EXISTS `information`;
+DROP TABLE IF EXISTS `products`;
+DROP TABLE IF EXISTS `merchants`;
+DROP TABLE IF EXISTS `categories`;
+DROP TABLE IF EXISTS `universities`;
+DROP TABLE IF EXISTS `provinces`;
+DROP TABLE IF EXISTS `users`;
@@ -0,0 +1,158 @@
+CREATE TABLE IF NOT EXISTS `users` (
+  `id` varchar(36) NOT NULL,
+  `name` varchar(191) DEFAULT NULL,
+  `email` varchar(191) DEFAULT NULL,
+  `password` longtext,
+  `gender` enum('Laki-laki','Perempuan','') NULL,
+  `place_birth` longtext,
+  `date_birth` longtext,
+  `is_admin` tinyint(1) DEFAULT NULL,
+  `profile_picture` longtext,
+  `two_factor` tinyint(1) DEFAULT NULL,
+  `two_factor_key` longtext,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uni_users_name` (`name`),
+  UNIQUE KEY `uni_users_email` (`email`)
+);
+
+CREATE TABLE IF NOT EXISTS `provinces` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `province` varchar(191) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uni_provinces_province` (`province`)
+);
+
+CREATE TABLE IF NOT EXISTS `universities` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `university` varchar(191) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uni_universities_university` (`university`)
+);
+
+CREATE TABLE IF NOT EXISTS `categories` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `category` varchar(191) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uni_categories_category` (`category`)
+);
+
+CREATE TABLE IF NOT EXISTS `merchants` (
+  `id` varchar(36) NOT NULL,
+  `user_id` varchar(36) DEFAULT NULL,
+  `merchant_name` longtext,
+  `university_id` bigint DEFAULT NULL,
+  `faculty` longtext,
+  `province_id` bigint DEFAULT NULL,
+  `city` longtext,
+  `phone_number` longtext,
+  `instagram` longtext,
+  `merchant_photo` longtext,
+  `is_active` tinyint(1) DEFAULT NULL,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uni_merchants_user_id` (`user_id`),
+  KEY `idx_merchants_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_users_merchant` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`),
+  CONSTRAINT `fk_universities_merchants` FOREIGN KEY (`university_id`) REFERENCES `universities` (`id`),
+  CONSTRAINT `fk_provinces_merchants` FOREIGN KEY (`province_id`) REFERENCES `provinces` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `products` (
+  `id` varchar(36) NOT NULL,
+  `merchant_id` varchar(36) DEFAULT NULL,
+  `category_id` bigint DEFAULT NULL,
+  `name` longtext,
+  `price` bigint unsigned DEFAULT NULL,
+  `description` longtext,
+  `product_photo` longtext,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_products_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_merchants_products` FOREIGN KEY (`merchant_id`) REFERENCES `merchants` (`id`),
+  CONSTRAINT `fk_categories_product` FOREIGN KEY (`category_id`) REFERENCES `categories` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `information` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `title` longtext,
+  `category_id` bigint DEFAULT NULL,
+  `synopsis` longtext,
+  `content` longtext,
+  `information_photo` longtext,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_information_deleted_at` (`deleted_at`),
+  CONSTRAINT `fk_categories_information` FOREIGN KEY (`category_id`) REFERENCES `categories` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `mentors` (
+  `id` varchar(36) NOT NULL,
+  `name` varchar(191) DEFAULT NULL,
+  `current_job` longtext,
+  `description` longtext,
+  `price` bigint unsigned DEFAULT NULL,
+  `mentor_picture` longtext,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  `deleted_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uni_mentors_name` (`name`),
+  KEY `idx_mentors_deleted_at` (`deleted_at`)
+);
+
+CREATE TABLE IF NOT EXISTS `experiences` (
+  `mentor_id` varchar(36) DEFAULT NULL,
+  `experience` longtext,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  KEY `fk_mentors_experiences` (`mentor_id`),
+  CONSTRAINT `fk_mentors_experiences` FOREIGN KEY (`mentor_id`) REFERENCES `mentors` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `transactions` (
+  `id` varchar(36) NOT NULL,
+  `user_id` varchar(36) DEFAULT NULL,
+  `mentor_id` varchar(36) DEFAULT NULL,
+  `price` bigint unsigned DEFAULT NULL,
+  `is_payed` tinyint(1) DEFAULT NULL,
+  `payment_type` longtext,
+  `created_at` datetime(3) DEFAULT NULL,
+  `payed_at` datetime NULL DEFAULT NULL ON UPDATE CURRENT_TIMESTAMP,
+  PRIMARY KEY (`id`),
+  CONSTRAINT `fk_users_transactions` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`),
+  CONSTRAINT `fk_mentors_transactions` FOREIGN KEY (`mentor_id`) REFERENCES `mentors` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `recovery_codes` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `user_id` varchar(36) DEFAULT NULL,
+  `code` longtext,
+  `created_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_recovery_codes_user_id` (`user_id`),
+  CONSTRAINT `fk_users_recovery_codes` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `has_mentors` (
+  `user_id` varchar(36) NOT NULL,
+  `mentor_id` varchar(36) NOT NULL,
+  PRIMARY KEY (`user_id`, `mentor_id`),
+  CONSTRAINT `fk_has_mentors_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`),
+  CONSTRAINT `fk_has_mentors_mentors` FOREIGN KEY (`mentor_id`) REFERENCES `mentors` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `user_like_product` (
+  `user_id` varchar(36) NOT NULL,
+  `product_id` varchar(36) NOT NULL,
+  PRIMARY KEY (`user_id`, `product_id`),
+  CONSTRAINT `fk_user_like_product_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`),
+  CONSTRAINT `fk_user_like_product_products` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`)
+);
@@ -0,0 +1,5 @@
+ALTER TABLE `categories`
+  DROP FOREIGN KEY `fk_categories_parent`,
+  DROP KEY `idx_categories_kind`,
+  DROP COLUMN `parent_id`,
+  DROP COLUMN `kind`;
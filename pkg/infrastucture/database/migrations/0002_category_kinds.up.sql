@@ -0,0 +1,9 @@
+ALTER TABLE `categories`
+  ADD COLUMN `kind` varchar(20) NOT NULL DEFAULT 'product',
+  ADD COLUMN `parent_id` bigint DEFAULT NULL,
+  ADD KEY `idx_categories_kind` (`kind`),
+  ADD CONSTRAINT `fk_categories_parent` FOREIGN KEY (`parent_id`) REFERENCES `categories` (`id`);
+
+UPDATE `categories` SET `kind` = 'article' WHERE `id` = 7;
+UPDATE `categories` SET `kind` = 'webinar' WHERE `id` = 8;
+UPDATE `categories` SET `kind` = 'competition' WHERE `id` = 9;
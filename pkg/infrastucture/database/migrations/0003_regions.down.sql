@@ -0,0 +1,13 @@
+ALTER TABLE `merchants`
+  DROP FOREIGN KEY `fk_districts_merchants`,
+  DROP FOREIGN KEY `fk_regencies_merchants`,
+  DROP KEY `idx_merchants_regency_id`,
+  DROP COLUMN `district_id`,
+  DROP COLUMN `regency_id`;
+
+DROP TABLE IF EXISTS `districts`;
+DROP TABLE IF EXISTS `regencies`;
+
+ALTER TABLE `provinces`
+  DROP KEY `uni_provinces_code`,
+  DROP COLUMN `code`;
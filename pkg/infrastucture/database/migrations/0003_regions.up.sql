@@ -0,0 +1,32 @@
+ALTER TABLE `provinces`
+  ADD COLUMN `code` varchar(2) DEFAULT NULL,
+  ADD UNIQUE KEY `uni_provinces_code` (`code`);
+
+CREATE TABLE IF NOT EXISTS `regencies` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `province_id` bigint NOT NULL,
+  `code` varchar(5) NOT NULL,
+  `name` varchar(191) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uni_regencies_code` (`code`),
+  KEY `idx_regencies_province_id_name` (`province_id`, `name`),
+  CONSTRAINT `fk_provinces_regencies` FOREIGN KEY (`province_id`) REFERENCES `provinces` (`id`)
+);
+
+CREATE TABLE IF NOT EXISTS `districts` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `regency_id` bigint NOT NULL,
+  `code` varchar(8) NOT NULL,
+  `name` varchar(191) NOT NULL,
+  PRIMARY KEY (`id`),
+  UNIQUE KEY `uni_districts_code` (`code`),
+  KEY `idx_districts_regency_id` (`regency_id`),
+  CONSTRAINT `fk_regencies_districts` FOREIGN KEY (`regency_id`) REFERENCES `regencies` (`id`)
+);
+
+ALTER TABLE `merchants`
+  ADD COLUMN `regency_id` bigint DEFAULT NULL,
+  ADD COLUMN `district_id` bigint DEFAULT NULL,
+  ADD KEY `idx_merchants_regency_id` (`regency_id`),
+  ADD CONSTRAINT `fk_regencies_merchants` FOREIGN KEY (`regency_id`) REFERENCES `regencies` (`id`),
+  ADD CONSTRAINT `fk_districts_merchants` FOREIGN KEY (`district_id`) REFERENCES `districts` (`id`);
@@ -0,0 +1,9 @@
+ALTER TABLE `merchants`
+  DROP KEY `idx_merchants_location`,
+  DROP COLUMN `longitude`,
+  DROP COLUMN `latitude`;
+
+ALTER TABLE `universities`
+  DROP KEY `idx_universities_location`,
+  DROP COLUMN `longitude`,
+  DROP COLUMN `latitude`;
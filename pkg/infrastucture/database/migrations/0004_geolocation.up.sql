@@ -0,0 +1,9 @@
+ALTER TABLE `universities`
+  ADD COLUMN `latitude` decimal(10,7) DEFAULT NULL,
+  ADD COLUMN `longitude` decimal(10,7) DEFAULT NULL,
+  ADD KEY `idx_universities_location` (`latitude`, `longitude`);
+
+ALTER TABLE `merchants`
+  ADD COLUMN `latitude` decimal(10,7) DEFAULT NULL,
+  ADD COLUMN `longitude` decimal(10,7) DEFAULT NULL,
+  ADD KEY `idx_merchants_location` (`latitude`, `longitude`);
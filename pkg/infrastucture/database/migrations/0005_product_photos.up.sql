@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS `product_photos` (
+  `id` varchar(36) NOT NULL,
+  `product_id` varchar(36) NOT NULL,
+  `url` longtext NOT NULL,
+  `position` bigint NOT NULL DEFAULT 0,
+  `is_primary` tinyint(1) NOT NULL DEFAULT 0,
+  `created_at` datetime(3) DEFAULT NULL,
+  `updated_at` datetime(3) DEFAULT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_product_photos_product_id` (`product_id`),
+  CONSTRAINT `fk_products_photos` FOREIGN KEY (`product_id`) REFERENCES `products` (`id`)
+);
+
+INSERT INTO `product_photos` (`id`, `product_id`, `url`, `position`, `is_primary`, `created_at`, `updated_at`)
+SELECT UUID(), `id`, `product_photo`, 0, 1, NOW(3), NOW(3)
+FROM `products`
+WHERE `product_photo` IS NOT NULL AND `product_photo` <> ''
+  AND NOT EXISTS (SELECT 1 FROM `product_photos` WHERE `product_photos`.`product_id` = `products`.`id`);
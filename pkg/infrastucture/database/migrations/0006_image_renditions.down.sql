@@ -0,0 +1,23 @@
+ALTER TABLE `information`
+  DROP COLUMN `information_photo_thumbnail`,
+  DROP COLUMN `information_photo_medium`;
+
+ALTER TABLE `mentors`
+  DROP COLUMN `mentor_picture_thumbnail`,
+  DROP COLUMN `mentor_picture_medium`;
+
+ALTER TABLE `product_photos`
+  DROP COLUMN `thumbnail`,
+  DROP COLUMN `medium`;
+
+ALTER TABLE `products`
+  DROP COLUMN `product_photo_thumbnail`,
+  DROP COLUMN `product_photo_medium`;
+
+ALTER TABLE `merchants`
+  DROP COLUMN `merchant_photo_thumbnail`,
+  DROP COLUMN `merchant_photo_medium`;
+
+ALTER TABLE `users`
+  DROP COLUMN `profile_picture_thumbnail`,
+  DROP COLUMN `profile_picture_medium`;
@@ -0,0 +1,23 @@
+ALTER TABLE `users`
+  ADD COLUMN `profile_picture_medium` longtext,
+  ADD COLUMN `profile_picture_thumbnail` longtext;
+
+ALTER TABLE `merchants`
+  ADD COLUMN `merchant_photo_medium` longtext,
+  ADD COLUMN `merchant_photo_thumbnail` longtext;
+
+ALTER TABLE `products`
+  ADD COLUMN `product_photo_medium` longtext,
+  ADD COLUMN `product_photo_thumbnail` longtext;
+
+ALTER TABLE `product_photos`
+  ADD COLUMN `medium` longtext,
+  ADD COLUMN `thumbnail` longtext;
+
+ALTER TABLE `mentors`
+  ADD COLUMN `mentor_picture_medium` longtext,
+  ADD COLUMN `mentor_picture_thumbnail` longtext;
+
+ALTER TABLE `information`
+  ADD COLUMN `information_photo_medium` longtext,
+  ADD COLUMN `information_photo_thumbnail` longtext;
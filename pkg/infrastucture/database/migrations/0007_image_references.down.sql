@@ -0,0 +1,31 @@
+DROP TRIGGER IF EXISTS `information_image_references_delete`;
+DROP TRIGGER IF EXISTS `information_image_references_update_insert`;
+DROP TRIGGER IF EXISTS `information_image_references_update_delete`;
+DROP TRIGGER IF EXISTS `information_image_references_insert`;
+
+DROP TRIGGER IF EXISTS `mentors_image_references_delete`;
+DROP TRIGGER IF EXISTS `mentors_image_references_update_insert`;
+DROP TRIGGER IF EXISTS `mentors_image_references_update_delete`;
+DROP TRIGGER IF EXISTS `mentors_image_references_insert`;
+
+DROP TRIGGER IF EXISTS `product_photos_image_references_delete`;
+DROP TRIGGER IF EXISTS `product_photos_image_references_update_insert`;
+DROP TRIGGER IF EXISTS `product_photos_image_references_update_delete`;
+DROP TRIGGER IF EXISTS `product_photos_image_references_insert`;
+
+DROP TRIGGER IF EXISTS `products_image_references_delete`;
+DROP TRIGGER IF EXISTS `products_image_references_update_insert`;
+DROP TRIGGER IF EXISTS `products_image_references_update_delete`;
+DROP TRIGGER IF EXISTS `products_image_references_insert`;
+
+DROP TRIGGER IF EXISTS `merchants_image_references_delete`;
+DROP TRIGGER IF EXISTS `merchants_image_references_update_insert`;
+DROP TRIGGER IF EXISTS `merchants_image_references_update_delete`;
+DROP TRIGGER IF EXISTS `merchants_image_references_insert`;
+
+DROP TRIGGER IF EXISTS `users_image_references_delete`;
+DROP TRIGGER IF EXISTS `users_image_references_update_insert`;
+DROP TRIGGER IF EXISTS `users_image_references_update_delete`;
+DROP TRIGGER IF EXISTS `users_image_references_insert`;
+
+DROP TABLE IF EXISTS `image_references`;
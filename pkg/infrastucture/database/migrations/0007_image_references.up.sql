@@ -0,0 +1,224 @@
+-- image_references lists every stored file a record uses, so deleting an
+-- unused file is an indexed lookup instead of a scan of every image column.
+-- Records keep it up to date through the triggers below; uploads add their
+-- own short lived rows with the `uploads` owner.
+CREATE TABLE IF NOT EXISTS `image_references` (
+  `id` bigint unsigned NOT NULL AUTO_INCREMENT,
+  `link` varchar(768) NOT NULL,
+  `owner` varchar(32) NOT NULL,
+  `owner_id` varchar(36) NOT NULL,
+  `created_at` datetime(3) NOT NULL,
+  PRIMARY KEY (`id`),
+  KEY `idx_image_references_link` (`link`),
+  KEY `idx_image_references_owner` (`owner`, `owner_id`)
+);
+
+CREATE TRIGGER `users_image_references_insert` AFTER INSERT ON `users` FOR EACH ROW
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'users', NEW.`id`, NOW(3) FROM (
+  SELECT NEW.`profile_picture` AS `link`
+  UNION ALL SELECT NEW.`profile_picture_medium`
+  UNION ALL SELECT NEW.`profile_picture_thumbnail`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> '';
+
+CREATE TRIGGER `users_image_references_update_delete` AFTER UPDATE ON `users` FOR EACH ROW
+DELETE FROM `image_references`
+WHERE `owner` = 'users' AND `owner_id` = OLD.`id`
+  AND NOT (OLD.`id` <=> NEW.`id` AND OLD.`profile_picture` <=> NEW.`profile_picture` AND OLD.`profile_picture_medium` <=> NEW.`profile_picture_medium` AND OLD.`profile_picture_thumbnail` <=> NEW.`profile_picture_thumbnail`);
+
+CREATE TRIGGER `users_image_references_update_insert` AFTER UPDATE ON `users` FOR EACH ROW
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'users', NEW.`id`, NOW(3) FROM (
+  SELECT NEW.`profile_picture` AS `link`
+  UNION ALL SELECT NEW.`profile_picture_medium`
+  UNION ALL SELECT NEW.`profile_picture_thumbnail`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> ''
+  AND NOT (OLD.`id` <=> NEW.`id` AND OLD.`profile_picture` <=> NEW.`profile_picture` AND OLD.`profile_picture_medium` <=> NEW.`profile_picture_medium` AND OLD.`profile_picture_thumbnail` <=> NEW.`profile_picture_thumbnail`);
+
+CREATE TRIGGER `users_image_references_delete` AFTER DELETE ON `users` FOR EACH ROW
+DELETE FROM `image_references` WHERE `owner` = 'users' AND `owner_id` = OLD.`id`;
+
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'users', `id`, NOW(3) FROM (
+  SELECT `id`, `profile_picture` AS `link` FROM `users`
+  UNION ALL SELECT `id`, `profile_picture_medium` FROM `users`
+  UNION ALL SELECT `id`, `profile_picture_thumbnail` FROM `users`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> '';
+
+CREATE TRIGGER `merchants_image_references_insert` AFTER INSERT ON `merchants` FOR EACH ROW
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'merchants', NEW.`id`, NOW(3) FROM (
+  SELECT NEW.`merchant_photo` AS `link`
+  UNION ALL SELECT NEW.`merchant_photo_medium`
+  UNION ALL SELECT NEW.`merchant_photo_thumbnail`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> '';
+
+CREATE TRIGGER `merchants_image_references_update_delete` AFTER UPDATE ON `merchants` FOR EACH ROW
+DELETE FROM `image_references`
+WHERE `owner` = 'merchants' AND `owner_id` = OLD.`id`
+  AND NOT (OLD.`id` <=> NEW.`id` AND OLD.`merchant_photo` <=> NEW.`merchant_photo` AND OLD.`merchant_photo_medium` <=> NEW.`merchant_photo_medium` AND OLD.`merchant_photo_thumbnail` <=> NEW.`merchant_photo_thumbnail`);
+
+CREATE TRIGGER `merchants_image_references_update_insert` AFTER UPDATE ON `merchants` FOR EACH ROW
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'merchants', NEW.`id`, NOW(3) FROM (
+  SELECT NEW.`merchant_photo` AS `link`
+  UNION ALL SELECT NEW.`merchant_photo_medium`
+  UNION ALL SELECT NEW.`merchant_photo_thumbnail`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> ''
+  AND NOT (OLD.`id` <=> NEW.`id` AND OLD.`merchant_photo` <=> NEW.`merchant_photo` AND OLD.`merchant_photo_medium` <=> NEW.`merchant_photo_medium` AND OLD.`merchant_photo_thumbnail` <=> NEW.`merchant_photo_thumbnail`);
+
+CREATE TRIGGER `merchants_image_references_delete` AFTER DELETE ON `merchants` FOR EACH ROW
+DELETE FROM `image_references` WHERE `owner` = 'merchants' AND `owner_id` = OLD.`id`;
+
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'merchants', `id`, NOW(3) FROM (
+  SELECT `id`, `merchant_photo` AS `link` FROM `merchants`
+  UNION ALL SELECT `id`, `merchant_photo_medium` FROM `merchants`
+  UNION ALL SELECT `id`, `merchant_photo_thumbnail` FROM `merchants`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> '';
+
+CREATE TRIGGER `products_image_references_insert` AFTER INSERT ON `products` FOR EACH ROW
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'products', NEW.`id`, NOW(3) FROM (
+  SELECT NEW.`product_photo` AS `link`
+  UNION ALL SELECT NEW.`product_photo_medium`
+  UNION ALL SELECT NEW.`product_photo_thumbnail`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> '';
+
+CREATE TRIGGER `products_image_references_update_delete` AFTER UPDATE ON `products` FOR EACH ROW
+DELETE FROM `image_references`
+WHERE `owner` = 'products' AND `owner_id` = OLD.`id`
+  AND NOT (OLD.`id` <=> NEW.`id` AND OLD.`product_photo` <=> NEW.`product_photo` AND OLD.`product_photo_medium` <=> NEW.`product_photo_medium` AND OLD.`product_photo_thumbnail` <=> NEW.`product_photo_thumbnail`);
+
+CREATE TRIGGER `products_image_references_update_insert` AFTER UPDATE ON `products` FOR EACH ROW
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'products', NEW.`id`, NOW(3) FROM (
+  SELECT NEW.`product_photo` AS `link`
+  UNION ALL SELECT NEW.`product_photo_medium`
+  UNION ALL SELECT NEW.`product_photo_thumbnail`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> ''
+  AND NOT (OLD.`id` <=> NEW.`id` AND OLD.`product_photo` <=> NEW.`product_photo` AND OLD.`product_photo_medium` <=> NEW.`product_photo_medium` AND OLD.`product_photo_thumbnail` <=> NEW.`product_photo_thumbnail`);
+
+CREATE TRIGGER `products_image_references_delete` AFTER DELETE ON `products` FOR EACH ROW
+DELETE FROM `image_references` WHERE `owner` = 'products' AND `owner_id` = OLD.`id`;
+
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'products', `id`, NOW(3) FROM (
+  SELECT `id`, `product_photo` AS `link` FROM `products`
+  UNION ALL SELECT `id`, `product_photo_medium` FROM `products`
+  UNION ALL SELECT `id`, `product_photo_thumbnail` FROM `products`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> '';
+
+CREATE TRIGGER `product_photos_image_references_insert` AFTER INSERT ON `product_photos` FOR EACH ROW
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'product_photos', NEW.`id`, NOW(3) FROM (
+  SELECT NEW.`url` AS `link`
+  UNION ALL SELECT NEW.`medium`
+  UNION ALL SELECT NEW.`thumbnail`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> '';
+
+CREATE TRIGGER `product_photos_image_references_update_delete` AFTER UPDATE ON `product_photos` FOR EACH ROW
+DELETE FROM `image_references`
+WHERE `owner` = 'product_photos' AND `owner_id` = OLD.`id`
+  AND NOT (OLD.`id` <=> NEW.`id` AND OLD.`url` <=> NEW.`url` AND OLD.`medium` <=> NEW.`medium` AND OLD.`thumbnail` <=> NEW.`thumbnail`);
+
+CREATE TRIGGER `product_photos_image_references_update_insert` AFTER UPDATE ON `product_photos` FOR EACH ROW
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'product_photos', NEW.`id`, NOW(3) FROM (
+  SELECT NEW.`url` AS `link`
+  UNION ALL SELECT NEW.`medium`
+  UNION ALL SELECT NEW.`thumbnail`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> ''
+  AND NOT (OLD.`id` <=> NEW.`id` AND OLD.`url` <=> NEW.`url` AND OLD.`medium` <=> NEW.`medium` AND OLD.`thumbnail` <=> NEW.`thumbnail`);
+
+CREATE TRIGGER `product_photos_image_references_delete` AFTER DELETE ON `product_photos` FOR EACH ROW
+DELETE FROM `image_references` WHERE `owner` = 'product_photos' AND `owner_id` = OLD.`id`;
+
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'product_photos', `id`, NOW(3) FROM (
+  SELECT `id`, `url` AS `link` FROM `product_photos`
+  UNION ALL SELECT `id`, `medium` FROM `product_photos`
+  UNION ALL SELECT `id`, `thumbnail` FROM `product_photos`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> '';
+
+CREATE TRIGGER `mentors_image_references_insert` AFTER INSERT ON `mentors` FOR EACH ROW
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'mentors', NEW.`id`, NOW(3) FROM (
+  SELECT NEW.`mentor_picture` AS `link`
+  UNION ALL SELECT NEW.`mentor_picture_medium`
+  UNION ALL SELECT NEW.`mentor_picture_thumbnail`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> '';
+
+CREATE TRIGGER `mentors_image_references_update_delete` AFTER UPDATE ON `mentors` FOR EACH ROW
+DELETE FROM `image_references`
+WHERE `owner` = 'mentors' AND `owner_id` = OLD.`id`
+  AND NOT (OLD.`id` <=> NEW.`id` AND OLD.`mentor_picture` <=> NEW.`mentor_picture` AND OLD.`mentor_picture_medium` <=> NEW.`mentor_picture_medium` AND OLD.`mentor_picture_thumbnail` <=> NEW.`mentor_picture_thumbnail`);
+
+CREATE TRIGGER `mentors_image_references_update_insert` AFTER UPDATE ON `mentors` FOR EACH ROW
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'mentors', NEW.`id`, NOW(3) FROM (
+  SELECT NEW.`mentor_picture` AS `link`
+  UNION ALL SELECT NEW.`mentor_picture_medium`
+  UNION ALL SELECT NEW.`mentor_picture_thumbnail`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> ''
+  AND NOT (OLD.`id` <=> NEW.`id` AND OLD.`mentor_picture` <=> NEW.`mentor_picture` AND OLD.`mentor_picture_medium` <=> NEW.`mentor_picture_medium` AND OLD.`mentor_picture_thumbnail` <=> NEW.`mentor_picture_thumbnail`);
+
+CREATE TRIGGER `mentors_image_references_delete` AFTER DELETE ON `mentors` FOR EACH ROW
+DELETE FROM `image_references` WHERE `owner` = 'mentors' AND `owner_id` = OLD.`id`;
+
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'mentors', `id`, NOW(3) FROM (
+  SELECT `id`, `mentor_picture` AS `link` FROM `mentors`
+  UNION ALL SELECT `id`, `mentor_picture_medium` FROM `mentors`
+  UNION ALL SELECT `id`, `mentor_picture_thumbnail` FROM `mentors`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> '';
+
+CREATE TRIGGER `information_image_references_insert` AFTER INSERT ON `information` FOR EACH ROW
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'information', NEW.`id`, NOW(3) FROM (
+  SELECT NEW.`information_photo` AS `link`
+  UNION ALL SELECT NEW.`information_photo_medium`
+  UNION ALL SELECT NEW.`information_photo_thumbnail`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> '';
+
+CREATE TRIGGER `information_image_references_update_delete` AFTER UPDATE ON `information` FOR EACH ROW
+DELETE FROM `image_references`
+WHERE `owner` = 'information' AND `owner_id` = OLD.`id`
+  AND NOT (OLD.`id` <=> NEW.`id` AND OLD.`information_photo` <=> NEW.`information_photo` AND OLD.`information_photo_medium` <=> NEW.`information_photo_medium` AND OLD.`information_photo_thumbnail` <=> NEW.`information_photo_thumbnail`);
+
+CREATE TRIGGER `information_image_references_update_insert` AFTER UPDATE ON `information` FOR EACH ROW
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'information', NEW.`id`, NOW(3) FROM (
+  SELECT NEW.`information_photo` AS `link`
+  UNION ALL SELECT NEW.`information_photo_medium`
+  UNION ALL SELECT NEW.`information_photo_thumbnail`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> ''
+  AND NOT (OLD.`id` <=> NEW.`id` AND OLD.`information_photo` <=> NEW.`information_photo` AND OLD.`information_photo_medium` <=> NEW.`information_photo_medium` AND OLD.`information_photo_thumbnail` <=> NEW.`information_photo_thumbnail`);
+
+CREATE TRIGGER `information_image_references_delete` AFTER DELETE ON `information` FOR EACH ROW
+DELETE FROM `image_references` WHERE `owner` = 'information' AND `owner_id` = OLD.`id`;
+
+INSERT INTO `image_references` (`link`, `owner`, `owner_id`, `created_at`)
+SELECT `link`, 'information', `id`, NOW(3) FROM (
+  SELECT `id`, `information_photo` AS `link` FROM `information`
+  UNION ALL SELECT `id`, `information_photo_medium` FROM `information`
+  UNION ALL SELECT `id`, `information_photo_thumbnail` FROM `information`
+) AS `links`
+WHERE `link` IS NOT NULL AND `link` <> '';
@@ -2,28 +2,26 @@ package database
 
 import (
 	"fmt"
-	"log"
-	"os"
+	"intern-bcc/pkg/config"
+	"intern-bcc/pkg/logging"
 
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
 
-var DB *gorm.DB
-
-func ConnectToDB() {
+func ConnectToDB(cfg config.DatabaseConfig) (*gorm.DB, error) {
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		os.Getenv("DB_USER"),
-		os.Getenv("DB_PASS"),
-		os.Getenv("DB_HOST"),
-		os.Getenv("DB_PORT"),
-		os.Getenv("DB_NAME"),
+		cfg.User,
+		cfg.Password,
+		cfg.Host,
+		cfg.Port,
+		cfg.Name,
 	)
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logging.GormLogger()})
 	if err != nil {
-		log.Fatal("failed to connect to database")
+		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
 
-	DB = db
+	return db, nil
 }
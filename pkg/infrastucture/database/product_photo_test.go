@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/cache"
+	"sync"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestCreatePhotoKeepsConcurrentUploadsWithinTheLimit(t *testing.T) {
+	ctx := context.Background()
+	db := newTestMySQL(t)
+	records := createMerchantRecords(t, db)
+	product := records.products[0]
+
+	const maxPhotos = 8
+	photos := repository.NewProductPhotoRepository(db, cache.MemoryInit(100))
+
+	// The product already has one photo, so only seven of these fit.
+	var wg sync.WaitGroup
+	errs := make([]error, 12)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			errs[i] = photos.CreatePhoto(ctx, &domain.ProductPhotos{Id: uuid.New(), ProductId: product.Id, Url: "http://storage/new.jpg"}, maxPhotos)
+		}(i)
+	}
+	wg.Wait()
+
+	var created, rejected int
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			created++
+		case errors.Is(err, repository.ErrPhotoLimitReached):
+			rejected++
+		default:
+			t.Fatal(err)
+		}
+	}
+	if created != maxPhotos-1 || rejected != len(errs)-created {
+		t.Fatalf("expected %v photos to be created, got %v created and %v rejected", maxPhotos-1, created, rejected)
+	}
+
+	var stored []domain.ProductPhotos
+	err := photos.GetPhotos(ctx, &stored, product.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(stored) != maxPhotos {
+		t.Fatalf("expected %v photos, got %v", maxPhotos, len(stored))
+	}
+
+	positions := make(map[int]bool)
+	primaries := 0
+	for _, photo := range stored {
+		positions[photo.Position] = true
+		if photo.IsPrimary {
+			primaries++
+		}
+	}
+	if len(positions) != maxPhotos || primaries != 1 {
+		t.Fatalf("expected distinct positions and one primary photo, got %+v", stored)
+	}
+}
@@ -0,0 +1,184 @@
+package database
+
+import (
+	"context"
+	"intern-bcc/domain"
+	"intern-bcc/internal/repository"
+	"intern-bcc/pkg/cache"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type merchantRecords struct {
+	user     domain.Users
+	merchant domain.Merchants
+	products []domain.Products
+}
+
+// createMerchantRecords migrates db and creates a user with a merchant and two
+// products, each with a photo.
+func createMerchantRecords(t *testing.T, db *gorm.DB) merchantRecords {
+	t.Helper()
+
+	migrator, err := MigratorInit(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = migrator.Up(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	province := domain.Province{Province: "Jawa Timur"}
+	university := domain.Universities{University: "Universitas Brawijaya"}
+	category := domain.Categories{Category: "Makanan", Kind: domain.CategoryKindProduct}
+	for _, record := range []any{&province, &university, &category} {
+		err = db.Create(record).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	user := domain.Users{Id: uuid.New(), Name: "Budi", Email: "budi@example.com"}
+	merchant := domain.Merchants{
+		Id:           uuid.New(),
+		UserId:       user.Id,
+		MerchantName: "Toko Budi",
+		UniversityId: university.Id,
+		ProvinceId:   province.Id,
+		IsActive:     true,
+	}
+	records := merchantRecords{user: user, merchant: merchant}
+	for _, name := range []string{"Keripik", "Sambal"} {
+		records.products = append(records.products, domain.Products{
+			Id:         uuid.New(),
+			MerchantId: merchant.Id,
+			CategoryId: category.Id,
+			Name:       name,
+		})
+	}
+
+	err = db.Omit(clause.Associations).Create(&records.user).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = db.Omit(clause.Associations).Create(&records.merchant).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, product := range records.products {
+		err = db.Omit(clause.Associations).Create(&product).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = db.Create(&domain.ProductPhotos{Id: uuid.New(), ProductId: product.Id, Url: "http://storage/" + product.Name + ".jpg"}).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return records
+}
+
+func countRows(t *testing.T, db *gorm.DB, table string, query string, args ...any) int64 {
+	t.Helper()
+
+	var total int64
+	err := db.Table(table).Where(query, args...).Count(&total).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return total
+}
+
+func TestDeleteUserRemovesPersonalData(t *testing.T) {
+	ctx := context.Background()
+	db := newTestMySQL(t)
+	records := createMerchantRecords(t, db)
+
+	// Another user likes one of the merchant's products, so deleting the
+	// user must also remove likes it does not own.
+	other := domain.Users{Id: uuid.New(), Name: "Siti", Email: "siti@example.com"}
+	mentor := domain.Mentors{Id: uuid.New(), Name: "Mentor"}
+	transaction := domain.Transactions{Id: uuid.New(), UserId: records.user.Id, MentorId: mentor.Id, Price: 50000, PayedAt: time.Now()}
+	for _, record := range []any{&other, &mentor, &transaction} {
+		err := db.Omit(clause.Associations).Create(record).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	for _, row := range []struct {
+		table  string
+		record map[string]any
+	}{
+		{"user_like_product", map[string]any{"user_id": records.user.Id, "product_id": records.products[0].Id}},
+		{"user_like_product", map[string]any{"user_id": other.Id, "product_id": records.products[1].Id}},
+		{"has_mentors", map[string]any{"user_id": records.user.Id, "mentor_id": mentor.Id}},
+		{"recovery_codes", map[string]any{"user_id": records.user.Id, "code": "hash"}},
+	} {
+		err := db.Table(row.table).Create(row.record).Error
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// A product in the trash is still personal data.
+	err := db.Delete(&records.products[1]).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users := repository.NewUserRepository(db, cache.MemoryInit(100))
+
+	var user domain.Users
+	err = users.GetUserData(ctx, &user, records.user.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if user.Merchant.Id != records.merchant.Id || len(user.Merchant.Products) != 2 {
+		t.Fatalf("expected the merchant with every product, got %+v", user.Merchant)
+	}
+	if len(user.LikeProduct) != 1 || len(user.HasMentors) != 1 || len(user.Transactions) != 1 {
+		t.Fatalf("expected likes, mentors and transactions, got %+v", user)
+	}
+
+	err = users.DeleteUser(ctx, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	productIds := []uuid.UUID{records.products[0].Id, records.products[1].Id}
+	for _, test := range []struct {
+		table string
+		query string
+		args  []any
+	}{
+		{"users", "id = ?", []any{records.user.Id}},
+		{"merchants", "id = ?", []any{records.merchant.Id}},
+		{"products", "merchant_id = ?", []any{records.merchant.Id}},
+		{"product_photos", "product_id IN ?", []any{productIds}},
+		{"user_like_product", "user_id = ? OR product_id IN ?", []any{records.user.Id, productIds}},
+		{"has_mentors", "user_id = ?", []any{records.user.Id}},
+		{"recovery_codes", "user_id = ?", []any{records.user.Id}},
+	} {
+		if total := countRows(t, db, test.table, test.query, test.args...); total != 0 {
+			t.Fatalf("expected no %v of the deleted user, got %v", test.table, total)
+		}
+	}
+
+	// Payments are kept for the mentor's books, without the user.
+	if total := countRows(t, db, "transactions", "id = ? AND user_id IS NULL", transaction.Id); total != 1 {
+		t.Fatal("expected the transaction to be kept without its user")
+	}
+	if total := countRows(t, db, "users", "id = ?", other.Id); total != 1 {
+		t.Fatal("expected the other user to be kept")
+	}
+	if total := countRows(t, db, "mentors", "id = ?", mentor.Id); total != 1 {
+		t.Fatal("expected the mentor to be kept")
+	}
+}
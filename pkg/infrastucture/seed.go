@@ -1,281 +1,382 @@
 package infrastucture
 
 import (
+	"bytes"
+	"context"
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
 	"intern-bcc/domain"
-	"log"
+	"intern-bcc/pkg/config"
+	"os"
+	"strings"
 
-	"github.com/bxcodec/faker/v4"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
-func SeedData(db *gorm.DB) {
-	var totalCategory int64
+const (
+	ProfileProduction  = "production"
+	ProfileDevelopment = "development"
+)
+
+const (
+	demoAdminEmail    = "Admin@gmail.com"
+	demoAdminPassword = "rahasiaadmin"
+)
+
+// provincesCSV lists every province. Regencies and districts come from the
+// official Kemendagri list that SeedConfig.RegionsFile points to.
+//
+//go:embed data/provinces.csv
+var provincesCSV []byte
+
+// provinceAliases maps province codes to names used before codes were seeded.
+var provinceAliases = map[string][]string{
+	"34": {"DIY Yogyakarta"},
+}
+
+var seedNamespace = uuid.MustParse("6f1c3c4e-8a3b-4d5e-9a87-1f0b7c2d9e10")
+
+type seeder struct {
+	name string
+	run  func(db *gorm.DB, cfg config.SeedConfig) error
+}
 
-	if err := db.Model(domain.Categories{}).Count(&totalCategory).Error; err != nil {
-		log.Fatal("error occured when counting category: ", err)
+var referenceSeeders = []seeder{
+	{name: "categories", run: seedCategories},
+	{name: "regions", run: seedRegions},
+	{name: "universities", run: seedUniversities},
+	{name: "admin", run: seedAdmin},
+}
+
+var demoSeeders = []seeder{
+	{name: "mentors", run: seedMentors},
+	{name: "information", run: seedInformation},
+}
+
+func Seed(ctx context.Context, db *gorm.DB, cfg config.SeedConfig) ([]string, error) {
+	var seeders []seeder
+	switch cfg.Profile {
+	case ProfileProduction:
+		seeders = referenceSeeders
+	case ProfileDevelopment:
+		seeders = append(append(seeders, referenceSeeders...), demoSeeders...)
+		if cfg.AdminEmail == "" {
+			cfg.AdminEmail = demoAdminEmail
+			cfg.AdminPassword = demoAdminPassword
+		}
+	default:
+		return nil, fmt.Errorf("unknown seed profile %q", cfg.Profile)
 	}
 
-	if totalCategory == 0 {
-		if err := generateCategory(db); err != nil {
-			log.Fatal("error occured when creating book: ", err)
+	var done []string
+	err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, s := range seeders {
+			err := s.run(tx, cfg)
+			if err != nil {
+				return fmt.Errorf("failed to seed %v: %w", s.name, err)
+			}
+			done = append(done, s.name)
 		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	var totalAdmin int64
-	if err := db.Model(domain.Users{}).Where("is_admin = ?", true).Count(&totalAdmin).Error; err != nil {
-		log.Fatal("error occured when counting admin: ", err)
+	return done, nil
+}
+
+func seedCategories(db *gorm.DB, cfg config.SeedConfig) error {
+	categories := []domain.Categories{
+		{Category: "Makanan & Minuman", Kind: domain.CategoryKindProduct},
+		{Category: "Kosmetik", Kind: domain.CategoryKindProduct},
+		{Category: "Fashion", Kind: domain.CategoryKindProduct},
+		{Category: "Aksesoris", Kind: domain.CategoryKindProduct},
+		{Category: "Kerajinan", Kind: domain.CategoryKindProduct},
+		{Category: "Jasa", Kind: domain.CategoryKindProduct},
+		{Category: "Artikel", Kind: domain.CategoryKindArticle},
+		{Category: "Webinar", Kind: domain.CategoryKindWebinar},
+		{Category: "Lomba", Kind: domain.CategoryKindCompetition},
 	}
 
-	if totalAdmin == 0 {
-		if err := generateAdmin(db); err != nil {
-			log.Fatal("error occured when creating admin: ", err)
+	for _, category := range categories {
+		err := db.Where(domain.Categories{Category: category.Category}).
+			Assign(domain.Categories{Kind: category.Kind}).
+			FirstOrCreate(&domain.Categories{}).Error
+		if err != nil {
+			return err
 		}
 	}
 
-	var totalMentor int64
-	if err := db.Model(domain.Mentors{}).Count(&totalMentor).Error; err != nil {
-		log.Fatal("error occured when counting mentor: ", err)
+	return nil
+}
+
+func seedRegions(db *gorm.DB, cfg config.SeedConfig) error {
+	records, err := readRegions(provincesCSV)
+	if err != nil {
+		return err
 	}
 
-	if totalMentor == 0 {
-		if err := generateMentor(db); err != nil {
-			log.Fatal("error occured when creating mentor: ", err)
+	if cfg.RegionsFile != "" {
+		data, err := os.ReadFile(cfg.RegionsFile)
+		if err != nil {
+			return err
 		}
-	}
 
-	var totalProvince int64
-	if err := db.Model(domain.Province{}).Count(&totalProvince).Error; err != nil {
-		log.Fatal("error occured when counting province: ", err)
-	}
+		fileRecords, err := readRegions(data)
+		if err != nil {
+			return fmt.Errorf("%v: %w", cfg.RegionsFile, err)
+		}
 
-	if totalProvince == 0 {
-		if err := generateProvince(db); err != nil {
-			log.Fatal("error occured when creating province: ", err)
+		// The bundled provinces are complete, so the file's own province rows
+		// are skipped and cannot rename them.
+		for _, record := range fileRecords {
+			if strings.Contains(record[0], ".") {
+				records = append(records, record)
+			}
 		}
 	}
 
-	var totalUniversity int64
-	if err := db.Model(domain.Universities{}).Count(&totalUniversity).Error; err != nil {
-		log.Fatal("error occured when counting university: ", err)
+	var provinces, regencies, districts [][]string
+	for _, record := range records {
+		switch len(strings.Split(record[0], ".")) {
+		case 1:
+			provinces = append(provinces, record)
+		case 2:
+			regencies = append(regencies, record)
+		case 3:
+			districts = append(districts, record)
+		case 4:
+			// Villages are part of the official list but not stored.
+		default:
+			return fmt.Errorf("invalid region code %q", record[0])
+		}
 	}
 
-	if totalUniversity == 0 {
-		if err := generateUniversity(db); err != nil {
-			log.Fatal("error occured when creating university: ", err)
+	provinceIds := make(map[string]int)
+	for _, record := range provinces {
+		code, name := record[0], record[1]
+
+		var province domain.Province
+		err := db.Where("code = ?", code).First(&province).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			names := append([]string{name}, provinceAliases[code]...)
+			err = db.Where("code IS NULL AND province IN (?)", names).First(&province).Error
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		province.Code = &code
+		province.Province = name
+		err = db.Save(&province).Error
+		if err != nil {
+			return err
 		}
-	}
 
-	var totalInformation int64
-	if err := db.Model(domain.Information{}).Count(&totalInformation).Error; err != nil {
-		log.Fatal("error occured when counting Information: ", err)
+		provinceIds[code] = province.Id
 	}
 
-	if totalInformation == 0 {
-		if err := generateInformation(db); err != nil {
-			log.Fatal("error occured when creating informatin: ", err)
+	regencyIds := make(map[string]int)
+	for _, record := range regencies {
+		code, name := record[0], record[1]
+
+		provinceId, ok := provinceIds[code[:2]]
+		if !ok {
+			return fmt.Errorf("regency %v has no province", code)
 		}
-	}
-}
 
-func generateInformation(db *gorm.DB) error {
-	var informations []*domain.Information
-	for i := 1; i < 15; i++ {
-		article := domain.Information{
-			Title:      faker.Name(),
-			CategoryId: 7,
-			Synopsis:   faker.Sentence(),
-			Content:    faker.Paragraph(),
+		var regency domain.Regencies
+		err := db.Where(domain.Regencies{Code: code}).
+			Assign(domain.Regencies{ProvinceId: provinceId, Name: name}).
+			FirstOrCreate(&regency).Error
+		if err != nil {
+			return err
 		}
-		informations = append(informations, &article)
 
-		webinar := domain.Information{
-			Title:      faker.Name(),
-			CategoryId: 8,
+		regencyIds[code] = regency.Id
+	}
+
+	for _, record := range districts {
+		code, name := record[0], record[1]
+
+		regencyId, ok := regencyIds[code[:5]]
+		if !ok {
+			return fmt.Errorf("district %v has no regency", code)
 		}
-		informations = append(informations, &webinar)
 
-		lomba := domain.Information{
-			Title:      faker.Name(),
-			CategoryId: 9,
+		err := db.Where(domain.Districts{Code: code}).
+			Assign(domain.Districts{RegencyId: regencyId, Name: name}).
+			FirstOrCreate(&domain.Districts{}).Error
+		if err != nil {
+			return err
 		}
-		informations = append(informations, &lomba)
 	}
 
-	if err := db.CreateInBatches(&informations, 45).Error; err != nil {
-		return err
+	return backfillMerchantRegencies(db)
+}
+
+// readRegions parses a `code,name` list, with or without a header row.
+func readRegions(data []byte) ([][]string, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = 2
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	if len(records) > 0 && (strings.EqualFold(records[0][0], "code") || strings.EqualFold(records[0][0], "kode")) {
+		records = records[1:]
+	}
+
+	return records, nil
 }
 
-func generateProvince(db *gorm.DB) error {
-	var provinces []*domain.Province
-
-	provinces = append(provinces,
-		&domain.Province{
-			Province: "Jawa Timur",
-		},
-		&domain.Province{
-			Province: "Jawa Tengah",
-		},
-		&domain.Province{
-			Province: "Jawa Barat",
-		},
-		&domain.Province{
-			Province: "DKI Jakarta",
-		},
-		&domain.Province{
-			Province: "DIY Yogyakarta",
-		},
-		&domain.Province{
-			Province: "Sumatera Utara",
-		},
-		&domain.Province{
-			Province: "Sumatera Barat",
-		},
-		&domain.Province{
-			Province: "Kalimantan Utara",
-		},
-		&domain.Province{
-			Province: "Kalimantan Barat",
-		},
-		&domain.Province{
-			Province: "Bali",
-		},
-	)
-
-	if err := db.CreateInBatches(&provinces, 10).Error; err != nil {
+// backfillMerchantRegencies links merchants created before regions existed,
+// preferring an exact name match and otherwise a single Kota/Kabupaten match.
+func backfillMerchantRegencies(db *gorm.DB) error {
+	err := db.Exec(`UPDATE merchants m
+		JOIN regencies r ON r.province_id = m.province_id AND r.name = m.city
+		SET m.regency_id = r.id
+		WHERE m.regency_id IS NULL`).Error
+	if err != nil {
 		return err
 	}
 
-	return nil
+	return db.Exec(`UPDATE merchants m
+		JOIN regencies r ON r.province_id = m.province_id
+			AND r.name IN (CONCAT('Kota ', m.city), CONCAT('Kabupaten ', m.city))
+		SET m.regency_id = r.id
+		WHERE m.regency_id IS NULL
+			AND (SELECT COUNT(*) FROM regencies c WHERE c.province_id = m.province_id
+				AND c.name IN (CONCAT('Kota ', m.city), CONCAT('Kabupaten ', m.city))) = 1`).Error
 }
 
-func generateUniversity(db *gorm.DB) error {
-	var universities []*domain.Universities
-
-	universities = append(universities,
-		&domain.Universities{
-			University: "Universitas Brawijaya",
-		},
-		&domain.Universities{
-			University: "Universitas Airlangga",
-		},
-		&domain.Universities{
-			University: "Universitas Negeri Semarang",
-		},
-		&domain.Universities{
-			University: "Universitas Negeri Surabaya",
-		},
-		&domain.Universities{
-			University: "Universitas Negeri Malang",
-		},
-		&domain.Universities{
-			University: "Institut Teknologi Surabaya",
-		},
-		&domain.Universities{
-			University: "Universitas Muhammadiyah Malang",
-		},
-		&domain.Universities{
-			University: "Universitas Jember",
-		},
-		&domain.Universities{
-			University: "Universitas Surabaya",
-		},
-		&domain.Universities{
-			University: "Universitas Kristen Petra",
-		},
-	)
-
-	if err := db.CreateInBatches(&universities, 10).Error; err != nil {
-		return err
+func seedUniversities(db *gorm.DB, cfg config.SeedConfig) error {
+	universities := []string{
+		"Universitas Brawijaya",
+		"Universitas Airlangga",
+		"Universitas Negeri Semarang",
+		"Universitas Negeri Surabaya",
+		"Universitas Negeri Malang",
+		"Institut Teknologi Surabaya",
+		"Universitas Muhammadiyah Malang",
+		"Universitas Jember",
+		"Universitas Surabaya",
+		"Universitas Kristen Petra",
+	}
+
+	for _, university := range universities {
+		err := db.Where(domain.Universities{University: university}).FirstOrCreate(&domain.Universities{}).Error
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func generateMentor(db *gorm.DB) error {
-	var mentors []*domain.Mentors
+func seedAdmin(db *gorm.DB, cfg config.SeedConfig) error {
+	if cfg.AdminEmail == "" {
+		return nil
+	}
 
-	for i := 1; i <= 5; i++ {
-		mentor := &domain.Mentors{
-			Id:          uuid.New(),
-			Name:        faker.Name(),
-			CurrentJob:  faker.Word(),
-			Description: faker.Sentence(),
-		}
+	adminPassword, err := bcrypt.GenerateFromPassword([]byte(cfg.AdminPassword), 10)
+	if err != nil {
+		return err
+	}
 
-		mentors = append(mentors, mentor)
+	return db.Where(domain.Users{Email: cfg.AdminEmail}).
+		Attrs(domain.Users{
+			Id:       seedId("user", cfg.AdminEmail),
+			Name:     cfg.AdminName,
+			Password: string(adminPassword),
+		}).
+		Assign(map[string]interface{}{"is_admin": true}).
+		FirstOrCreate(&domain.Users{}).Error
+}
+
+func seedMentors(db *gorm.DB, cfg config.SeedConfig) error {
+	mentors := []domain.Mentors{
+		{Name: "Rina Kusuma", CurrentJob: "Brand Strategist", Description: "Membantu UMKM membangun identitas merek yang konsisten.", Price: 150000},
+		{Name: "Dimas Pratama", CurrentJob: "Digital Marketer", Description: "Berpengalaman mengelola iklan media sosial untuk produk lokal.", Price: 125000},
+		{Name: "Sari Wulandari", CurrentJob: "Financial Planner", Description: "Mendampingi pengelolaan arus kas dan pembukuan usaha kecil.", Price: 175000},
+		{Name: "Bagus Santoso", CurrentJob: "Product Photographer", Description: "Mengajarkan teknik foto produk sederhana dengan ponsel.", Price: 100000},
+		{Name: "Ayu Lestari", CurrentJob: "E-commerce Consultant", Description: "Membantu merchant berjualan di marketplace dengan efektif.", Price: 200000},
 	}
 
-	err := db.CreateInBatches(&mentors, 5).Error
-	if err != nil {
-		log.Fatal("error occured when creating mentor: ", err)
+	for _, mentor := range mentors {
+		err := db.Unscoped().
+			Where(domain.Mentors{Name: mentor.Name}).
+			Attrs(domain.Mentors{Id: seedId("mentor", mentor.Name)}).
+			Assign(domain.Mentors{
+				CurrentJob:  mentor.CurrentJob,
+				Description: mentor.Description,
+				Price:       mentor.Price,
+			}).
+			FirstOrCreate(&domain.Mentors{}).Error
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func generateAdmin(db *gorm.DB) error {
-	adminPassword, err := bcrypt.GenerateFromPassword([]byte("rahasiaadmin"), 10)
-	if err != nil {
-		log.Fatal("error occured when hashing admin password: ", err)
+func seedInformation(db *gorm.DB, cfg config.SeedConfig) error {
+	categoryIds := make(map[string]int)
+	for _, kind := range []string{domain.CategoryKindArticle, domain.CategoryKindWebinar, domain.CategoryKindCompetition} {
+		var category domain.Categories
+		err := db.Where("kind = ? AND parent_id IS NULL", kind).Order("id").First(&category).Error
+		if err != nil {
+			return fmt.Errorf("no %v category: %w", kind, err)
+		}
+		categoryIds[kind] = category.Id
 	}
 
-	admin := domain.Users{
-		Id:       uuid.New(),
-		Name:     "Admin",
-		Email:    "Admin@gmail.com",
-		Password: string(adminPassword),
-		IsAdmin:  true,
+	var informations []domain.Information
+	for i := 1; i <= 5; i++ {
+		informations = append(informations,
+			domain.Information{
+				Title:      fmt.Sprintf("Artikel Demo %d", i),
+				CategoryId: categoryIds[domain.CategoryKindArticle],
+				Synopsis:   fmt.Sprintf("Ringkasan artikel demo nomor %d.", i),
+				Content:    fmt.Sprintf("Isi artikel demo nomor %d untuk lingkungan pengembangan.", i),
+			},
+			domain.Information{
+				Title:      fmt.Sprintf("Webinar Demo %d", i),
+				CategoryId: categoryIds[domain.CategoryKindWebinar],
+			},
+			domain.Information{
+				Title:      fmt.Sprintf("Lomba Demo %d", i),
+				CategoryId: categoryIds[domain.CategoryKindCompetition],
+			},
+		)
 	}
 
-	err = db.Model(domain.Users{}).Create(&admin).Error
-	if err != nil {
-		log.Fatal("error occured when creating admin: ", err)
+	for _, information := range informations {
+		err := db.Unscoped().
+			Where(domain.Information{Title: information.Title, CategoryId: information.CategoryId}).
+			Assign(domain.Information{
+				Synopsis: information.Synopsis,
+				Content:  information.Content,
+			}).
+			FirstOrCreate(&domain.Information{}).Error
+		if err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func generateCategory(db *gorm.DB) error {
-	var categories []*domain.Categories
-
-	categories = append(categories,
-		&domain.Categories{
-			Category: "Makanan & Minuman",
-		},
-		&domain.Categories{
-			Category: "Kosmetik",
-		},
-		&domain.Categories{
-			Category: "Fashion",
-		},
-		&domain.Categories{
-			Category: "Aksesoris",
-		},
-		&domain.Categories{
-			Category: "Kerajinan",
-		},
-		&domain.Categories{
-			Category: "Jasa",
-		},
-		&domain.Categories{
-			Category: "Artikel",
-		},
-		&domain.Categories{
-			Category: "Webinar",
-		},
-		&domain.Categories{
-			Category: "Lomba",
-		},
-	)
-
-	if err := db.CreateInBatches(&categories, 9).Error; err != nil {
-		return err
-	}
-
-	return nil
+func seedId(kind string, naturalKey string) uuid.UUID {
+	return uuid.NewSHA1(seedNamespace, []byte(kind+":"+naturalKey))
 }
@@ -0,0 +1,199 @@
+package infrastucture
+
+import (
+	"context"
+	"intern-bcc/domain"
+	"intern-bcc/pkg/config"
+	"intern-bcc/pkg/infrastucture/database"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/testcontainers/testcontainers-go"
+	tcmysql "github.com/testcontainers/testcontainers-go/modules/mysql"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newTestMySQL(t *testing.T) *gorm.DB {
+	t.Helper()
+	testcontainers.SkipIfProviderIsNotHealthy(t)
+
+	ctx := context.Background()
+	container, err := tcmysql.RunContainer(ctx,
+		testcontainers.WithImage("mysql:8.0.36"),
+		tcmysql.WithDatabase("intern_bcc"),
+		tcmysql.WithUsername("test"),
+		tcmysql.WithPassword("test"),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		_ = container.Terminate(context.Background())
+	})
+
+	dsn, err := container.ConnectionString(ctx, "charset=utf8mb4", "parseTime=True")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func TestSeedTwiceCreatesNoDuplicates(t *testing.T) {
+	ctx := context.Background()
+	db := newTestMySQL(t)
+
+	migrator, err := database.MigratorInit(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = migrator.Up(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A province seeded before codes existed is renamed, not duplicated.
+	err = db.Create(&domain.Province{Province: "DIY Yogyakarta"}).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	regionsFile := filepath.Join(t.TempDir(), "regions.csv")
+	err = os.WriteFile(regionsFile, []byte("kode,nama\n"+
+		"35,JAWA TIMUR\n"+
+		"35.73,Kota Malang\n"+
+		"35.73.01,Kedungkandang\n"+
+		"35.73.01.1001,Arjowinangun\n"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := config.SeedConfig{Profile: ProfileDevelopment, AdminName: "Admin", RegionsFile: regionsFile}
+
+	tables := []string{"categories", "provinces", "regencies", "districts", "universities", "users", "mentors", "information"}
+	count := func() []int64 {
+		t.Helper()
+
+		var totals []int64
+		for _, table := range tables {
+			var total int64
+			err := db.Table(table).Count(&total).Error
+			if err != nil {
+				t.Fatal(err)
+			}
+			totals = append(totals, total)
+		}
+
+		return totals
+	}
+
+	done, err := Seed(ctx, db, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(done, []string{"categories", "regions", "universities", "admin", "mentors", "information"}) {
+		t.Fatalf("expected every development seeder to run, got %v", done)
+	}
+	first := count()
+	for i, total := range first {
+		if total == 0 {
+			t.Fatalf("expected %v to be seeded", tables[i])
+		}
+	}
+
+	_, err = Seed(ctx, db, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if second := count(); !slices.Equal(first, second) {
+		t.Fatalf("expected a second seed to add nothing to %v, got %v then %v", tables, first, second)
+	}
+
+	var provinces []domain.Province
+	err = db.Where("code IN ?", []string{"34", "35"}).Order("code").Find(&provinces).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(provinces) != 2 || provinces[0].Province != "DI Yogyakarta" || provinces[1].Province != "Jawa Timur" {
+		t.Fatalf("expected the bundled province names, got %+v", provinces)
+	}
+
+	bundled, err := readRegions(provincesCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first[1] != int64(len(bundled)) {
+		t.Fatalf("expected %v provinces, got %v", len(bundled), first[1])
+	}
+
+	var admins int64
+	err = db.Model(&domain.Users{}).Where("is_admin = ?", true).Count(&admins).Error
+	if err != nil {
+		t.Fatal(err)
+	}
+	if admins != 1 {
+		t.Fatalf("expected one admin, got %v", admins)
+	}
+}
+
+func TestSeedRejectsUnknownProfile(t *testing.T) {
+	_, err := Seed(context.Background(), nil, config.SeedConfig{Profile: "staging"})
+	if err == nil {
+		t.Fatal("expected an unknown profile to be rejected")
+	}
+}
+
+func TestReadRegions(t *testing.T) {
+	for _, test := range []struct {
+		name string
+		data string
+		want [][]string
+	}{
+		{"header", "code,name\n35,Jawa Timur\n", [][]string{{"35", "Jawa Timur"}}},
+		{"kemendagri header", "kode, nama\n35.73, Kota Malang\n", [][]string{{"35.73", "Kota Malang"}}},
+		{"no header", "35.73.01,Kedungkandang\n", [][]string{{"35.73.01", "Kedungkandang"}}},
+		{"quoted name", "35.07,\"Malang, Kabupaten\"\n", [][]string{{"35.07", "Malang, Kabupaten"}}},
+		{"empty", "", nil},
+	} {
+		t.Run(test.name, func(t *testing.T) {
+			records, err := readRegions([]byte(test.data))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.EqualFunc(records, test.want, slices.Equal[[]string]) {
+				t.Fatalf("expected %v, got %v", test.want, records)
+			}
+		})
+	}
+
+	_, err := readRegions([]byte("35,Jawa Timur,extra\n"))
+	if err == nil {
+		t.Fatal("expected a row with the wrong number of fields to be rejected")
+	}
+}
+
+func TestBundledProvincesHaveCodes(t *testing.T) {
+	records, err := readRegions(provincesCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) < 34 {
+		t.Fatalf("expected every province, got %v", len(records))
+	}
+
+	seen := make(map[string]bool)
+	for _, record := range records {
+		if len(record[0]) != 2 || seen[record[0]] {
+			t.Fatalf("expected unique two digit province codes, got %q", record[0])
+		}
+		seen[record[0]] = true
+	}
+}
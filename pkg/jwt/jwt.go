@@ -2,11 +2,8 @@ package jwt
 
 import (
 	"errors"
-	"fmt"
 	"intern-bcc/domain"
-	"log"
-	"os"
-	"strconv"
+	"intern-bcc/pkg/config"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -30,36 +27,23 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
-func JwtInit() IJwt {
-	secretKey := os.Getenv("SECRET_KEY")
-	expiredTime, err := strconv.Atoi(os.Getenv("JWT_EXP_TIME"))
-	if err != nil {
-		log.Fatal("failed to set jwt expired time")
-	}
-
+func JwtInit(cfg config.JwtConfig) IJwt {
 	return &jsonWebToken{
-		SecretKey:   secretKey,
-		ExpiredTime: time.Duration(expiredTime) * time.Hour,
+		SecretKey:   cfg.SecretKey,
+		ExpiredTime: time.Duration(cfg.ExpiredTime) * time.Hour,
 	}
 }
 
 func (j *jsonWebToken) GenerateToken(userId uuid.UUID) (string, error) {
-	expiredTime, err := strconv.Atoi(os.Getenv("JWT_EXP_TIME"))
-	secretKey := os.Getenv("SECRET_KEY")
-	fmt.Println(expiredTime, "\n\n\n", secretKey)
-	if err != nil {
-		log.Fatal("failed to set jwt expired time")
-	}
-
 	claim := &Claims{
 		UserId: userId,
 		RegisteredClaims: jwt.RegisteredClaims{
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Duration(expiredTime) * time.Hour)),
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(j.ExpiredTime)),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claim)
-	tokenString, err := token.SignedString([]byte(secretKey))
+	tokenString, err := token.SignedString([]byte(j.SecretKey))
 	if err != nil {
 		return tokenString, err
 	}
@@ -71,7 +55,7 @@ func (j *jsonWebToken) ValidateToken(tokenString string) (uuid.UUID, error) {
 	var userId uuid.UUID
 	var claims Claims
 	token, err := jwt.ParseWithClaims(tokenString, &claims, func(token *jwt.Token) (interface{}, error) {
-		return []byte(os.Getenv("SECRET_KEY")), nil
+		return []byte(j.SecretKey), nil
 	})
 
 	if err != nil {
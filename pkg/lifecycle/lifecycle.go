@@ -0,0 +1,135 @@
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"intern-bcc/pkg/logging"
+	"sync"
+	"time"
+)
+
+type Hook struct {
+	Name    string
+	OnStart func(ctx context.Context) error
+	OnStop  func(ctx context.Context) error
+}
+
+type ILifecycle interface {
+	Append(hook Hook)
+	Worker(name string, run func(ctx context.Context) error)
+	Fail(err error)
+	Run(ctx context.Context) error
+}
+
+type Lifecycle struct {
+	mu              sync.Mutex
+	hooks           []Hook
+	shutdownTimeout time.Duration
+	errs            chan error
+}
+
+func LifecycleInit(shutdownTimeout time.Duration) ILifecycle {
+	return &Lifecycle{
+		shutdownTimeout: shutdownTimeout,
+		errs:            make(chan error, 1),
+	}
+}
+
+func (l *Lifecycle) Append(hook Hook) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.hooks = append(l.hooks, hook)
+}
+
+func (l *Lifecycle) Worker(name string, run func(ctx context.Context) error) {
+	var cancel context.CancelFunc
+	done := make(chan struct{})
+
+	l.Append(Hook{
+		Name: name,
+		OnStart: func(ctx context.Context) error {
+			var workerCtx context.Context
+			workerCtx, cancel = context.WithCancel(context.Background())
+
+			go func() {
+				defer close(done)
+
+				err := run(workerCtx)
+				if err != nil && !errors.Is(err, context.Canceled) {
+					l.Fail(fmt.Errorf("%v stopped: %w", name, err))
+				}
+			}()
+
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			cancel()
+
+			select {
+			case <-done:
+				return nil
+			case <-ctx.Done():
+				return fmt.Errorf("%v did not stop in time: %w", name, ctx.Err())
+			}
+		},
+	})
+}
+
+func (l *Lifecycle) Fail(err error) {
+	select {
+	case l.errs <- err:
+	default:
+	}
+}
+
+func (l *Lifecycle) Run(ctx context.Context) error {
+	l.mu.Lock()
+	hooks := append([]Hook(nil), l.hooks...)
+	l.mu.Unlock()
+
+	var runErr error
+	started := 0
+	for _, hook := range hooks {
+		if hook.OnStart != nil {
+			err := hook.OnStart(ctx)
+			if err != nil {
+				runErr = fmt.Errorf("failed to start %v: %w", hook.Name, err)
+				break
+			}
+		}
+
+		logging.FromContext(ctx).WithField("hook", hook.Name).Info("started")
+		started++
+	}
+
+	if runErr == nil {
+		select {
+		case <-ctx.Done():
+			logging.FromContext(ctx).Info("shutdown signal received")
+		case runErr = <-l.errs:
+		}
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), l.shutdownTimeout)
+	defer cancel()
+
+	var stopErrs []error
+	for i := started - 1; i >= 0; i-- {
+		hook := hooks[i]
+		if hook.OnStop == nil {
+			continue
+		}
+
+		err := hook.OnStop(stopCtx)
+		if err != nil {
+			stopErrs = append(stopErrs, fmt.Errorf("failed to stop %v: %w", hook.Name, err))
+			continue
+		}
+
+		logging.FromContext(stopCtx).WithField("hook", hook.Name).Info("stopped")
+	}
+
+	return errors.Join(append([]error{runErr}, stopErrs...)...)
+}
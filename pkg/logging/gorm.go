@@ -0,0 +1,72 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+const slowQueryThreshold = 200 * time.Millisecond
+
+type gormLogger struct {
+	level logger.LogLevel
+}
+
+// GormLogger logs failed and slow queries with the fields of the request that
+// ran them.
+func GormLogger() logger.Interface {
+	return &gormLogger{level: logger.Warn}
+}
+
+func (l *gormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &gormLogger{level: level}
+}
+
+func (l *gormLogger) Info(ctx context.Context, message string, args ...interface{}) {
+	if l.level >= logger.Info {
+		FromContext(ctx).Infof(message, args...)
+	}
+}
+
+func (l *gormLogger) Warn(ctx context.Context, message string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		FromContext(ctx).Warnf(message, args...)
+	}
+}
+
+func (l *gormLogger) Error(ctx context.Context, message string, args ...interface{}) {
+	if l.level >= logger.Error {
+		FromContext(ctx).Errorf(message, args...)
+	}
+}
+
+func (l *gormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= logger.Error:
+		sql, rows := fc()
+		FromContext(ctx).WithError(err).WithFields(queryFields(sql, rows, elapsed)).Error("query failed")
+	case elapsed > slowQueryThreshold && l.level >= logger.Warn:
+		sql, rows := fc()
+		FromContext(ctx).WithFields(queryFields(sql, rows, elapsed)).Warn("slow query")
+	case l.level >= logger.Info:
+		sql, rows := fc()
+		FromContext(ctx).WithFields(queryFields(sql, rows, elapsed)).Debug("query")
+	}
+}
+
+func queryFields(sql string, rows int64, elapsed time.Duration) logrus.Fields {
+	return logrus.Fields{
+		"sql":      sql,
+		"rows":     rows,
+		"duration": elapsed.Seconds(),
+	}
+}
@@ -1,16 +1,26 @@
 package logging
 
 import (
+	"context"
+	"sync/atomic"
+
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 )
 
+type contextKey struct{}
+
+// defaultLogger is the logger of the last LoggingInit, which FromContext falls
+// back to outside a request.
+var defaultLogger atomic.Pointer[logrus.Logger]
+
 type ILogging interface {
 	Info(c *gin.Context, statusCode int, executionTime float64)
 	Error(c *gin.Context, statusCode int, executionTime float64, err error)
 	InfoLn(message string)
 	ErrorLn(err error)
 	WarnLn(err error)
+	WithFields(ctx context.Context, fields map[string]interface{}) context.Context
 }
 
 type Logging struct {
@@ -24,12 +34,13 @@ func LoggingInit() ILogging {
 	logger.SetFormatter(customFormatter)
 	logger.SetReportCaller(true)
 	logger.SetLevel(logrus.DebugLevel)
+	defaultLogger.Store(logger)
 
 	return &Logging{logrus: logger}
 }
 
 func (l *Logging) Info(c *gin.Context, statusCode int, executionTime float64) {
-	l.logrus.WithFields(logrus.Fields{
+	l.entry(c.Request.Context()).WithFields(logrus.Fields{
 		"success":     true,
 		"status_code": statusCode,
 		"duration":    executionTime,
@@ -37,7 +48,7 @@ func (l *Logging) Info(c *gin.Context, statusCode int, executionTime float64) {
 }
 
 func (l *Logging) Error(c *gin.Context, statusCode int, executionTime float64, err error) {
-	l.logrus.WithFields(logrus.Fields{
+	l.entry(c.Request.Context()).WithFields(logrus.Fields{
 		"success":     false,
 		"status_code": statusCode,
 		"duration":    executionTime,
@@ -56,3 +67,28 @@ func (l *Logging) InfoLn(message string) {
 func(l *Logging) WarnLn(err error) {
 	l.logrus.Warningln(err)
 }
+
+func (l *Logging) WithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	return context.WithValue(ctx, contextKey{}, l.entry(ctx).WithFields(fields))
+}
+
+func (l *Logging) entry(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+
+	return logrus.NewEntry(l.logrus)
+}
+
+func FromContext(ctx context.Context) *logrus.Entry {
+	if entry, ok := ctx.Value(contextKey{}).(*logrus.Entry); ok {
+		return entry
+	}
+
+	logger := defaultLogger.Load()
+	if logger == nil {
+		logger = logrus.StandardLogger()
+	}
+
+	return logrus.NewEntry(logger)
+}
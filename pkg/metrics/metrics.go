@@ -0,0 +1,29 @@
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	HttpRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Latency of HTTP requests by route.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	CacheRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "cache_requests_total",
+		Help: "Cache lookups by cache name and result (hit, miss, stale).",
+	}, []string{"cache", "result"})
+
+	PaymentOutcomes = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "payment_outcomes_total",
+		Help: "Payment gateway calls by operation and outcome.",
+	}, []string{"operation", "outcome"})
+
+	EmailSendFailures = promauto.NewCounter(prometheus.CounterOpts{
+		Name: "email_send_failures_total",
+		Help: "Emails that could not be sent.",
+	})
+)
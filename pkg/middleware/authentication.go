@@ -18,7 +18,14 @@ func (m *Middleware) Authentication(c *gin.Context) {
 		return
 	}
 
-	tokenString := strings.Split(bearer, " ")[1]
+	bearerParts := strings.Split(bearer, " ")
+	if len(bearerParts) != 2 {
+		response.Failed(c, response.NewError(http.StatusUnauthorized, "token is invalid", errors.New("wrong bearer format")))
+		c.Abort()
+		return
+	}
+
+	tokenString := bearerParts[1]
 	userId, err := m.jwtAuth.ValidateToken(tokenString)
 	if err != nil {
 		response.Failed(c, response.NewError(http.StatusUnauthorized, "failed to validate token", err))
@@ -26,11 +33,16 @@ func (m *Middleware) Authentication(c *gin.Context) {
 		return
 	}
 
-	user, err := m.usecase.UserUsecase.GetUser(domain.UserParam{Id: userId})
+	user, err := m.usecase.UserUsecase.GetUser(c.Request.Context(), domain.UserParam{Id: userId})
 	if err != nil {
 		response.Failed(c, err)
+		c.Abort()
+		return
 	}
 
 	c.Set("user", user)
+	c.Request = c.Request.WithContext(m.logging.WithFields(c.Request.Context(), map[string]interface{}{
+		"user_id": user.Id,
+	}))
 	c.Next()
 }
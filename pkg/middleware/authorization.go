@@ -13,9 +13,17 @@ func (m *Middleware) OnlyAdmin(c *gin.Context) {
 	if err != nil {
 		response.Failed(c, response.NewError(http.StatusNotFound, "failed to get account", err))
 		c.Abort()
+		return
 	}
 	if !user.IsAdmin {
 		response.Failed(c, response.NewError(http.StatusUnauthorized, "access denied", errors.New("only admin")))
+		c.Abort()
+		return
+	}
+	if !user.TwoFactor {
+		response.Failed(c, response.NewError(http.StatusForbidden, "please enable two factor authentication", errors.New("admin account must use two factor")))
+		c.Abort()
+		return
 	}
 
 	c.Next()
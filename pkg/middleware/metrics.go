@@ -0,0 +1,24 @@
+package middleware
+
+import (
+	"intern-bcc/pkg/metrics"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (m *Middleware) Metrics(c *gin.Context) {
+	timeStart := time.Now()
+
+	c.Next()
+
+	route := c.FullPath()
+	if route == "" {
+		route = "unmatched"
+	}
+
+	metrics.HttpRequestDuration.
+		WithLabelValues(c.Request.Method, route, strconv.Itoa(c.Writer.Status())).
+		Observe(time.Since(timeStart).Seconds())
+}
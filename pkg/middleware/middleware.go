@@ -2,8 +2,10 @@ package middleware
 
 import (
 	"intern-bcc/internal/usecase"
+	"intern-bcc/pkg/cache"
 	"intern-bcc/pkg/jwt"
 	"intern-bcc/pkg/logging"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -12,18 +14,25 @@ type IMiddleware interface {
 	Authentication(c *gin.Context)
 	OnlyAdmin(c *gin.Context)
 	LogEvent(c *gin.Context)
+	RequestId(c *gin.Context)
+	Recovery(c *gin.Context)
+	Metrics(c *gin.Context)
+	RateLimitByIP(bucket string, limit int64, window time.Duration) gin.HandlerFunc
+	RateLimitByAccount(bucket string, limit int64, window time.Duration) gin.HandlerFunc
 }
 
 type Middleware struct {
 	jwtAuth jwt.IJwt
 	usecase *usecase.Usecase
 	logging logging.ILogging
+	cache   cache.ICache
 }
 
-func MiddlerwareInit(jwtAuth jwt.IJwt, usecase *usecase.Usecase, logging logging.ILogging) IMiddleware {
+func MiddlerwareInit(jwtAuth jwt.IJwt, usecase *usecase.Usecase, logging logging.ILogging, cache cache.ICache) IMiddleware {
 	return &Middleware{
 		jwtAuth: jwtAuth,
 		usecase: usecase,
 		logging: logging,
+		cache:   cache,
 	}
 }
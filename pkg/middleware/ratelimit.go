@@ -0,0 +1,97 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"intern-bcc/domain"
+	"intern-bcc/pkg/logging"
+	"intern-bcc/pkg/response"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	keySetRateLimit    = "ratelimit:%v:%v:%v"
+	maxAccountBodySize = 64 << 10
+)
+
+var errBodyTooLarge = errors.New("request body is too large")
+
+func (m *Middleware) RateLimitByIP(bucket string, limit int64, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		m.rateLimit(c, fmt.Sprintf(keySetRateLimit, bucket, "ip", c.ClientIP()), limit, window)
+	}
+}
+
+func (m *Middleware) RateLimitByAccount(bucket string, limit int64, window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		account, err := accountIdentifier(c)
+		if errors.Is(err, errBodyTooLarge) {
+			response.Failed(c, response.NewError(http.StatusRequestEntityTooLarge, "request body is too large", err))
+			c.Abort()
+			return
+		}
+		if account == "" {
+			c.Next()
+			return
+		}
+
+		m.rateLimit(c, fmt.Sprintf(keySetRateLimit, bucket, "account", account), limit, window)
+	}
+}
+
+func (m *Middleware) rateLimit(c *gin.Context, key string, limit int64, window time.Duration) {
+	count, err := m.cache.Incr(c.Request.Context(), key, window)
+	if err != nil {
+		logging.FromContext(c.Request.Context()).WithError(err).Warn("rate limiter unavailable")
+		c.Next()
+		return
+	}
+
+	if count > limit {
+		c.Header("Retry-After", strconv.Itoa(int(window.Seconds())))
+		response.Failed(c, response.NewError(http.StatusTooManyRequests, "too many requests, please try again later", errors.New("rate limit exceeded")))
+		c.Abort()
+		return
+	}
+
+	c.Next()
+}
+
+// accountIdentifier reads the email from the body before any handler has bound
+// it, so the body is capped at maxAccountBodySize and put back for the handler.
+func accountIdentifier(c *gin.Context) (string, error) {
+	if user, ok := c.Get("user"); ok {
+		return user.(domain.Users).Id.String(), nil
+	}
+
+	if c.Request.Body == nil {
+		return "", nil
+	}
+
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxAccountBodySize+1))
+	if err != nil {
+		return "", nil
+	}
+	if len(body) > maxAccountBodySize {
+		return "", errBodyTooLarge
+	}
+	c.Request.Body = io.NopCloser(bytes.NewBuffer(body))
+
+	var account struct {
+		Email string `json:"email"`
+	}
+	err = json.Unmarshal(body, &account)
+	if err != nil {
+		return "", nil
+	}
+
+	return strings.ToLower(strings.TrimSpace(account.Email)), nil
+}
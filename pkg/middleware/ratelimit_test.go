@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"intern-bcc/pkg/cache"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newRateLimitedEngine(t *testing.T, trustedProxies []string, handlers ...gin.HandlerFunc) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	engine := gin.New()
+	err := engine.SetTrustedProxies(trustedProxies)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	handlers = append(handlers, func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	engine.POST("/login", handlers...)
+
+	return engine
+}
+
+func post(engine *gin.Engine, remoteAddr string, forwardedFor string, body string) int {
+	req := httptest.NewRequest(http.MethodPost, "/login", strings.NewReader(body))
+	req.RemoteAddr = remoteAddr
+	if forwardedFor != "" {
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+	}
+
+	res := httptest.NewRecorder()
+	engine.ServeHTTP(res, req)
+
+	return res.Code
+}
+
+func TestRateLimitByIPIgnoresSpoofedForwardedFor(t *testing.T) {
+	m := &Middleware{cache: cache.MemoryInit(100)}
+	engine := newRateLimitedEngine(t, nil, m.RateLimitByIP("login", 2, time.Minute))
+
+	for i, forwardedFor := range []string{"10.0.0.1", "10.0.0.2"} {
+		if code := post(engine, "203.0.113.7:4000", forwardedFor, ""); code != http.StatusOK {
+			t.Fatalf("request %d: expected to be allowed, got %v", i, code)
+		}
+	}
+
+	if code := post(engine, "203.0.113.7:4000", "10.0.0.3", ""); code != http.StatusTooManyRequests {
+		t.Fatalf("expected a new X-Forwarded-For to share the limit of its address, got %v", code)
+	}
+}
+
+func TestRateLimitByIPUsesForwardedForFromTrustedProxy(t *testing.T) {
+	m := &Middleware{cache: cache.MemoryInit(100)}
+	engine := newRateLimitedEngine(t, []string{"192.0.2.10"}, m.RateLimitByIP("login", 1, time.Minute))
+
+	for _, client := range []string{"10.0.0.1", "10.0.0.2"} {
+		if code := post(engine, "192.0.2.10:4000", client, ""); code != http.StatusOK {
+			t.Fatalf("expected %v behind the proxy to have its own limit, got %v", client, code)
+		}
+	}
+
+	if code := post(engine, "192.0.2.10:4000", "10.0.0.1", ""); code != http.StatusTooManyRequests {
+		t.Fatalf("expected a repeated client behind the proxy to be limited, got %v", code)
+	}
+}
+
+func TestRateLimitByAccountRejectsOversizedBody(t *testing.T) {
+	m := &Middleware{cache: cache.MemoryInit(100)}
+	engine := newRateLimitedEngine(t, nil, m.RateLimitByAccount("login", 10, time.Minute))
+
+	body := `{"email":"a@example.com","padding":"` + strings.Repeat("x", maxAccountBodySize) + `"}`
+	if code := post(engine, "203.0.113.7:4000", "", body); code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected an oversized body to be rejected, got %v", code)
+	}
+
+	if code := post(engine, "203.0.113.7:4000", "", `{"email":"a@example.com"}`); code != http.StatusOK {
+		t.Fatalf("expected a normal body to pass, got %v", code)
+	}
+}
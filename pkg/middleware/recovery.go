@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"intern-bcc/pkg/logging"
+	"intern-bcc/pkg/response"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Recovery turns a panic into a 500 and logs it with the request fields, in
+// place of gin's recovery which writes plain text to stderr.
+func (m *Middleware) Recovery(c *gin.Context) {
+	defer func() {
+		recovered := recover()
+		if recovered == nil {
+			return
+		}
+
+		logging.FromContext(c.Request.Context()).
+			WithField("panic", fmt.Sprint(recovered)).
+			WithField("stack", string(debug.Stack())).
+			Error("request panicked")
+
+		response.Failed(c, response.NewError(http.StatusInternalServerError, "internal server error", errors.New("unexpected panic")))
+		c.Abort()
+	}()
+
+	c.Next()
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const headerRequestId = "X-Request-ID"
+
+var validRequestId = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+func (m *Middleware) RequestId(c *gin.Context) {
+	requestId := c.GetHeader(headerRequestId)
+	if !validRequestId.MatchString(requestId) {
+		requestId = uuid.New().String()
+	}
+
+	c.Set("request_id", requestId)
+	c.Header(headerRequestId, requestId)
+
+	route := c.FullPath()
+	if route == "" {
+		route = c.Request.URL.Path
+	}
+
+	fields := map[string]interface{}{
+		"request_id": requestId,
+		"method":     c.Request.Method,
+		"route":      route,
+		"client_ip":  c.ClientIP(),
+	}
+
+	spanContext := trace.SpanContextFromContext(c.Request.Context())
+	if spanContext.IsValid() {
+		fields["trace_id"] = spanContext.TraceID().String()
+	}
+
+	ctx := m.logging.WithFields(c.Request.Context(), fields)
+	c.Request = c.Request.WithContext(ctx)
+
+	c.Next()
+}
@@ -1,32 +1,38 @@
 package midtrans
 
 import (
+	"context"
 	"errors"
 	"intern-bcc/domain"
-	"os"
+	"intern-bcc/pkg/config"
+	"intern-bcc/pkg/metrics"
+	"intern-bcc/pkg/tracing"
 
 	"github.com/midtrans/midtrans-go"
 	"github.com/midtrans/midtrans-go/coreapi"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 type IMidTrans interface {
-	ChargeTransaction(newTransaction domain.Transactions) (*coreapi.ChargeResponse, error)
-	VerifyPayment(transctionIdString string) (bool, error)
+	ChargeTransaction(ctx context.Context, newTransaction domain.Transactions) (*coreapi.ChargeResponse, error)
+	VerifyPayment(ctx context.Context, transctionIdString string) (bool, error)
 }
 
 type MidTrans struct {
 	serverKey string
 }
 
-func MidTransInit() IMidTrans {
-	serverKey := os.Getenv("MIDTRANS_SERVER_KEY")
-
+func MidTransInit(cfg config.MidtransConfig) IMidTrans {
 	return &MidTrans{
-		serverKey: serverKey,
+		serverKey: cfg.ServerKey,
 	}
 }
 
-func (m *MidTrans) ChargeTransaction(newTransaction domain.Transactions) (*coreapi.ChargeResponse, error) {
+func (m *MidTrans) ChargeTransaction(ctx context.Context, newTransaction domain.Transactions) (*coreapi.ChargeResponse, error) {
+	_, span := tracing.StartClient(ctx, "midtrans.charge", attribute.String("payment.type", newTransaction.PaymentType))
+	defer span.End()
+
 	c := coreapi.Client{}
 	c.New(m.serverKey, midtrans.Sandbox)
 
@@ -65,20 +71,34 @@ func (m *MidTrans) ChargeTransaction(newTransaction domain.Transactions) (*corea
 
 	coreApiRes, err := c.ChargeTransaction(chargeReq)
 	if err != nil {
+		metrics.PaymentOutcomes.WithLabelValues("charge", "error").Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return coreApiRes, err
 	}
+
+	metrics.PaymentOutcomes.WithLabelValues("charge", "created").Inc()
 	return coreApiRes, nil
 }
 
-func (m *MidTrans) VerifyPayment(transctionIdString string) (bool, error) {
+func (m *MidTrans) VerifyPayment(ctx context.Context, transctionIdString string) (bool, error) {
+	_, span := tracing.StartClient(ctx, "midtrans.verify")
+	defer span.End()
+
 	c := coreapi.Client{}
 	c.New(m.serverKey, midtrans.Sandbox)
 
 	transactionStatusRespone, err := c.CheckTransaction(transctionIdString)
 	if err != nil {
+		metrics.PaymentOutcomes.WithLabelValues("verify", "error").Inc()
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
 		return false, err
 	}
 
+	metrics.PaymentOutcomes.WithLabelValues("verify", transactionStatusRespone.TransactionStatus).Inc()
+	span.SetAttributes(attribute.String("payment.status", transactionStatusRespone.TransactionStatus))
+
 	switch transactionStatusRespone.TransactionStatus {
 	case "settlement":
 		return true, nil
@@ -0,0 +1,45 @@
+package redis
+
+import (
+	"testing"
+	"time"
+)
+
+func TestHalfOpenBreakerAllowsSingleProbe(t *testing.T) {
+	b := newBreaker(2, 10*time.Millisecond)
+	b.failure()
+	b.failure()
+
+	if b.allow() {
+		t.Fatal("expected an open breaker to fail fast")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if !b.allow() {
+		t.Fatal("expected a half-open breaker to let a probe through")
+	}
+	if b.allow() {
+		t.Fatal("expected a half-open breaker to hold calls while its probe runs")
+	}
+
+	b.failure()
+	if b.allow() {
+		t.Fatal("expected a failed probe to open the breaker again")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if !b.allow() {
+		t.Fatal("expected a new probe after the cooldown")
+	}
+	b.abort()
+	if !b.allow() {
+		t.Fatal("expected an aborted probe to let another one through")
+	}
+
+	b.success()
+	if !b.allow() || !b.allow() {
+		t.Fatal("expected a successful probe to close the breaker")
+	}
+}
@@ -2,40 +2,148 @@ package redis
 
 import (
 	"context"
-	"log"
-	"os"
+	"errors"
+	"fmt"
+	"intern-bcc/pkg/cache"
+	"intern-bcc/pkg/logging"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
 )
 
-var RDB *redis.Client
+const (
+	keySetTag           = "tag:%v"
+	keySetTagGeneration = "taggen:%v"
+	keyTagGeneration    = "taggen"
+	breakerThreshold    = 5
+	breakerCooldown     = 30 * time.Second
+	// tagGenerationTTL is how long the generation of an invalidated tag is
+	// kept. It only has to outlive the loads that started before the
+	// invalidation, which take seconds.
+	tagGenerationTTL = time.Hour
+)
+
+// incrScript increments a counter and sets its expiry in one step, so a counter
+// can never be left without one. A counter that has no expiry, from before this
+// script, gets one on its next increment.
+var incrScript = redis.NewScript(`
+local count = redis.call("INCR", KEYS[1])
+if redis.call("PTTL", KEYS[1]) < 0 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return count
+`)
+
+// setWithTagsScript stores a tagged entry unless one of its tags was
+// invalidated after the generation in ARGV[3] was read. KEYS holds the entry
+// followed by the set and generation key of every tag.
+var setWithTagsScript = redis.NewScript(`
+for i = 2, #KEYS, 2 do
+	if tonumber(redis.call("GET", KEYS[i + 1]) or "0") > tonumber(ARGV[3]) then
+		return 0
+	end
+end
+redis.call("SET", KEYS[1], ARGV[1], "PX", ARGV[2])
+for i = 2, #KEYS, 2 do
+	redis.call("SADD", KEYS[i], KEYS[1])
+	redis.call("PEXPIRE", KEYS[i], ARGV[2])
+end
+return 1
+`)
 
-func ConnectToRedis() {
-	opts, err := redis.ParseURL(os.Getenv("REDIS_URL"))
+// invalidateTagScript records the next generation on the tag before deleting
+// its entries, so a tagged write checked against an older generation is
+// refused from then on.
+var invalidateTagScript = redis.NewScript(`
+local generation = redis.call("INCR", KEYS[3])
+redis.call("SET", KEYS[2], generation, "PX", ARGV[1])
+local keys = redis.call("SMEMBERS", KEYS[1])
+for i = 1, #keys, 1000 do
+	redis.call("DEL", unpack(keys, i, math.min(i + 999, #keys)))
+end
+redis.call("DEL", KEYS[1])
+return generation
+`)
+
+func ConnectToRedis(url string) (*redis.Client, error) {
+	opts, err := redis.ParseURL(url)
 	if err != nil {
-		log.Fatal("Failed to Connect to Redis")
+		return nil, fmt.Errorf("failed to parse redis url: %w", err)
 	}
 
-	redisClient := redis.NewClient(opts)
-	RDB = redisClient
-}
+	opts.DialTimeout = 2 * time.Second
+	opts.ReadTimeout = time.Second
+	opts.WriteTimeout = time.Second
+	opts.MaxRetries = 1
 
-type IRedis interface {
-	SetRedis(ctx context.Context, key string, data string, ttl time.Duration) error
-	GetRedis(ctx context.Context, key string) (string, error)
+	return redis.NewClient(opts), nil
 }
 
+// Redis queues tag invalidations that fail during an outage in pendingTags and
+// replays them before the next read or tagged write. Each queued tag keeps the
+// sequence number it was last queued with, so a replay never drops a tag that
+// was queued again while it ran.
 type Redis struct {
-	r *redis.Client
+	r           *redis.Client
+	breaker     *breaker
+	mu          sync.Mutex
+	pending     atomic.Bool
+	sequence    uint64
+	pendingTags map[string]uint64
+}
+
+func RedisInit(r *redis.Client) cache.ICache {
+	return &Redis{
+		r:           r,
+		breaker:     newBreaker(breakerThreshold, breakerCooldown),
+		pendingTags: make(map[string]uint64),
+	}
+}
+
+func (r *Redis) do(ctx context.Context, fn func() error) error {
+	if !r.breaker.allow() {
+		return cache.ErrUnavailable
+	}
+
+	err := fn()
+	if err == nil || errors.Is(err, redis.Nil) {
+		if r.breaker.success() {
+			logging.FromContext(ctx).Info("redis connection recovered")
+		}
+		return err
+	}
+	if errors.Is(err, context.Canceled) {
+		r.breaker.abort()
+		return err
+	}
+
+	if r.breaker.failure() {
+		logging.FromContext(ctx).WithError(err).Warn("redis circuit breaker opened")
+	}
+
+	return fmt.Errorf("%w: %v", cache.ErrUnavailable, err)
+}
+
+func (r *Redis) Ping(ctx context.Context) error {
+	return r.do(ctx, func() error {
+		return r.r.Ping(ctx).Err()
+	})
+}
+
+func (r *Redis) Close() error {
+	return r.r.Close()
 }
 
-func RedisInit(r *redis.Client) IRedis {
-	return &Redis{r}
+func (r *Redis) State() BreakerState {
+	return r.breaker.state()
 }
 
-func (r *Redis) SetRedis(ctx context.Context, key string, data string, ttl time.Duration) error {
-	err := r.r.SetEx(ctx, key, data, ttl).Err()
+func (r *Redis) Set(ctx context.Context, key string, data string, ttl time.Duration) error {
+	err := r.do(ctx, func() error {
+		return r.r.SetEx(ctx, key, data, ttl).Err()
+	})
 	if err != nil {
 		return err
 	}
@@ -43,11 +151,178 @@ func (r *Redis) SetRedis(ctx context.Context, key string, data string, ttl time.
 	return nil
 }
 
-func (r *Redis) GetRedis(ctx context.Context, key string) (string, error) {
-	stringData, err := r.r.Get(ctx, key).Result()
+func (r *Redis) SetNX(ctx context.Context, key string, data string, ttl time.Duration) (bool, error) {
+	var ok bool
+	err := r.do(ctx, func() error {
+		var err error
+		ok, err = r.r.SetNX(ctx, key, data, ttl).Result()
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return ok, nil
+}
+
+func (r *Redis) Get(ctx context.Context, key string) (string, error) {
+	err := r.flushPendingTags(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var stringData string
+	err = r.do(ctx, func() error {
+		var err error
+		stringData, err = r.r.Get(ctx, key).Result()
+		return err
+	})
+	if errors.Is(err, redis.Nil) {
+		return "", cache.ErrMiss
+	}
 	if err != nil {
 		return "", err
 	}
 
 	return stringData, nil
 }
+
+func (r *Redis) Delete(ctx context.Context, key string) error {
+	err := r.do(ctx, func() error {
+		return r.r.Del(ctx, key).Err()
+	})
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *Redis) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	var count int64
+	err := r.do(ctx, func() error {
+		var err error
+		count, err = incrScript.Run(ctx, r.r, []string{key}, ttl.Milliseconds()).Int64()
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return count, nil
+}
+
+func (r *Redis) TagGeneration(ctx context.Context) (int64, error) {
+	err := r.flushPendingTags(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	var generation int64
+	err = r.do(ctx, func() error {
+		var err error
+		generation, err = r.r.Get(ctx, keyTagGeneration).Int64()
+		return err
+	})
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return generation, nil
+}
+
+func (r *Redis) SetWithTags(ctx context.Context, key string, data string, ttl time.Duration, generation int64, tags ...string) (bool, error) {
+	err := r.flushPendingTags(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	keys := []string{key}
+	for _, tag := range tags {
+		keys = append(keys, fmt.Sprintf(keySetTag, tag), fmt.Sprintf(keySetTagGeneration, tag))
+	}
+
+	var stored int64
+	err = r.do(ctx, func() error {
+		var err error
+		stored, err = setWithTagsScript.Run(ctx, r.r, keys, data, ttl.Milliseconds(), generation).Int64()
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return stored == 1, nil
+}
+
+func (r *Redis) InvalidateTags(ctx context.Context, tags ...string) error {
+	for i, tag := range tags {
+		err := r.do(ctx, func() error {
+			return r.invalidateTag(ctx, tag)
+		})
+		if errors.Is(err, cache.ErrUnavailable) {
+			r.queuePendingTags(tags[i:]...)
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (r *Redis) invalidateTag(ctx context.Context, tag string) error {
+	keys := []string{fmt.Sprintf(keySetTag, tag), fmt.Sprintf(keySetTagGeneration, tag), keyTagGeneration}
+	return invalidateTagScript.Run(ctx, r.r, keys, tagGenerationTTL.Milliseconds()).Err()
+}
+
+func (r *Redis) queuePendingTags(tags ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, tag := range tags {
+		r.sequence++
+		r.pendingTags[tag] = r.sequence
+	}
+	r.pending.Store(true)
+}
+
+// flushPendingTags checks an atomic flag first, so reads do not contend on the
+// mutex unless there is something to replay, and never holds the mutex while
+// talking to Redis.
+func (r *Redis) flushPendingTags(ctx context.Context) error {
+	if !r.pending.Load() {
+		return nil
+	}
+
+	r.mu.Lock()
+	pendingTags := make(map[string]uint64, len(r.pendingTags))
+	for tag, sequence := range r.pendingTags {
+		pendingTags[tag] = sequence
+	}
+	r.mu.Unlock()
+
+	for tag, sequence := range pendingTags {
+		err := r.do(ctx, func() error {
+			return r.invalidateTag(ctx, tag)
+		})
+		if err != nil {
+			return err
+		}
+
+		r.mu.Lock()
+		if r.pendingTags[tag] == sequence {
+			delete(r.pendingTags, tag)
+		}
+		if len(r.pendingTags) == 0 {
+			r.pending.Store(false)
+		}
+		r.mu.Unlock()
+	}
+
+	return nil
+}
@@ -0,0 +1,270 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"intern-bcc/pkg/cache"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func newTestRedis(t *testing.T) (*Redis, *miniredis.Miniredis) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	t.Cleanup(func() { client.Close() })
+
+	return RedisInit(client).(*Redis), mr
+}
+
+func assertMiss(t *testing.T, c cache.ICache, key string) {
+	t.Helper()
+
+	_, err := c.Get(context.Background(), key)
+	if !errors.Is(err, cache.ErrMiss) {
+		t.Fatalf("get %v: expected a miss, got %v", key, err)
+	}
+}
+
+func assertHit(t *testing.T, c cache.ICache, key string, want string) {
+	t.Helper()
+
+	got, err := c.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("get %v: %v", key, err)
+	}
+	if got != want {
+		t.Fatalf("get %v: expected %q, got %q", key, want, got)
+	}
+}
+
+func TestInvalidateTags(t *testing.T) {
+	ctx := context.Background()
+	r, mr := newTestRedis(t)
+
+	for key, tags := range map[string][]string{
+		"a": {"product"},
+		"b": {"product", "merchant:1"},
+		"c": {"merchant:1"},
+	} {
+		_, err := r.SetWithTags(ctx, key, key, time.Minute, 0, tags...)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err := r.Set(ctx, "d", "d", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.InvalidateTags(ctx, "product")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	assertMiss(t, r, "a")
+	assertMiss(t, r, "b")
+	assertHit(t, r, "c", "c")
+	assertHit(t, r, "d", "d")
+	if mr.Exists("tag:product") {
+		t.Fatal("expected the invalidated tag set to be deleted")
+	}
+}
+
+func TestPendingInvalidationReplayedAfterOutage(t *testing.T) {
+	ctx := context.Background()
+	r, mr := newTestRedis(t)
+	r.breaker = newBreaker(1, 10*time.Millisecond)
+
+	_, err := r.SetWithTags(ctx, "a", "a", time.Minute, 0, "product")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mr.SetError("connection lost")
+	err = r.InvalidateTags(ctx, "product")
+	if err != nil {
+		t.Fatalf("expected the invalidation to be queued, got %v", err)
+	}
+
+	_, err = r.Get(ctx, "a")
+	if !errors.Is(err, cache.ErrUnavailable) {
+		t.Fatalf("expected the cache to be unavailable during the outage, got %v", err)
+	}
+
+	mr.SetError("")
+	time.Sleep(20 * time.Millisecond)
+
+	if !mr.Exists("a") {
+		t.Fatal("expected the stale entry to survive until redis is reachable")
+	}
+	assertMiss(t, r, "a")
+	if mr.Exists("tag:product") {
+		t.Fatal("expected the replayed tag set to be deleted")
+	}
+	if len(r.pendingTags) != 0 {
+		t.Fatalf("expected no pending tags after replay, got %v", r.pendingTags)
+	}
+}
+
+func TestEntryWrittenWhileInvalidationIsPending(t *testing.T) {
+	ctx := context.Background()
+	r, mr := newTestRedis(t)
+
+	_, err := r.SetWithTags(ctx, "stale", "old", time.Minute, 0, "product")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mr.SetError("connection lost")
+	err = r.InvalidateTags(ctx, "product")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = r.SetWithTags(ctx, "during", "new", time.Minute, 0, "product")
+	if !errors.Is(err, cache.ErrUnavailable) {
+		t.Fatalf("expected the write to fail during the outage, got %v", err)
+	}
+
+	mr.SetError("")
+
+	generation, err := r.TagGeneration(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := r.SetWithTags(ctx, "after", "new", time.Minute, generation, "product")
+	if err != nil || !stored {
+		t.Fatalf("expected the write after the replay to be stored, got %v, %v", stored, err)
+	}
+
+	assertMiss(t, r, "stale")
+	assertMiss(t, r, "during")
+	assertHit(t, r, "after", "new")
+
+	err = r.InvalidateTags(ctx, "product")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMiss(t, r, "after")
+}
+
+func TestIncrAlwaysSetsExpiry(t *testing.T) {
+	ctx := context.Background()
+	r, mr := newTestRedis(t)
+
+	for want := int64(1); want <= 3; want++ {
+		count, err := r.Incr(ctx, "ratelimit:login", time.Minute)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if count != want {
+			t.Fatalf("expected count %v, got %v", want, count)
+		}
+	}
+
+	if ttl := mr.TTL("ratelimit:login"); ttl <= 0 || ttl > time.Minute {
+		t.Fatalf("expected the counter to expire within a minute, got %v", ttl)
+	}
+
+	// A counter left without an expiry gets one on its next increment.
+	err := mr.Set("otp:attempt", "4")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	count, err := r.Incr(ctx, "otp:attempt", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 5 || mr.TTL("otp:attempt") <= 0 {
+		t.Fatalf("expected the counter to be 5 with an expiry, got %v with %v", count, mr.TTL("otp:attempt"))
+	}
+
+	mr.FastForward(2 * time.Minute)
+	assertMiss(t, r, "ratelimit:login")
+}
+
+func TestSetNXStoresOnlyOnce(t *testing.T) {
+	ctx := context.Background()
+	r, mr := newTestRedis(t)
+
+	ok, err := r.SetNX(ctx, "2fa:used", "1", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected the first SetNX to store the key")
+	}
+
+	ok, err = r.SetNX(ctx, "2fa:used", "2", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ok {
+		t.Fatal("expected the second SetNX to find the key already set")
+	}
+	assertHit(t, r, "2fa:used", "1")
+
+	mr.FastForward(2 * time.Minute)
+	ok, err = r.SetNX(ctx, "2fa:used", "3", time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !ok {
+		t.Fatal("expected SetNX to store the key again once it expired")
+	}
+}
+
+func TestSetWithTagsSkipsEntriesLoadedBeforeInvalidation(t *testing.T) {
+	ctx := context.Background()
+	r, mr := newTestRedis(t)
+
+	generation, err := r.TagGeneration(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = r.InvalidateTags(ctx, "merchant:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := r.SetWithTags(ctx, "listing", "old", time.Minute, generation, "product", "merchant:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if stored {
+		t.Fatal("expected a page loaded before its tag was invalidated not to be stored")
+	}
+	assertMiss(t, r, "listing")
+	if mr.Exists("tag:product") {
+		t.Fatal("expected a refused write not to be added to its tags")
+	}
+
+	// Tags that were not invalidated since do not hold the write back.
+	stored, err = r.SetWithTags(ctx, "mentors", "page", time.Minute, generation, "mentor")
+	if err != nil || !stored {
+		t.Fatalf("expected the page to be stored, got %v, %v", stored, err)
+	}
+
+	generation, err = r.TagGeneration(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err = r.SetWithTags(ctx, "listing", "new", time.Minute, generation, "product", "merchant:1")
+	if err != nil || !stored {
+		t.Fatalf("expected a page loaded after the invalidation to be stored, got %v, %v", stored, err)
+	}
+	assertHit(t, r, "listing", "new")
+
+	err = r.InvalidateTags(ctx, "merchant:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertMiss(t, r, "listing")
+}
@@ -29,9 +29,10 @@ func Failed(c *gin.Context, err error) {
 	c.Set("error", err)
 	errorObject := err.(*ErrorObject)
 	c.JSON(errorObject.Code, gin.H{
-		"status":  "error",
-		"message": errorObject.Message,
-		"error":   errorObject.Err.Error(),
+		"status":     "error",
+		"message":    errorObject.Message,
+		"error":      errorObject.Err.Error(),
+		"request_id": c.GetString("request_id"),
 	})
 }
 
@@ -0,0 +1,346 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalRoute is where the API serves files of the local storage.
+const LocalRoute = "/storage"
+
+// PendingDir holds direct uploads until they are confirmed. Their content has
+// not been checked yet, so Local never serves them.
+const PendingDir = "uploads"
+
+type Local struct {
+	dir     string
+	baseUrl string
+	secret  []byte
+}
+
+// LocalInit signs upload URLs with a key generated on start, so URLs handed
+// out before a restart stop working.
+func LocalInit(dir string, baseUrl string) (*Local, error) {
+	err := os.MkdirAll(dir, 0o755)
+	if err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	_, err = rand.Read(secret)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Local{dir, strings.TrimSuffix(baseUrl, "/"), secret}, nil
+}
+
+// Upload writes to a temporary file first, so a failed write never leaves a
+// partial object under its final key.
+func (l *Local) Upload(ctx context.Context, key string, contentType string, data []byte) (string, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.MkdirAll(filepath.Dir(path), 0o755)
+	if err != nil {
+		return "", err
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(file.Name())
+
+	_, err = file.Write(data)
+	if err != nil {
+		file.Close()
+		return "", err
+	}
+
+	err = file.Close()
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Chmod(file.Name(), 0o644)
+	if err != nil {
+		return "", err
+	}
+
+	err = os.Rename(file.Name(), path)
+	if err != nil {
+		return "", err
+	}
+
+	return l.Link(key), nil
+}
+
+func (l *Local) Link(key string) string {
+	return l.baseUrl + "/" + key
+}
+
+// PresignUpload returns a URL served by ServeHTTP.
+func (l *Local) PresignUpload(ctx context.Context, key string, contentType string, size int64, expires time.Duration) (PresignedUpload, error) {
+	_, err := l.path(key)
+	if err != nil {
+		return PresignedUpload{}, err
+	}
+
+	query := url.Values{}
+	query.Set("content_type", contentType)
+	query.Set("size", strconv.FormatInt(size, 10))
+	query.Set("expires", strconv.FormatInt(time.Now().Add(expires).Unix(), 10))
+	query.Set("signature", l.signature(key, query))
+
+	return PresignedUpload{
+		Url:     l.Link(key) + "?" + query.Encode(),
+		Method:  http.MethodPut,
+		Headers: map[string]string{"Content-Type": contentType},
+		Link:    l.Link(key),
+	}, nil
+}
+
+// ServeHTTP serves stored files on GET and HEAD and stores the body of a PUT to
+// a URL from PresignUpload. The request path is the object key.
+func (l *Local) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	switch r.Method {
+	case http.MethodGet, http.MethodHead:
+		l.serveFile(w, r, key)
+	case http.MethodPut:
+		l.storeUpload(w, r, key)
+	default:
+		w.Header().Set("Allow", "GET, HEAD, PUT")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// serveFile takes the content type from the key's extension and forbids
+// sniffing, so a file is never rendered as something other than it claims.
+func (l *Local) serveFile(w http.ResponseWriter, r *http.Request, key string) {
+	if key == PendingDir || strings.HasPrefix(key, PendingDir+"/") {
+		http.NotFound(w, r)
+		return
+	}
+
+	path, err := l.path(key)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+
+	contentType := mime.TypeByExtension(filepath.Ext(key))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	http.ServeContent(w, r, "", info.ModTime(), file)
+}
+
+func (l *Local) storeUpload(w http.ResponseWriter, r *http.Request, key string) {
+	query := r.URL.Query()
+
+	signature, err := hex.DecodeString(query.Get("signature"))
+	if err != nil || !hmac.Equal(signature, l.mac(key, query)) {
+		http.Error(w, "invalid signature", http.StatusForbidden)
+		return
+	}
+
+	expires, err := strconv.ParseInt(query.Get("expires"), 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		http.Error(w, "upload url has expired", http.StatusForbidden)
+		return
+	}
+
+	if r.Header.Get("Content-Type") != query.Get("content_type") {
+		http.Error(w, "content type does not match the signed one", http.StatusBadRequest)
+		return
+	}
+
+	size, err := strconv.ParseInt(query.Get("size"), 10, 64)
+	if err != nil {
+		http.Error(w, "invalid size", http.StatusBadRequest)
+		return
+	}
+
+	data, err := readAll(r.Body, size)
+	if errors.Is(err, ErrTooLarge) {
+		http.Error(w, "body is larger than the signed size", http.StatusRequestEntityTooLarge)
+		return
+	}
+	if err != nil {
+		http.Error(w, "failed to read body", http.StatusBadRequest)
+		return
+	}
+	if int64(len(data)) != size {
+		http.Error(w, "body is smaller than the signed size", http.StatusBadRequest)
+		return
+	}
+
+	_, err = l.Upload(r.Context(), key, query.Get("content_type"), data)
+	if err != nil {
+		http.Error(w, "failed to store upload", http.StatusInternalServerError)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (l *Local) Size(ctx context.Context, key string) (int64, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return 0, err
+	}
+
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, fmt.Errorf("%w: %v", ErrNotFound, key)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	return info.Size(), nil
+}
+
+func (l *Local) Download(ctx context.Context, key string, limit int64) ([]byte, error) {
+	path, err := l.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, key)
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return readAll(file, limit)
+}
+
+// List returns the files directly inside dir. A missing dir has no files.
+func (l *Local) List(ctx context.Context, dir string) ([]Object, error) {
+	path, err := l.path(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	entries, err := os.ReadDir(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var objects []Object
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		info, err := entry.Info()
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		key := dir + "/" + entry.Name()
+		objects = append(objects, Object{
+			Key:        key,
+			Link:       l.Link(key),
+			ModifiedAt: info.ModTime(),
+		})
+	}
+
+	return objects, nil
+}
+
+func (l *Local) Delete(ctx context.Context, link string) error {
+	key, err := keyOf(l.baseUrl, link)
+	if err != nil {
+		return err
+	}
+
+	path, err := l.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+
+	return nil
+}
+
+func (l *Local) Ping(ctx context.Context) error {
+	info, err := os.Stat(l.dir)
+	if err != nil {
+		return err
+	}
+
+	if !info.IsDir() {
+		return fmt.Errorf("%v is not a directory", l.dir)
+	}
+
+	return nil
+}
+
+func (l *Local) signature(key string, query url.Values) string {
+	return hex.EncodeToString(l.mac(key, query))
+}
+
+func (l *Local) mac(key string, query url.Values) []byte {
+	mac := hmac.New(sha256.New, l.secret)
+	for _, part := range []string{key, query.Get("content_type"), query.Get("size"), query.Get("expires")} {
+		mac.Write([]byte(part + "\n"))
+	}
+
+	return mac.Sum(nil)
+}
+
+func (l *Local) path(key string) (string, error) {
+	if !filepath.IsLocal(key) {
+		return "", fmt.Errorf("invalid storage key %q", key)
+	}
+
+	return filepath.Join(l.dir, filepath.FromSlash(key)), nil
+}
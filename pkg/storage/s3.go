@@ -0,0 +1,394 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"intern-bcc/pkg/config"
+	"intern-bcc/pkg/tracing"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+const (
+	amzDateFormat    = "20060102T150405Z"
+	emptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+)
+
+// S3 talks to any S3-compatible object storage, such as AWS S3, MinIO or
+// Cloudflare R2, and signs its requests with AWS Signature Version 4.
+type S3 struct {
+	client    *http.Client
+	endpoint  *url.URL
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+	pathStyle bool
+	baseUrl   string
+}
+
+func S3Init(cfg config.S3Config, baseUrl string) (IStorage, error) {
+	endpoint, err := url.Parse(strings.TrimSuffix(cfg.Endpoint, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, fmt.Errorf("invalid s3 endpoint %q", cfg.Endpoint)
+	}
+
+	s := &S3{
+		client:    http.DefaultClient,
+		endpoint:  endpoint,
+		region:    cfg.Region,
+		bucket:    cfg.Bucket,
+		accessKey: cfg.AccessKey,
+		secretKey: cfg.SecretKey,
+		pathStyle: cfg.PathStyle,
+		baseUrl:   strings.TrimSuffix(baseUrl, "/"),
+	}
+	if s.baseUrl == "" {
+		s.baseUrl = strings.TrimSuffix(s.url("").String(), "/")
+	}
+
+	return s, nil
+}
+
+func (s *S3) Upload(ctx context.Context, key string, contentType string, data []byte) (link string, err error) {
+	ctx, span := tracing.StartClient(ctx, "s3.upload", attribute.Int("file.size", len(data)))
+	defer func() { tracing.End(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.url(key).String(), bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Content-Type", contentType)
+
+	sum := sha256.Sum256(data)
+	s.sign(req, hex.EncodeToString(sum[:]), time.Now())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	err = checkResponse(res, "s3")
+	if err != nil {
+		return "", err
+	}
+
+	return s.Link(key), nil
+}
+
+func (s *S3) Link(key string) string {
+	return s.baseUrl + "/" + key
+}
+
+// PresignUpload signs the content type and length into the URL, so S3 rejects
+// an upload of any other type or size.
+func (s *S3) PresignUpload(ctx context.Context, key string, contentType string, size int64, expires time.Duration) (PresignedUpload, error) {
+	u := s.url(key)
+	amzDate := time.Now().UTC().Format(amzDateFormat)
+	headers := map[string]string{
+		"content-length": strconv.FormatInt(size, 10),
+		"content-type":   contentType,
+		"host":           u.Host,
+	}
+
+	query := url.Values{}
+	query.Set("X-Amz-Algorithm", "AWS4-HMAC-SHA256")
+	query.Set("X-Amz-Credential", s.accessKey+"/"+s.scope(amzDate))
+	query.Set("X-Amz-Date", amzDate)
+	query.Set("X-Amz-Expires", strconv.Itoa(int(expires.Seconds())))
+	query.Set("X-Amz-SignedHeaders", "content-length;content-type;host")
+	u.RawQuery = canonicalQuery(query)
+
+	_, signature := s.signature(http.MethodPut, u, headers, "UNSIGNED-PAYLOAD", amzDate)
+	u.RawQuery += "&X-Amz-Signature=" + signature
+
+	return PresignedUpload{
+		Url:     u.String(),
+		Method:  http.MethodPut,
+		Headers: map[string]string{"Content-Type": contentType},
+		Link:    s.Link(key),
+	}, nil
+}
+
+func (s *S3) Size(ctx context.Context, key string) (size int64, err error) {
+	ctx, span := tracing.StartClient(ctx, "s3.size")
+	defer func() { tracing.End(span, err) }()
+
+	res, err := s.do(ctx, http.MethodHead, key)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	return res.ContentLength, nil
+}
+
+func (s *S3) Download(ctx context.Context, key string, limit int64) (data []byte, err error) {
+	ctx, span := tracing.StartClient(ctx, "s3.download")
+	defer func() { tracing.End(span, err) }()
+
+	res, err := s.do(ctx, http.MethodGet, key)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	return readAll(res.Body, limit)
+}
+
+// List pages through ListObjectsV2 for the objects directly inside dir.
+func (s *S3) List(ctx context.Context, dir string) (objects []Object, err error) {
+	ctx, span := tracing.StartClient(ctx, "s3.list")
+	defer func() { tracing.End(span, err) }()
+
+	var continuationToken string
+	for {
+		query := url.Values{}
+		query.Set("list-type", "2")
+		query.Set("prefix", dir+"/")
+		query.Set("delimiter", "/")
+		if continuationToken != "" {
+			query.Set("continuation-token", continuationToken)
+		}
+
+		u := s.url("")
+		u.RawQuery = canonicalQuery(query)
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+		if err != nil {
+			return nil, err
+		}
+		s.sign(req, emptyPayloadHash, time.Now())
+
+		res, err := s.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var page struct {
+			Contents []struct {
+				Key          string
+				LastModified time.Time
+			}
+			IsTruncated           bool
+			NextContinuationToken string
+		}
+		err = checkResponse(res, "s3")
+		if err == nil {
+			err = xml.NewDecoder(res.Body).Decode(&page)
+		}
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, content := range page.Contents {
+			objects = append(objects, Object{
+				Key:        content.Key,
+				Link:       s.Link(content.Key),
+				ModifiedAt: content.LastModified,
+			})
+		}
+
+		if !page.IsTruncated {
+			return objects, nil
+		}
+		continuationToken = page.NextContinuationToken
+	}
+}
+
+func (s *S3) Delete(ctx context.Context, link string) (err error) {
+	ctx, span := tracing.StartClient(ctx, "s3.delete")
+	defer func() { tracing.End(span, err) }()
+
+	key, err := keyOf(s.baseUrl, link)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.url(key).String(), nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, emptyPayloadHash, time.Now())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	return checkResponse(res, "s3")
+}
+
+func (s *S3) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodHead, s.url("").String(), nil)
+	if err != nil {
+		return err
+	}
+	s.sign(req, emptyPayloadHash, time.Now())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	return checkResponse(res, "s3")
+}
+
+// do sends a bodiless request for an object and fails when it is missing.
+func (s *S3) do(ctx context.Context, method string, key string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, s.url(key).String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	s.sign(req, emptyPayloadHash, time.Now())
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		res.Body.Close()
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, key)
+	}
+
+	err = checkResponse(res, "s3")
+	if err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// url returns the address of an object, or of the bucket when key is empty.
+func (s *S3) url(key string) *url.URL {
+	u := *s.endpoint
+	path := "/" + key
+	if s.pathStyle {
+		path = "/" + s.bucket + path
+	} else {
+		u.Host = s.bucket + "." + u.Host
+	}
+
+	u.Path = u.Path + path
+	u.RawPath = uriEncode(u.Path, false)
+
+	return &u
+}
+
+func (s *S3) sign(req *http.Request, payloadHash string, now time.Time) {
+	amzDate := now.UTC().Format(amzDateFormat)
+	req.Header.Set("x-amz-date", amzDate)
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(req.Header.Get(name))
+	}
+
+	signedHeaders, signature := s.signature(req.Method, req.URL, headers, payloadHash, amzDate)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%v/%v, SignedHeaders=%v, Signature=%v", s.accessKey, s.scope(amzDate), signedHeaders, signature))
+}
+
+func (s *S3) signature(method string, u *url.URL, headers map[string]string, payloadHash string, amzDate string) (string, string) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		method,
+		u.EscapedPath(),
+		canonicalQuery(u.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, s.scope(amzDate), hex.EncodeToString(requestHash[:])}, "\n")
+
+	key := hmacSha256([]byte("AWS4"+s.secretKey), amzDate[:8])
+	for _, part := range []string{s.region, "s3", "aws4_request"} {
+		key = hmacSha256(key, part)
+	}
+
+	return signedHeaders, hex.EncodeToString(hmacSha256(key, stringToSign))
+}
+
+func (s *S3) scope(amzDate string) string {
+	return fmt.Sprintf("%v/%v/s3/aws4_request", amzDate[:8], s.region)
+}
+
+func canonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			pairs = append(pairs, uriEncode(key, true)+"="+uriEncode(value, true))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// uriEncode escapes everything except unreserved characters, which is the
+// encoding AWS signatures are computed over.
+func uriEncode(s string, encodeSlash bool) string {
+	var encoded strings.Builder
+	for _, b := range []byte(s) {
+		switch {
+		case 'A' <= b && b <= 'Z', 'a' <= b && b <= 'z', '0' <= b && b <= '9', b == '-', b == '_', b == '.', b == '~':
+			encoded.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			encoded.WriteByte(b)
+		default:
+			fmt.Fprintf(&encoded, "%%%02X", b)
+		}
+	}
+
+	return encoded.String()
+}
+
+func hmacSha256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotManaged = errors.New("link does not belong to this storage")
+	ErrNotFound   = errors.New("object not found")
+	ErrTooLarge   = errors.New("object is too large")
+)
+
+type IStorage interface {
+	Upload(ctx context.Context, key string, contentType string, data []byte) (string, error)
+	// Link returns where the object stored under key is served, whether or
+	// not it was uploaded yet.
+	Link(key string) string
+	PresignUpload(ctx context.Context, key string, contentType string, size int64, expires time.Duration) (PresignedUpload, error)
+	Size(ctx context.Context, key string) (int64, error)
+	Download(ctx context.Context, key string, limit int64) ([]byte, error)
+	List(ctx context.Context, dir string) ([]Object, error)
+	Delete(ctx context.Context, link string) error
+	Ping(ctx context.Context) error
+}
+
+// Object is a stored file as returned by List. Link is what Delete accepts.
+type Object struct {
+	Key        string
+	Link       string
+	ModifiedAt time.Time
+}
+
+// PresignedUpload lets a client upload an object straight to the storage by
+// sending its content with Method and Headers to Url. Link is where the object
+// is served once uploaded.
+type PresignedUpload struct {
+	Url     string
+	Method  string
+	Headers map[string]string
+	Link    string
+}
+
+// Key addresses an object by its content, so uploading the same file twice
+// stores it once and retrying a failed upload is harmless.
+func Key(data []byte, extension string) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]) + extension
+}
+
+// keyOf returns the key of a link served under baseUrl.
+func keyOf(baseUrl string, link string) (string, error) {
+	key, ok := strings.CutPrefix(link, strings.TrimSuffix(baseUrl, "/")+"/")
+	if !ok || key == "" {
+		return "", fmt.Errorf("%w: %v", ErrNotManaged, link)
+	}
+
+	return key, nil
+}
+
+// readAll reads at most limit bytes of an object.
+func readAll(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("%w: more than %d bytes", ErrTooLarge, limit)
+	}
+
+	return data, nil
+}
+
+func checkResponse(res *http.Response, service string) error {
+	if res.StatusCode >= http.StatusOK && res.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1<<10))
+	return fmt.Errorf("%v responded with status %v: %s", service, res.StatusCode, body)
+}
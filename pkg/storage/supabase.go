@@ -0,0 +1,273 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"intern-bcc/pkg/config"
+	"intern-bcc/pkg/tracing"
+	"net/http"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type Supabase struct {
+	client *http.Client
+	url    string
+	token  string
+	bucket string
+}
+
+func SupabaseInit(cfg config.SupabaseConfig) IStorage {
+	return &Supabase{
+		client: http.DefaultClient,
+		url:    strings.TrimSuffix(cfg.Url, "/"),
+		token:  cfg.Token,
+		bucket: cfg.Bucket,
+	}
+}
+
+func (s *Supabase) Upload(ctx context.Context, key string, contentType string, data []byte) (link string, err error) {
+	ctx, span := tracing.StartClient(ctx, "supabase.upload", attribute.Int("file.size", len(data)))
+	defer func() { tracing.End(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.objectUrl(key), bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("x-upsert", "true")
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	err = checkResponse(res, "supabase")
+	if err != nil {
+		return "", err
+	}
+
+	return s.Link(key), nil
+}
+
+func (s *Supabase) Link(key string) string {
+	return s.publicUrl() + "/" + key
+}
+
+// PresignUpload creates a signed upload URL. Supabase fixes how long it stays
+// valid and does not bind it to a size, so the size is checked on confirm.
+func (s *Supabase) PresignUpload(ctx context.Context, key string, contentType string, size int64, expires time.Duration) (presigned PresignedUpload, err error) {
+	ctx, span := tracing.StartClient(ctx, "supabase.presign")
+	defer func() { tracing.End(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%v/storage/v1/object/upload/sign/%v/%v", s.url, s.bucket, key), nil)
+	if err != nil {
+		return PresignedUpload{}, err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return PresignedUpload{}, err
+	}
+	defer res.Body.Close()
+
+	err = checkResponse(res, "supabase")
+	if err != nil {
+		return PresignedUpload{}, err
+	}
+
+	var signed struct {
+		Url string `json:"url"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&signed)
+	if err != nil {
+		return PresignedUpload{}, err
+	}
+
+	return PresignedUpload{
+		Url:     s.url + "/storage/v1" + signed.Url,
+		Method:  http.MethodPut,
+		Headers: map[string]string{"Content-Type": contentType},
+		Link:    s.Link(key),
+	}, nil
+}
+
+func (s *Supabase) Size(ctx context.Context, key string) (size int64, err error) {
+	ctx, span := tracing.StartClient(ctx, "supabase.size")
+	defer func() { tracing.End(span, err) }()
+
+	res, err := s.do(ctx, http.MethodHead, key)
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+
+	return res.ContentLength, nil
+}
+
+func (s *Supabase) Download(ctx context.Context, key string, limit int64) (data []byte, err error) {
+	ctx, span := tracing.StartClient(ctx, "supabase.download")
+	defer func() { tracing.End(span, err) }()
+
+	res, err := s.do(ctx, http.MethodGet, key)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	return readAll(res.Body, limit)
+}
+
+const supabaseListLimit = 1000
+
+// List pages through the objects directly inside dir. Supabase lists nested
+// folders as entries without an id, which are skipped.
+func (s *Supabase) List(ctx context.Context, dir string) (objects []Object, err error) {
+	ctx, span := tracing.StartClient(ctx, "supabase.list")
+	defer func() { tracing.End(span, err) }()
+
+	for offset := 0; ; offset += supabaseListLimit {
+		body, err := json.Marshal(map[string]interface{}{
+			"prefix": dir,
+			"limit":  supabaseListLimit,
+			"offset": offset,
+			"sortBy": map[string]string{"column": "name", "order": "asc"},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, fmt.Sprintf("%v/storage/v1/object/list/%v", s.url, s.bucket), bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Authorization", "Bearer "+s.token)
+		req.Header.Set("Content-Type", "application/json")
+
+		res, err := s.client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+
+		var entries []struct {
+			Id        *string   `json:"id"`
+			Name      string    `json:"name"`
+			UpdatedAt time.Time `json:"updated_at"`
+		}
+		err = checkResponse(res, "supabase")
+		if err == nil {
+			err = json.NewDecoder(res.Body).Decode(&entries)
+		}
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			if entry.Id == nil {
+				continue
+			}
+
+			key := dir + "/" + entry.Name
+			objects = append(objects, Object{
+				Key:        key,
+				Link:       s.Link(key),
+				ModifiedAt: entry.UpdatedAt,
+			})
+		}
+
+		if len(entries) < supabaseListLimit {
+			return objects, nil
+		}
+	}
+}
+
+func (s *Supabase) Delete(ctx context.Context, link string) (err error) {
+	ctx, span := tracing.StartClient(ctx, "supabase.delete")
+	defer func() { tracing.End(span, err) }()
+
+	key, err := keyOf(s.publicUrl(), link)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, s.objectUrl(key), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil
+	}
+
+	return checkResponse(res, "supabase")
+}
+
+func (s *Supabase) Ping(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("supabase responded with status %v", res.StatusCode)
+	}
+
+	return nil
+}
+
+// do sends an authenticated bodiless request for an object and fails when it
+// is missing. Older Supabase versions answer 400 instead of 404 for those.
+func (s *Supabase) do(ctx context.Context, method string, key string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, fmt.Sprintf("%v/storage/v1/object/authenticated/%v/%v", s.url, s.bucket, key), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+s.token)
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	if res.StatusCode == http.StatusNotFound || res.StatusCode == http.StatusBadRequest {
+		res.Body.Close()
+		return nil, fmt.Errorf("%w: %v", ErrNotFound, key)
+	}
+
+	err = checkResponse(res, "supabase")
+	if err != nil {
+		res.Body.Close()
+		return nil, err
+	}
+
+	return res, nil
+}
+
+func (s *Supabase) objectUrl(key string) string {
+	return fmt.Sprintf("%v/storage/v1/object/%v/%v", s.url, s.bucket, key)
+}
+
+func (s *Supabase) publicUrl() string {
+	return fmt.Sprintf("%v/storage/v1/object/public/%v", s.url, s.bucket)
+}
@@ -0,0 +1,53 @@
+package totp
+
+import (
+	"intern-bcc/pkg/config"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+)
+
+type ITotp interface {
+	GenerateSecret(accountName string) (string, string, error)
+	Validate(code string, secret string) bool
+}
+
+type Totp struct {
+	issuer string
+}
+
+func TotpInit(cfg config.TotpConfig) ITotp {
+	return &Totp{
+		issuer: cfg.Issuer,
+	}
+}
+
+func (t *Totp) GenerateSecret(accountName string) (string, string, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      t.issuer,
+		AccountName: accountName,
+		Period:      30,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	return key.Secret(), key.URL(), nil
+}
+
+func (t *Totp) Validate(code string, secret string) bool {
+	valid, err := totp.ValidateCustom(code, secret, time.Now().UTC(), totp.ValidateOpts{
+		Period:    30,
+		Skew:      1,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	})
+	if err != nil {
+		return false
+	}
+
+	return valid
+}
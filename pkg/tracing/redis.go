@@ -0,0 +1,57 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"net"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+type redisHook struct{}
+
+func RedisHook() redis.Hook {
+	return &redisHook{}
+}
+
+func (h *redisHook) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, span := StartClient(ctx, "redis.dial", attribute.String("db.system", "redis"))
+		conn, err := next(ctx, network, addr)
+		End(span, err)
+
+		return conn, err
+	}
+}
+
+func (h *redisHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := StartClient(ctx, "redis."+cmd.Name(), attribute.String("db.system", "redis"))
+		err := next(ctx, cmd)
+		End(span, ignoreNil(err))
+
+		return err
+	}
+}
+
+func (h *redisHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := StartClient(ctx, "redis.pipeline",
+			attribute.String("db.system", "redis"),
+			attribute.Int("db.redis.num_cmd", len(cmds)),
+		)
+		err := next(ctx, cmds)
+		End(span, ignoreNil(err))
+
+		return err
+	}
+}
+
+func ignoreNil(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+
+	return err
+}